@@ -0,0 +1,183 @@
+package technical
+
+import (
+	"sort"
+	"time"
+)
+
+/*
+* An anchored VWAP starts the volume weighted average at an event rather than at a fixed lookback:
+* a session open, an opening gap, an earnings print.
+*
+* Anchors at or before the last bar re-anchor the VWAP immediately, recomputing it from the bars kept since
+* the current anchor. Anchors after the last bar are pending until the first bar that starts at or after them.
+* The bands are a multiple of the volume weighted standard deviation of the prices from the VWAP,
+* so like a Bollinger Band the deviation of the price can be traded with the Bound64 helpers.
+ */
+
+// vwapPoint is a bar kept since the anchor of an AnchoredVWAPStream64
+type vwapPoint struct {
+	t      time.Time
+	price  float64
+	volume float64
+}
+
+// AnchoredVWAP64 computes the VWAP and its bands of each bar anchored to the latest of anchors at or before its start,
+// see AnchoredVWAPStream64. Bars before the first anchor are anchored to the first bar. Returns nil if a < 0.
+func AnchoredVWAP64(bars []Bar, anchors []time.Time, a float64) []Bound64 {
+	s, err := NewAnchoredVWAPStream64(a)
+	if err != nil {
+		return nil
+	}
+
+	for _, t := range anchors {
+		s.Anchor(t)
+	}
+
+	bounds := make([]Bound64, len(bars))
+	for i, b := range bars {
+		s.UpdateBar(b)
+		if s.Ready() {
+			bounds[i] = s.Bound()
+		}
+	}
+
+	return bounds
+}
+
+// AnchoredVWAPStream64 computes a VWAP over a stream of bars or ticks from an anchor time that can be moved at any time
+// Until the first anchor the VWAP is anchored to the first bar. The price of a bar is its typical price and
+// of a tick its price. The bars since the current anchor are kept to re-anchor at a past time.
+type AnchoredVWAPStream64 struct {
+	vwap    VWAPStream64
+	points  []vwapPoint // bars since the anchor
+	pending []time.Time // anchors after the last bar in ascending order
+}
+
+// NewAnchoredVWAPStream64 creates an AnchoredVWAPStream64 with bands a standard deviations from the VWAP
+// Returns ErrInvalidMultiplier if a < 0
+func NewAnchoredVWAPStream64(a float64) (*AnchoredVWAPStream64, error) {
+	vwap, err := NewVWAPStream64(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnchoredVWAPStream64{vwap: *vwap}, nil
+}
+
+// Anchor moves the anchor of the VWAP to the first bar that starts at or after t
+// If t is after the last bar the anchor is pending until a bar at or after t, else the VWAP is recomputed
+// from the first bar at or after t. Returns ErrInvalidAnchor if t is before the current anchor,
+// whose prior bars are no longer kept.
+func (s *AnchoredVWAPStream64) Anchor(t time.Time) error {
+	n := len(s.points)
+	if n == 0 || t.After(s.points[n-1].t) {
+		i := sort.Search(len(s.pending), func(i int) bool { return s.pending[i].After(t) })
+		s.pending = append(s.pending, time.Time{})
+		copy(s.pending[i+1:], s.pending[i:])
+		s.pending[i] = t
+
+		return nil
+	}
+
+	if t.Before(s.points[0].t) {
+		return ErrInvalidAnchor
+	}
+
+	i := sort.Search(n, func(i int) bool { return !s.points[i].t.Before(t) })
+	s.points = append([]vwapPoint(nil), s.points[i:]...)
+
+	s.vwap.Reset()
+	for _, p := range s.points {
+		s.vwap.update(p.price, p.volume)
+	}
+
+	return nil
+}
+
+// UpdateBar adds the next bar of the stream, placed in time by its start
+func (s *AnchoredVWAPStream64) UpdateBar(b Bar) {
+	s.update(vwapPoint{t: b.Start, price: b.TypicalPrice(), volume: b.Volume})
+}
+
+// UpdateTick adds the next tick of the stream
+func (s *AnchoredVWAPStream64) UpdateTick(t Tick) {
+	s.update(vwapPoint{t: t.Time, price: t.Price, volume: t.Size})
+}
+
+func (s *AnchoredVWAPStream64) update(p vwapPoint) {
+	// anchor to the bar if it is at or after pending anchors
+	i := 0
+	for i < len(s.pending) && !s.pending[i].After(p.t) {
+		i++
+	}
+
+	if i > 0 {
+		s.pending = s.pending[i:]
+		s.points = s.points[:0]
+		s.vwap.Reset()
+	}
+
+	s.points = append(s.points, p)
+	s.vwap.update(p.price, p.volume)
+}
+
+// AnchorTime returns the time of the first bar since the anchor, false before the first bar
+func (s *AnchoredVWAPStream64) AnchorTime() (time.Time, bool) {
+	if len(s.points) == 0 {
+		return time.Time{}, false
+	}
+
+	return s.points[0].t, true
+}
+
+// Bound returns the VWAP as the Midpoint and the bands as the Upper and Lower bounds
+func (s *AnchoredVWAPStream64) Bound() Bound64 {
+	return s.vwap.Bound()
+}
+
+// Bands returns the VWAP with bands a standard deviations away, e.g. for bands at 1, 2 and 3 standard deviations
+func (s *AnchoredVWAPStream64) Bands(a float64) Bound64 {
+	return vwapBound(s.vwap.Value(), s.vwap.variance(), a)
+}
+
+// Value returns the current VWAP
+func (s *AnchoredVWAPStream64) Value() float64 {
+	return s.vwap.Value()
+}
+
+// StdDev returns the volume weighted standard deviation of the prices from the VWAP
+func (s *AnchoredVWAPStream64) StdDev() float64 {
+	return s.vwap.StdDev()
+}
+
+// Volume returns the volume since the anchor
+func (s *AnchoredVWAPStream64) Volume() float64 {
+	return s.vwap.Volume()
+}
+
+// Ready reports whether a bar with volume has been seen since the anchor
+func (s *AnchoredVWAPStream64) Ready() bool {
+	return s.vwap.Ready()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *AnchoredVWAPStream64) WarmupPeriod() int {
+	return 1
+}
+
+// Reset clears the stream and its pending anchors
+func (s *AnchoredVWAPStream64) Reset() {
+	s.vwap.Reset()
+	s.points = nil
+	s.pending = nil
+}
+
+// Clone returns an independent copy of the stream
+func (s *AnchoredVWAPStream64) Clone() *AnchoredVWAPStream64 {
+	c := *s
+	c.points = append([]vwapPoint(nil), s.points...)
+	c.pending = append([]time.Time(nil), s.pending...)
+
+	return &c
+}
@@ -0,0 +1,107 @@
+package technical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnchoredVWAP(t *testing.T) {
+	bars := mockVolumeBars()
+
+	assert.Nil(t, AnchoredVWAP64(bars, nil, -1))
+
+	// without anchors the VWAP is anchored to the first bar
+	assert.Equal(t, VWAP64(bars, nil, 2), AnchoredVWAP64(bars, nil, 2))
+
+	// anchors in any order, the second between bars
+	anchors := []time.Time{bars[300].Start.Add(-30 * time.Second), bars[100].Start}
+	bounds := AnchoredVWAP64(bars, anchors, 2)
+	for _, i := range []int{50, 99} {
+		assertBoundInDelta(t, vwapOf(bars[:i+1], 2), bounds[i], 1e-9)
+	}
+
+	for _, i := range []int{100, 150, 299} {
+		assertBoundInDelta(t, vwapOf(bars[100:i+1], 2), bounds[i], 1e-9)
+	}
+
+	for _, i := range []int{300, len(bars) - 1} {
+		assertBoundInDelta(t, vwapOf(bars[300:i+1], 2), bounds[i], 1e-9)
+	}
+}
+
+func TestAnchoredVWAPReanchor(t *testing.T) {
+	bars := mockVolumeBars()
+
+	s, _ := NewAnchoredVWAPStream64(2)
+	_, ok := s.AnchorTime()
+	assert.False(t, ok)
+
+	for _, b := range bars[:200] {
+		s.UpdateBar(b)
+	}
+
+	// re-anchoring at a past time recomputes from the bars since
+	assert.Nil(t, s.Anchor(bars[150].Start))
+	anchor, _ := s.AnchorTime()
+	assert.Equal(t, bars[150].Start, anchor)
+	assertBoundInDelta(t, vwapOf(bars[150:200], 2), s.Bound(), 1e-9)
+
+	assert.Nil(t, s.Anchor(bars[160].Start.Add(time.Second)))
+	anchor, _ = s.AnchorTime()
+	assert.Equal(t, bars[161].Start, anchor)
+	assertBoundInDelta(t, vwapOf(bars[161:200], 2), s.Bound(), 1e-9)
+
+	// the bars before the anchor are no longer kept
+	assert.Equal(t, ErrInvalidAnchor, s.Anchor(bars[150].Start))
+
+	// at the last bar
+	assert.Nil(t, s.Anchor(bars[199].Start))
+	assertBoundInDelta(t, vwapOf(bars[199:200], 2), s.Bound(), 1e-9)
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, Bound64{}, s.Bound())
+	assert.True(t, c.Ready())
+	assert.Equal(t, 1, c.WarmupPeriod())
+}
+
+func TestAnchoredVWAPBands(t *testing.T) {
+	s, _ := NewAnchoredVWAPStream64(1)
+	for _, b := range mockVolumeBars()[:60] {
+		s.UpdateBar(b)
+	}
+
+	one, two := s.Bands(1), s.Bands(2)
+	assert.Equal(t, s.Bound(), one)
+	assert.Equal(t, s.Value(), two.Midpoint)
+	assert.InDelta(t, 2*s.StdDev(), two.Upper-two.Midpoint, 1e-12)
+	assert.True(t, CompareBound64(&two, &one))
+
+	// rounded to cents the bands contain the unrounded bands
+	rounded := two
+	RoundBoundToNearestCent64(&rounded)
+	assert.True(t, rounded.Upper >= two.Upper)
+	assert.True(t, rounded.Lower <= two.Lower)
+}
+
+func TestAnchoredVWAPTicks(t *testing.T) {
+	ticks := []Tick{
+		{Time: mockSessionOpen, Price: 10, Size: 100},
+		{Time: mockSessionOpen.Add(time.Second), Price: 11, Size: 300},
+		{Time: mockSessionOpen.Add(2 * time.Second), Price: 13, Size: 100},
+	}
+
+	s, _ := NewAnchoredVWAPStream64(1)
+	assert.Nil(t, s.Anchor(ticks[1].Time))
+	for _, tick := range ticks {
+		s.UpdateTick(tick)
+	}
+
+	// anchored at the second tick: (11 * 300 + 13 * 100) / 400
+	assert.InDelta(t, 11.5, s.Value(), 1e-12)
+	assert.Equal(t, 400.0, s.Volume())
+	assert.InDelta(t, 0.75, s.StdDev()*s.StdDev(), 1e-12)
+}
@@ -0,0 +1,494 @@
+package technical
+
+import (
+	"math"
+)
+
+/*
+* ATR based position sizing and stops use the Average True Range as the unit of price risk.
+*
+* A position sized by ATRPositionSize64 loses the same fraction of capital when stopped out k ATRs away,
+* whatever the volatility of the instrument. The stops trail the price by k ATRs:
+*
+*	Chandelier Exit: k ATRs below the highest high (long) and above the lowest low (short) of a lookback
+*	ATR trailing stop: k ATRs from the close, ratcheting with the trend and flipping side when the close crosses it
+*	SuperTrend: k ATRs from the bar midpoint (high + low) / 2, ratcheting and flipping like the trailing stop
+*
+* The ATR of each is computed with ATRStream64, i.e. RollingATR64 after the warm up.
+* Stops are rounded to a tick size away from the price, long stops down and short stops up, the zero TickSize to not round.
+ */
+
+// valueBar returns the bar of a single value
+func valueBar(v float64) Bar {
+	return Bar{Open: v, High: v, Low: v, Close: v, Ticks: 1}
+}
+
+// ATRPositionSize64 computes the position size which loses a fraction of capital if stopped out k ATRs away
+// size = capital * risk / (k * atr), rounded down to a multiple of lot, or not rounded if lot is 0
+// For contracts with a multiplier divide the size by the multiplier.
+// Returns 0.0 if atr, k or risk is not positive, capital is negative or lot is negative.
+//
+// Parameters:
+//
+//	capital: capital of the account
+//	risk: fraction of the capital to risk, e.g. 0.01
+//	atr: current ATR
+//	k: number of ATRs to the stop
+//	lot: size increment, e.g. 100 shares or 1 contract
+func ATRPositionSize64(capital float64, risk float64, atr float64, k float64, lot float64) float64 {
+	if !(atr > 0.0) || !(k > 0.0) || !(risk > 0.0) || !(capital >= 0.0) || !(lot >= 0.0) {
+		return 0.0
+	}
+
+	size := capital * risk / (k * atr)
+	if lot == 0.0 {
+		return size
+	}
+
+	// tolerate rounding of the division, e.g. a size of 299.99999999999994 lots is 300
+	return math.Floor(size/lot+1e-9) * lot
+}
+
+// ATRPositionSize32 is 32 bit version of ATRPositionSize64
+func ATRPositionSize32(capital float32, risk float32, atr float32, k float32, lot float32) float32 {
+	return float32(ATRPositionSize64(float64(capital), float64(risk), float64(atr), float64(k), float64(lot)))
+}
+
+// ATRPositionSizes64 computes the position size of each bar with the ATR of n periods, see ATRPositionSize64
+// Sizes before the ATR is ready are 0.0. Returns nil if n <= 0.
+func ATRPositionSizes64(bars []Bar, n int, capital float64, risk float64, k float64, lot float64) []float64 {
+	s, err := NewPositionSizer64(n, risk, k, lot)
+	if err != nil {
+		return nil
+	}
+
+	sizes := make([]float64, len(bars))
+	for i, b := range bars {
+		s.UpdateBar(b)
+		sizes[i] = s.Size(capital)
+	}
+
+	return sizes
+}
+
+// PositionSizer64 sizes positions with the ATR of a stream of bars, see ATRPositionSize64
+type PositionSizer64 struct {
+	atr  ATRStream64
+	risk float64
+	k    float64
+	lot  float64
+}
+
+// NewPositionSizer64 creates a PositionSizer64 with an ATR of n periods
+// Returns ErrInvalidPeriods if n <= 0
+func NewPositionSizer64(n int, risk float64, k float64, lot float64) (*PositionSizer64, error) {
+	atr, err := NewATRStream64(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PositionSizer64{atr: *atr, risk: risk, k: k, lot: lot}, nil
+}
+
+// Update adds the next value of the stream as a period of one value
+func (s *PositionSizer64) Update(v float64) {
+	s.atr.Update(v)
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *PositionSizer64) UpdateBar(b Bar) {
+	s.atr.UpdateBar(b)
+}
+
+// Size returns the position size for capital, 0.0 before the ATR is ready
+func (s *PositionSizer64) Size(capital float64) float64 {
+	if !s.atr.Ready() {
+		return 0.0
+	}
+
+	return ATRPositionSize64(capital, s.risk, s.atr.Value(), s.k, s.lot)
+}
+
+// ATR returns the current ATR
+func (s *PositionSizer64) ATR() float64 {
+	return s.atr.Value()
+}
+
+// Ready reports whether the ATR is ready
+func (s *PositionSizer64) Ready() bool {
+	return s.atr.Ready()
+}
+
+// Reset clears the stream
+func (s *PositionSizer64) Reset() {
+	s.atr.Reset()
+}
+
+// ChandelierExit64 computes the Chandelier Exit of each bar, see ChandelierStream64
+// Bounds before the stream is ready are empty. Returns nil for invalid parameters.
+func ChandelierExit64(bars []Bar, n int, lb int, k float64, tick TickSize) []Bound64 {
+	s, err := NewChandelierStream64(n, lb, k, tick)
+	if err != nil {
+		return nil
+	}
+
+	bounds := make([]Bound64, len(bars))
+	for i, b := range bars {
+		s.UpdateBar(b)
+		if s.Ready() {
+			bounds[i] = s.Bound()
+		}
+	}
+
+	return bounds
+}
+
+// ChandelierStream64 computes the Chandelier Exit over a stream of bars
+// The Lower bound is the long exit, k ATRs below the highest high of the last lb bars,
+// and the Upper bound is the short exit, k ATRs above the lowest low. The Midpoint is halfway between.
+type ChandelierStream64 struct {
+	atr   ATRStream64
+	highs extremeWindow64
+	lows  extremeWindow64
+	k     float64
+	tick  TickSize
+}
+
+// NewChandelierStream64 creates a ChandelierStream64
+// Returns ErrInvalidPeriods if n <= 0, ErrInvalidLookback if lb <= 0 and ErrInvalidMultiplier if k < 0
+//
+// Parameters:
+//
+//	n: number of periods of the ATR, typically 22
+//	lb: lookback of the highest high and lowest low, typically 22
+//	k: multiplier on the ATR, typically 3
+//	tick: tick size the exits are rounded to, the zero TickSize to not round
+func NewChandelierStream64(n int, lb int, k float64, tick TickSize) (*ChandelierStream64, error) {
+	atr, err := NewATRStream64(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if lb <= 0 {
+		return nil, ErrInvalidLookback
+	}
+
+	if err := checkMultiplier(k); err != nil {
+		return nil, err
+	}
+
+	return &ChandelierStream64{atr: *atr, highs: newMaxWindow64(lb), lows: newMinWindow64(lb), k: k, tick: tick}, nil
+}
+
+// Update adds the next value of the stream as a bar of one value
+func (s *ChandelierStream64) Update(v float64) {
+	s.UpdateBar(valueBar(v))
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *ChandelierStream64) UpdateBar(b Bar) {
+	s.atr.UpdateBar(b)
+	s.highs.push(b.High)
+	s.lows.push(b.Low)
+}
+
+// Bound returns the current exits
+func (s *ChandelierStream64) Bound() Bound64 {
+	atr := s.k * s.atr.Value()
+	lower := s.tick.Floor(s.highs.value() - atr)
+	upper := s.tick.Ceil(s.lows.value() + atr)
+
+	return Bound64{Lower: lower, Midpoint: (lower + upper) / 2.0, Upper: upper}
+}
+
+// Value returns the midpoint of the current exits
+func (s *ChandelierStream64) Value() float64 {
+	return s.Bound().Midpoint
+}
+
+// Ready reports whether the ATR and lookback are full
+func (s *ChandelierStream64) Ready() bool {
+	return s.atr.Ready() && s.highs.full()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *ChandelierStream64) WarmupPeriod() int {
+	if lb := s.highs.size(); lb > s.atr.n {
+		return lb
+	}
+
+	return s.atr.n
+}
+
+// Reset clears the stream
+func (s *ChandelierStream64) Reset() {
+	s.atr.Reset()
+	s.highs.reset()
+	s.lows.reset()
+}
+
+// Clone returns an independent copy of the stream
+func (s *ChandelierStream64) Clone() *ChandelierStream64 {
+	c := *s
+	c.highs = s.highs.clone()
+	c.lows = s.lows.clone()
+
+	return &c
+}
+
+// StopLevel is a trailing stop and the side of the position it protects
+type StopLevel struct {
+	Stop float64 `json:"stop"`
+	Long bool    `json:"long"` // true for a stop below the price protecting a long position
+}
+
+// ATRTrailingStop64 computes the ATR trailing stop of each bar, see ATRTrailingStopStream64
+// Levels before the stream is ready are empty. Returns nil for invalid parameters.
+func ATRTrailingStop64(bars []Bar, n int, k float64, tick TickSize) []StopLevel {
+	s, err := NewATRTrailingStopStream64(n, k, tick)
+	if err != nil {
+		return nil
+	}
+
+	levels := make([]StopLevel, len(bars))
+	for i, b := range bars {
+		s.UpdateBar(b)
+		if s.Ready() {
+			levels[i] = s.Level()
+		}
+	}
+
+	return levels
+}
+
+// ATRTrailingStopStream64 computes an ATR trailing stop over a stream of bars
+// The stop starts long, k ATRs below the close of the first bar the ATR is ready, and only moves up.
+// When a close is below it the stop flips short, k ATRs above the close, and only moves down until a close is above it.
+type ATRTrailingStopStream64 struct {
+	atr   ATRStream64
+	k     float64
+	tick  TickSize
+	level StopLevel
+	init  bool
+}
+
+// NewATRTrailingStopStream64 creates an ATRTrailingStopStream64
+// Returns ErrInvalidPeriods if n <= 0 and ErrInvalidMultiplier if k < 0
+//
+// Parameters:
+//
+//	n: number of periods of the ATR
+//	k: multiplier on the ATR, typically 2 to 3
+//	tick: tick size the stop is rounded to, the zero TickSize to not round
+func NewATRTrailingStopStream64(n int, k float64, tick TickSize) (*ATRTrailingStopStream64, error) {
+	atr, err := NewATRStream64(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkMultiplier(k); err != nil {
+		return nil, err
+	}
+
+	return &ATRTrailingStopStream64{atr: *atr, k: k, tick: tick}, nil
+}
+
+// Update adds the next value of the stream as a bar of one value
+func (s *ATRTrailingStopStream64) Update(v float64) {
+	s.UpdateBar(valueBar(v))
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *ATRTrailingStopStream64) UpdateBar(b Bar) {
+	s.atr.UpdateBar(b)
+	if !s.atr.Ready() {
+		return
+	}
+
+	atr := s.k * s.atr.Value()
+	long, short := s.tick.Floor(b.Close-atr), s.tick.Ceil(b.Close+atr)
+
+	switch {
+	case !s.init:
+		s.level, s.init = StopLevel{Stop: long, Long: true}, true
+	case s.level.Long && b.Close < s.level.Stop:
+		s.level = StopLevel{Stop: short}
+	case s.level.Long:
+		s.level.Stop = math.Max(s.level.Stop, long)
+	case b.Close > s.level.Stop:
+		s.level = StopLevel{Stop: long, Long: true}
+	default:
+		s.level.Stop = math.Min(s.level.Stop, short)
+	}
+}
+
+// Level returns the current stop and its side
+func (s *ATRTrailingStopStream64) Level() StopLevel {
+	return s.level
+}
+
+// Value returns the current stop
+func (s *ATRTrailingStopStream64) Value() float64 {
+	return s.level.Stop
+}
+
+// Ready reports whether the ATR is ready
+func (s *ATRTrailingStopStream64) Ready() bool {
+	return s.atr.Ready()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *ATRTrailingStopStream64) WarmupPeriod() int {
+	return s.atr.n
+}
+
+// Reset clears the stream
+func (s *ATRTrailingStopStream64) Reset() {
+	s.atr.Reset()
+	s.level, s.init = StopLevel{}, false
+}
+
+// Clone returns an independent copy of the stream
+func (s *ATRTrailingStopStream64) Clone() *ATRTrailingStopStream64 {
+	c := *s
+	return &c
+}
+
+// SuperTrend64 computes the SuperTrend of each bar, see SuperTrendStream64
+// Levels before the stream is ready are empty. Returns nil for invalid parameters.
+func SuperTrend64(bars []Bar, n int, k float64, tick TickSize) []StopLevel {
+	s, err := NewSuperTrendStream64(n, k, tick)
+	if err != nil {
+		return nil
+	}
+
+	levels := make([]StopLevel, len(bars))
+	for i, b := range bars {
+		s.UpdateBar(b)
+		if s.Ready() {
+			levels[i] = s.Level()
+		}
+	}
+
+	return levels
+}
+
+// SuperTrendStream64 computes the SuperTrend over a stream of bars
+// The bands are k ATRs above and below the bar midpoint (high + low) / 2. The lower band only moves up
+// unless the previous close was below it and the upper band only moves down unless the previous close was above it.
+// In an up trend the SuperTrend is the lower band until a close is below it, in a down trend it is the upper band
+// until a close is above it. The trend starts up on the first bar the ATR is ready.
+type SuperTrendStream64 struct {
+	atr   ATRStream64
+	k     float64
+	tick  TickSize
+	upper float64
+	lower float64
+	close float64
+	long  bool
+	init  bool
+}
+
+// NewSuperTrendStream64 creates a SuperTrendStream64
+// Returns ErrInvalidPeriods if n <= 0 and ErrInvalidMultiplier if k < 0
+//
+// Parameters:
+//
+//	n: number of periods of the ATR, typically 10
+//	k: multiplier on the ATR, typically 3
+//	tick: tick size the SuperTrend is rounded to, the zero TickSize to not round
+func NewSuperTrendStream64(n int, k float64, tick TickSize) (*SuperTrendStream64, error) {
+	atr, err := NewATRStream64(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkMultiplier(k); err != nil {
+		return nil, err
+	}
+
+	return &SuperTrendStream64{atr: *atr, k: k, tick: tick}, nil
+}
+
+// Update adds the next value of the stream as a bar of one value
+func (s *SuperTrendStream64) Update(v float64) {
+	s.UpdateBar(valueBar(v))
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *SuperTrendStream64) UpdateBar(b Bar) {
+	s.atr.UpdateBar(b)
+	if !s.atr.Ready() {
+		return
+	}
+
+	mid, atr := (b.High+b.Low)/2.0, s.k*s.atr.Value()
+	upper, lower := s.tick.Ceil(mid+atr), s.tick.Floor(mid-atr)
+
+	if !s.init {
+		s.upper, s.lower, s.long, s.init = upper, lower, true, true
+	} else {
+		if upper < s.upper || s.close > s.upper {
+			s.upper = upper
+		}
+
+		if lower > s.lower || s.close < s.lower {
+			s.lower = lower
+		}
+
+		switch {
+		case s.long && b.Close < s.lower:
+			s.long = false
+		case !s.long && b.Close > s.upper:
+			s.long = true
+		}
+	}
+
+	s.close = b.Close
+}
+
+// Level returns the current SuperTrend and the side of the trend, Long in an up trend
+func (s *SuperTrendStream64) Level() StopLevel {
+	if !s.init {
+		return StopLevel{}
+	}
+
+	if s.long {
+		return StopLevel{Stop: s.lower, Long: true}
+	}
+
+	return StopLevel{Stop: s.upper}
+}
+
+// Bound returns the current upper and lower bands, the Midpoint is the SuperTrend
+func (s *SuperTrendStream64) Bound() Bound64 {
+	return Bound64{Lower: s.lower, Midpoint: s.Value(), Upper: s.upper}
+}
+
+// Value returns the current SuperTrend
+func (s *SuperTrendStream64) Value() float64 {
+	return s.Level().Stop
+}
+
+// Ready reports whether the ATR is ready
+func (s *SuperTrendStream64) Ready() bool {
+	return s.atr.Ready()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *SuperTrendStream64) WarmupPeriod() int {
+	return s.atr.n
+}
+
+// Reset clears the stream
+func (s *SuperTrendStream64) Reset() {
+	s.atr.Reset()
+	s.upper, s.lower, s.close = 0.0, 0.0, 0.0
+	s.long, s.init = false, false
+}
+
+// Clone returns an independent copy of the stream
+func (s *SuperTrendStream64) Clone() *SuperTrendStream64 {
+	c := *s
+	return &c
+}
@@ -0,0 +1,228 @@
+package technical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stopBars rises from 10 to 14 and falls back to 9
+func stopBars() []Bar {
+	return testBars(
+		[4]float64{10, 10.5, 9.5, 10},
+		[4]float64{10, 11.5, 9.5, 11},
+		[4]float64{11, 12.5, 10.5, 12},
+		[4]float64{12, 13.5, 11.5, 13},
+		[4]float64{13, 14.5, 12.5, 14},
+		[4]float64{14, 14.5, 11.5, 12},
+		[4]float64{12, 12.5, 9.5, 10},
+		[4]float64{10, 10.5, 8.5, 9},
+	)
+}
+
+func TestATRPositionSize(t *testing.T) {
+	// risking 1% of 100000 on a stop 2 ATRs of 0.5 away is 1000 units
+	assert.Equal(t, 1000.0, ATRPositionSize64(100000, 0.01, 0.5, 2, 0))
+	assert.Equal(t, 1000.0, ATRPositionSize64(100000, 0.01, 0.5, 2, 100))
+	assert.Equal(t, 900.0, ATRPositionSize64(100000, 0.01, 0.55, 2, 100))
+	assert.InDelta(t, 909.0909, ATRPositionSize64(100000, 0.01, 0.55, 2, 0), 1e-4)
+
+	assert.Equal(t, 0.0, ATRPositionSize64(100000, 0.01, 0, 2, 100))
+	assert.Equal(t, 0.0, ATRPositionSize64(100000, 0.01, 0.5, 0, 100))
+	assert.Equal(t, 0.0, ATRPositionSize64(-1, 0.01, 0.5, 2, 100))
+	assert.InDelta(t, float32(1000), ATRPositionSize32(100000, 0.01, 0.5, 2, 0), 1e-3)
+}
+
+func TestATRPositionSizes(t *testing.T) {
+	bars := stopBars()
+
+	sizes := ATRPositionSizes64(bars, 3, 10000, 0.01, 2, 1)
+	assert.Len(t, sizes, len(bars))
+	assert.Equal(t, []float64{0, 0}, sizes[:2])
+
+	atr, _ := NewATRStream64(3)
+	for i, b := range bars {
+		atr.UpdateBar(b)
+		if i >= 2 {
+			assert.Equal(t, ATRPositionSize64(10000, 0.01, atr.Value(), 2, 1), sizes[i])
+		}
+	}
+
+	assert.Nil(t, ATRPositionSizes64(bars, 0, 10000, 0.01, 2, 1))
+
+	s, _ := NewPositionSizer64(3, 0.01, 2, 1)
+	for _, b := range bars {
+		s.UpdateBar(b)
+	}
+
+	assert.True(t, s.Ready())
+	assert.Equal(t, atr.Value(), s.ATR())
+	assert.Equal(t, sizes[len(sizes)-1], s.Size(10000))
+
+	s.Reset()
+	assert.Equal(t, 0.0, s.Size(10000))
+}
+
+func TestChandelierExit(t *testing.T) {
+	bars := stopBars()
+
+	_, err := NewChandelierStream64(3, 0, 3, TickSize{})
+	assert.Equal(t, ErrInvalidLookback, err)
+	_, err = NewChandelierStream64(3, 3, -1, TickSize{})
+	assert.Equal(t, ErrInvalidMultiplier, err)
+	assert.Nil(t, ChandelierExit64(bars, 0, 3, 3, TickSize{}))
+
+	bounds := ChandelierExit64(bars, 2, 3, 1, TickSize{})
+	assert.Equal(t, Bound64{}, bounds[1])
+
+	atr, _ := NewATRStream64(2)
+	for i, b := range bars {
+		atr.UpdateBar(b)
+		if i < 2 {
+			continue
+		}
+
+		high, low := 0.0, 1e9
+		for _, p := range bars[i-2 : i+1] {
+			high, low = math.Max(high, p.High), math.Min(low, p.Low)
+		}
+
+		assert.InDelta(t, high-atr.Value(), bounds[i].Lower, 1e-12)
+		assert.InDelta(t, low+atr.Value(), bounds[i].Upper, 1e-12)
+		assert.InDelta(t, (bounds[i].Lower+bounds[i].Upper)/2, bounds[i].Midpoint, 1e-12)
+	}
+
+	// rounded away from the price
+	rounded := ChandelierExit64(bars, 2, 3, 1, quarter)
+	for i := 2; i < len(bars); i++ {
+		assert.Equal(t, quarter.Floor(bounds[i].Lower), rounded[i].Lower)
+		assert.Equal(t, quarter.Ceil(bounds[i].Upper), rounded[i].Upper)
+	}
+
+	s, _ := NewChandelierStream64(2, 3, 1, TickSize{})
+	assert.Equal(t, 3, s.WarmupPeriod())
+	for _, b := range bars {
+		s.UpdateBar(b)
+	}
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, bounds[len(bounds)-1], c.Bound())
+	assert.Equal(t, bounds[len(bounds)-1].Midpoint, c.Value())
+}
+
+func TestATRTrailingStop(t *testing.T) {
+	bars := stopBars()
+
+	_, err := NewATRTrailingStopStream64(0, 2, TickSize{})
+	assert.Equal(t, ErrInvalidPeriods, err)
+	assert.Nil(t, ATRTrailingStop64(bars, 2, -1, TickSize{}))
+
+	levels := ATRTrailingStop64(bars, 2, 1, TickSize{})
+	assert.Equal(t, StopLevel{}, levels[0])
+
+	// long from the first ready bar, ratcheting up with the rally
+	for i := 1; i < 5; i++ {
+		assert.True(t, levels[i].Long)
+		assert.True(t, levels[i].Stop >= levels[i-1].Stop)
+		assert.True(t, levels[i].Stop < bars[i].Close)
+	}
+
+	// the sell off closes below the stop and flips it short above the close
+	flip := 5
+	for levels[flip].Long {
+		flip++
+	}
+
+	assert.True(t, bars[flip].Close < levels[flip-1].Stop)
+	assert.True(t, levels[flip].Stop > bars[flip].Close)
+	for i := flip + 1; i < len(bars); i++ {
+		assert.False(t, levels[i].Long)
+		assert.True(t, levels[i].Stop <= levels[i-1].Stop)
+	}
+
+	s, _ := NewATRTrailingStopStream64(2, 1, quarter)
+	for _, b := range bars {
+		s.UpdateBar(b)
+	}
+
+	assert.Equal(t, 2, s.WarmupPeriod())
+	assert.False(t, s.Level().Long)
+	rounded := ATRTrailingStop64(bars, 2, 1, quarter)
+	assert.Equal(t, rounded[len(rounded)-1].Stop, s.Value())
+	assert.Equal(t, quarter.Ceil(s.Value()), s.Value())
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, StopLevel{}, s.Level())
+	assert.True(t, c.Ready())
+}
+
+func TestSuperTrend(t *testing.T) {
+	bars := stopBars()
+
+	_, err := NewSuperTrendStream64(2, -3, TickSize{})
+	assert.Equal(t, ErrInvalidMultiplier, err)
+	assert.Nil(t, SuperTrend64(bars, 0, 3, TickSize{}))
+
+	levels := SuperTrend64(bars, 2, 1, TickSize{})
+	assert.Equal(t, StopLevel{}, levels[0])
+
+	atr, _ := NewATRStream64(2)
+	atr.UpdateBar(bars[0])
+	atr.UpdateBar(bars[1])
+
+	// the first ready bar starts the up trend on the lower band
+	assert.Equal(t, StopLevel{Stop: (bars[1].High+bars[1].Low)/2 - atr.Value(), Long: true}, levels[1])
+
+	// the lower band only rises during the rally
+	for i := 2; i < 5; i++ {
+		assert.True(t, levels[i].Long)
+		assert.True(t, levels[i].Stop >= levels[i-1].Stop)
+	}
+
+	// the sell off closes below the lower band and flips to the upper band
+	assert.False(t, levels[len(levels)-1].Long)
+	assert.True(t, levels[len(levels)-1].Stop > bars[len(bars)-1].Close)
+
+	s, _ := NewSuperTrendStream64(2, 1, TickSize{})
+	for _, b := range bars {
+		s.UpdateBar(b)
+	}
+
+	b := s.Bound()
+	assert.Equal(t, b.Upper, s.Value())
+	assert.Equal(t, s.Value(), b.Midpoint)
+	assert.True(t, b.Lower < b.Upper)
+
+	c := s.Clone()
+	s.Reset()
+	assert.Equal(t, StopLevel{}, s.Level())
+	assert.Equal(t, levels[len(levels)-1], c.Level())
+}
+
+func TestMockSuperTrend(t *testing.T) {
+	bars := mockBars()
+
+	// stream update with single values matches bars of one value
+	series := BarCloses(bars)
+	values := make([]Bar, len(series))
+	for i, v := range series {
+		values[i] = valueBar(v)
+	}
+
+	s, _ := NewSuperTrendStream64(10, 3, cent)
+	for _, v := range series {
+		s.Update(v)
+	}
+
+	levels := SuperTrend64(values, 10, 3, cent)
+	assert.Equal(t, levels[len(levels)-1], s.Level())
+
+	for _, l := range SuperTrend64(bars, 10, 3, cent)[9:] {
+		assert.Equal(t, cent.Round(l.Stop), l.Stop)
+	}
+}
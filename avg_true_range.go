@@ -13,6 +13,9 @@ is a technical analysis indicator that measures the price change volatility.
 // True range is defined as the following:
 // max(High, last) −  min(Low, last)
 // where last is the previous period close, or last value
+// Non positive values are ignored as gaps, the MissingSkip mode of a MissingPolicy with ZeroIsMissing,
+// while NaN values propagate as MissingPropagate. Returns 0.0 for an empty period.
+// See MissingPolicy.TrueRange64 to forward fill or reject gaps instead.
 func TrueRange64(period []float64, last float64) float64 {
 	if len(period) == 0 {
 		return 0.0
@@ -23,6 +26,10 @@ func TrueRange64(period []float64, last float64) float64 {
 	high := 0.0
 	low := magic // first val is magic number
 	for _, v := range period {
+		if math.IsNaN(v) {
+			return v
+		}
+
 		if v > high {
 			high = v
 		}
@@ -62,6 +69,10 @@ func TrueRange32(period []float32, last float32) float32 {
 	high := float32(0.0)
 	low := magic // first val is magic number
 	for _, v := range period {
+		if math.IsNaN(float64(v)) {
+			return v
+		}
+
 		if v > high {
 			high = v
 		}
@@ -92,8 +103,10 @@ func TrueRange32(period []float32, last float32) float32 {
 
 // RollingATR64 computes the next ATR value based on the prior periods ATR (lastATR),
 // the current periods TrueRange64 (curTR), and the number of periods (n)
+// This is an EWMA of the true ranges with the Wilder smoothing y = 1/n, see SmoothingWilder
+// Returns 0.0 if n <= 0
 func RollingATR64(lastATR float64, curTR float64, n int) float64 {
-	if n == 0 {
+	if n <= 0 {
 		return 0.0
 	}
 
@@ -102,7 +115,7 @@ func RollingATR64(lastATR float64, curTR float64, n int) float64 {
 
 // RollingATR32 is 32 bit version of RollingATR64
 func RollingATR32(lastATR float32, curTR float32, n int) float32 {
-	if n == 0 {
+	if n <= 0 {
 		return 0.0
 	}
 
@@ -119,8 +132,10 @@ func RollingATR32(lastATR float32, curTR float32, n int) float32 {
 // complete ATR value the simple average of the partially complete
 // true ranges of the derived periods is used as an approximate ATR
 // To compute a true range a period must be totally complete
+// See StaticBarATR64 to compute an ATR from bars aggregated by time, tick count, volume or value
+// Returns 0.0 if n <= 0 or s <= 0. See StaticATRChecked64 to validate the parameters instead.
 func StaticATR64(series []float64, n int, s int) float64 {
-	if n == 0 || s == 0 {
+	if n <= 0 || s <= 0 {
 		return 0.0
 	}
 
@@ -144,6 +159,11 @@ func StaticATR64(series []float64, n int, s int) float64 {
 
 	}
 
+	return atrFromTrueRanges64(trngs, n)
+}
+
+// atrFromTrueRanges64 computes the ATR of a list of period true ranges
+func atrFromTrueRanges64(trngs []float64, n int) float64 {
 	// if dont have enough period true range values just return avg
 	if n >= len(trngs) {
 		return SimpleAvg64(trngs)
@@ -163,7 +183,7 @@ func StaticATR64(series []float64, n int, s int) float64 {
 
 // StaticATR32 is 32 bit version of StaticATR64
 func StaticATR32(series []float32, n int, s int) float32 {
-	if n == 0 || s == 0 {
+	if n <= 0 || s <= 0 {
 		return 0.0
 	}
 
@@ -202,3 +222,148 @@ func StaticATR32(series []float32, n int, s int) float32 {
 
 	return atr
 }
+
+// StaticATRChecked64 is StaticATR64 returning an error for invalid parameters
+// Returns ErrEmptySeries, ErrInvalidPeriods if n <= 0
+// and ErrInvalidPeriodSize if s <= 0 or s > len(series) i.e. no complete period
+func StaticATRChecked64(series []float64, n int, s int) (float64, error) {
+	if err := checkATR(len(series), n, s); err != nil {
+		return 0.0, err
+	}
+
+	return StaticATR64(series, n, s), nil
+}
+
+// StaticATRChecked32 is 32 bit version of StaticATRChecked64
+func StaticATRChecked32(series []float32, n int, s int) (float32, error) {
+	if err := checkATR(len(series), n, s); err != nil {
+		return 0.0, err
+	}
+
+	return StaticATR32(series, n, s), nil
+}
+
+// checkATR validates the parameters of a static ATR
+func checkATR(size int, n int, s int) error {
+	if size == 0 {
+		return ErrEmptySeries
+	}
+
+	if n <= 0 {
+		return ErrInvalidPeriods
+	}
+
+	if s <= 0 || s > size {
+		return ErrInvalidPeriodSize
+	}
+
+	return nil
+}
+
+// BarTrueRange64 computes the Wilder True Range of a bar
+// where last is the close of the previous bar, or 0.0 if there is none
+// Equivalent to TrueRange64 of the bar high and low.
+func BarTrueRange64(b Bar, last float64) float64 {
+	return TrueRange64([]float64{b.Low, b.High}, last)
+}
+
+// StaticBarATR64 computes an ATR value from a list of bars based on a number of periods (n)
+// Each bar is a period, see StaticATR64 for how the ATR is derived from the period true ranges.
+// Bars can be aggregated from ticks with any BarAggregator, i.e. by time, tick count, volume or value.
+// Returns 0.0 if n <= 0.
+func StaticBarATR64(bars []Bar, n int) float64 {
+	if n <= 0 {
+		return 0.0
+	}
+
+	trngs := make([]float64, len(bars))
+	for i, b := range bars {
+		last := 0.0
+		if i != 0 {
+			last = bars[i-1].Close
+		}
+
+		trngs[i] = BarTrueRange64(b, last)
+	}
+
+	return atrFromTrueRanges64(trngs, n)
+}
+
+// ATRStream64 computes an ATR over a stream of bars
+// The first n bars warm up the stream, during which the ATR is the simple average of their true ranges.
+// After that the ATR is updated with RollingATR64, as StaticBarATR64.
+type ATRStream64 struct {
+	n     int
+	atr   float64
+	last  float64
+	count int
+}
+
+// NewATRStream64 creates an ATRStream64 over n periods
+// Returns ErrInvalidPeriods if n <= 0
+func NewATRStream64(n int) (*ATRStream64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	return &ATRStream64{n: n}, nil
+}
+
+// Update adds the next value of the stream as a period of one value
+// Equivalent to StaticATR64 with a period size of 1, aggregate values into bars to use larger periods.
+func (s *ATRStream64) Update(v float64) {
+	s.UpdateTrueRange(TrueRange64([]float64{v}, s.last))
+	s.last = v
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *ATRStream64) UpdateBar(b Bar) {
+	s.UpdateTrueRange(BarTrueRange64(b, s.last))
+	s.last = b.Close
+}
+
+// UpdateTrueRange adds the next period true range of the stream directly
+// Use when the true range is not derived from a Bar, e.g. from TrueRange64
+func (s *ATRStream64) UpdateTrueRange(tr float64) {
+	s.count++
+
+	if s.count <= s.n { // warm up is the simple average
+		s.atr += (tr - s.atr) / float64(s.count)
+		return
+	}
+
+	s.atr = RollingATR64(s.atr, tr, s.n)
+}
+
+// Value returns the current ATR
+func (s *ATRStream64) Value() float64 {
+	return s.atr
+}
+
+// Ready reports whether n periods have been seen
+func (s *ATRStream64) Ready() bool {
+	return s.count >= s.n
+}
+
+// WarmupPeriod returns the number of periods before the stream is ready
+func (s *ATRStream64) WarmupPeriod() int {
+	return s.n
+}
+
+// Reset clears the stream
+func (s *ATRStream64) Reset() {
+	s.atr, s.last, s.count = 0.0, 0.0, 0
+}
+
+// AdjustGap shifts the last close by gap, so the overnight gap is excluded from the true range of the next bar
+func (s *ATRStream64) AdjustGap(gap float64) {
+	if s.count > 0 {
+		s.last += gap
+	}
+}
+
+// Clone returns an independent copy of the stream
+func (s *ATRStream64) Clone() *ATRStream64 {
+	c := *s
+	return &c
+}
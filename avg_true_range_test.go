@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -124,3 +125,103 @@ func TestStaticATR32(t *testing.T) {
 
 	assert.Equal(t, float32(0.25532743), StaticATR32(testseries, 30, 300))
 }
+
+func TestStaticATRChecked64(t *testing.T) {
+	s := []float64{1, 4, 2, 7, 9, 4}
+
+	_, err := StaticATRChecked64(nil, 3, 5)
+	assert.Equal(t, ErrEmptySeries, err)
+
+	_, err = StaticATRChecked64(s, 0, 5)
+	assert.Equal(t, ErrInvalidPeriods, err)
+
+	_, err = StaticATRChecked64(s, 3, -1)
+	assert.Equal(t, ErrInvalidPeriodSize, err)
+
+	// no complete period
+	_, err = StaticATRChecked64(s, 3, 10)
+	assert.Equal(t, ErrInvalidPeriodSize, err)
+
+	atr, err := StaticATRChecked64(s, 3, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 8.0, atr)
+
+	// unchecked version does not panic on negative parameters
+	assert.Equal(t, 0.0, StaticATR64(s, -1, 5))
+	assert.Equal(t, 0.0, RollingATR64(8.0, 3.0, -1))
+}
+
+func TestStaticATRChecked32(t *testing.T) {
+	s := []float32{1, 4, 2, 7, 9, 4}
+
+	_, err := StaticATRChecked32(s, -3, 5)
+	assert.Equal(t, ErrInvalidPeriods, err)
+
+	_, err = StaticATRChecked32(s, 3, 0)
+	assert.Equal(t, ErrInvalidPeriodSize, err)
+
+	atr, err := StaticATRChecked32(s, 3, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(8.0), atr)
+
+	assert.Equal(t, float32(0.0), StaticATR32(s, -1, 5))
+}
+
+func TestBarTrueRange64(t *testing.T) {
+	b := Bar{Open: 4, High: 9, Low: 1, Close: 4}
+	assert.Equal(t, 8.0, BarTrueRange64(b, 0.0))
+	assert.Equal(t, 10.0, BarTrueRange64(b, 11.0))
+	assert.Equal(t, 8.0, BarTrueRange64(b, 5.0))
+}
+
+func TestStaticBarATR64(t *testing.T) {
+	assert.Equal(t, 0.0, StaticBarATR64(nil, 3))
+	assert.Equal(t, 0.0, StaticBarATR64([]Bar{{High: 2, Low: 1}}, 0))
+
+	// tick bars of the period size reproduce the periods of StaticATR64
+	testseries := readMockSeries64("./mock/test_atr_series.txt")
+	ticks := SeriesTicks(testseries, mockSessionTimes(len(testseries)))
+
+	bars, err := TickBars(ticks, 300)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticATR64(testseries, 30, 300), StaticBarATR64(bars, 30))
+
+	// 5 minute time bars of the per second series are the same periods
+	bars, err = TimeBars(ticks, 5*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.25532787677004043, StaticBarATR64(bars, 30))
+}
+
+func TestATRStream64(t *testing.T) {
+	_, err := NewATRStream64(0)
+	assert.Equal(t, ErrInvalidPeriods, err)
+
+	testseries := readMockSeries64("./mock/test_atr_series.txt")
+	ticks := SeriesTicks(testseries, mockSessionTimes(len(testseries)))
+
+	agg, _ := NewTimeBarAggregator(5 * time.Minute)
+	s, err := NewATRStream64(30)
+	assert.NoError(t, err)
+
+	for _, tk := range ticks {
+		if b, ok := agg.Update(tk); ok {
+			s.UpdateBar(b)
+		}
+	}
+
+	b, ok := agg.Flush()
+	assert.True(t, ok)
+	s.UpdateBar(b)
+
+	assert.True(t, s.Ready())
+	assert.InDelta(t, 0.25532787677004043, s.Value(), 1e-12)
+
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, 0.0, s.Value())
+
+	// true ranges directly, warm up is the simple average
+	s.UpdateTrueRange(8.0)
+	assert.Equal(t, 8.0, s.Value())
+	assert.False(t, s.Ready())
+}
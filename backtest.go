@@ -0,0 +1,402 @@
+package technical
+
+import (
+	"math"
+	"time"
+)
+
+/*
+* The backtester replays bars (or ticks) through a Strategy and simulates the fills of its orders.
+*
+* It is deterministic and single threaded. A strategy sees each bar once it is complete and its orders
+* can only fill from the next bar on, so a strategy can never trade on a price it has not yet seen:
+*
+*	market orders fill at the open of the next bar
+*	limit orders fill at the open if it is better than the limit, otherwise at the limit if the bar reaches it
+*	stop orders fill at the open if it gaps through the stop, otherwise at the stop if the bar reaches it
+*
+* Slippage is applied adversely to market and stop fills, limit fills are at their price or better.
+ */
+
+// Side is the side of an order, 1 to buy and -1 to sell
+type Side int
+
+const (
+	// Buy buys, increasing the position
+	Buy Side = 1
+	// Sell sells, decreasing the position
+	Sell Side = -1
+)
+
+// String returns the name of the side
+func (s Side) String() string {
+	if s == Buy {
+		return "buy"
+	}
+
+	return "sell"
+}
+
+// OrderType is the type of an order
+type OrderType int
+
+const (
+	// OrderMarket fills at the next available price
+	OrderMarket OrderType = iota
+	// OrderLimit fills at its price or better
+	OrderLimit
+	// OrderStop becomes a market order once the price reaches its stop price
+	OrderStop
+)
+
+// Order is an order submitted by a strategy
+type Order struct {
+	ID    int       `json:"id"`
+	Side  Side      `json:"side"`
+	Type  OrderType `json:"type"`
+	Qty   float64   `json:"qty"`
+	Price float64   `json:"price"` // limit or stop price, unused for market orders
+	Time  time.Time `json:"time"`  // end of the bar the order was submitted on
+}
+
+// Fill is the execution of an order
+type Fill struct {
+	OrderID    int       `json:"orderId"`
+	Side       Side      `json:"side"`
+	Qty        float64   `json:"qty"`
+	Price      float64   `json:"price"`
+	Commission float64   `json:"commission"`
+	Time       time.Time `json:"time"`
+}
+
+// Trade is a round trip from a flat position back to flat, or to a position on the other side
+// A long trade has Side Buy and a short trade Side Sell. PnL is net of commission.
+type Trade struct {
+	Side       Side      `json:"side"`
+	Qty        float64   `json:"qty"` // largest position of the trade
+	EntryTime  time.Time `json:"entryTime"`
+	ExitTime   time.Time `json:"exitTime"`
+	EntryPrice float64   `json:"entryPrice"` // average entry price
+	ExitPrice  float64   `json:"exitPrice"`  // average exit price
+	Commission float64   `json:"commission"`
+	PnL        float64   `json:"pnl"`
+}
+
+// Position is the current position of a backtest
+// Qty is positive for a long position and negative for a short position.
+type Position struct {
+	Qty      float64 `json:"qty"`
+	AvgPrice float64 `json:"avgPrice"`
+}
+
+// BacktestConfig configures a backtest
+type BacktestConfig struct {
+	InitialCash       float64 `json:"initialCash"`
+	CommissionPerUnit float64 `json:"commissionPerUnit"` // commission per unit of quantity filled
+	CommissionRate    float64 `json:"commissionRate"`    // commission as a fraction of the value filled
+	Slippage          float64 `json:"slippage"`          // price slippage per unit on market and stop fills
+	SlippageRate      float64 `json:"slippageRate"`      // price slippage as a fraction of the price on market and stop fills
+	FlattenAtEnd      bool    `json:"flattenAtEnd"`      // cancel open orders and close the position at the close of the last bar
+}
+
+// validate checks the config for negative or NaN values
+func (c BacktestConfig) validate() error {
+	for _, v := range []float64{c.InitialCash, c.CommissionPerUnit, c.CommissionRate, c.Slippage, c.SlippageRate} {
+		if !(v >= 0.0) {
+			return ErrInvalidBacktest
+		}
+	}
+
+	return nil
+}
+
+// Strategy is a trading strategy run by the backtester
+// A strategy typically holds indicator streams, updates them with each bar and submits orders to the broker.
+type Strategy interface {
+	// OnBar is called with each completed bar. Orders submitted can fill from the next bar.
+	OnBar(b Bar, broker *Broker)
+}
+
+// StrategyFunc adapts a function to a Strategy
+type StrategyFunc func(b Bar, broker *Broker)
+
+// OnBar calls f
+func (f StrategyFunc) OnBar(b Bar, broker *Broker) {
+	f(b, broker)
+}
+
+// Broker simulates the orders of a strategy during a backtest
+type Broker struct {
+	config BacktestConfig
+	cash   float64
+	pos    Position
+	orders []Order
+	nextID int
+	bar    Bar // last completed bar
+	fills  []Fill
+	trades []Trade
+	trade  *Trade  // open trade
+	exits  float64 // value of the exits of the open trade
+	exited float64 // quantity of the exits of the open trade
+}
+
+// Submit submits an order and returns its ID
+// Returns ErrInvalidOrder if the quantity is not positive, the side is invalid, or a limit or stop order has no positive price
+func (b *Broker) Submit(o Order) (int, error) {
+	if !(o.Qty > 0.0) || (o.Side != Buy && o.Side != Sell) || (o.Type != OrderMarket && !(o.Price > 0.0)) {
+		return 0, ErrInvalidOrder
+	}
+
+	b.nextID++
+	o.ID = b.nextID
+	o.Time = b.bar.End
+	b.orders = append(b.orders, o)
+
+	return o.ID, nil
+}
+
+// Market submits a market order
+func (b *Broker) Market(side Side, qty float64) (int, error) {
+	return b.Submit(Order{Side: side, Type: OrderMarket, Qty: qty})
+}
+
+// Limit submits a limit order
+func (b *Broker) Limit(side Side, qty float64, price float64) (int, error) {
+	return b.Submit(Order{Side: side, Type: OrderLimit, Qty: qty, Price: price})
+}
+
+// Stop submits a stop order
+func (b *Broker) Stop(side Side, qty float64, price float64) (int, error) {
+	return b.Submit(Order{Side: side, Type: OrderStop, Qty: qty, Price: price})
+}
+
+// Cancel cancels an open order and reports whether it was open
+func (b *Broker) Cancel(id int) bool {
+	for i, o := range b.orders {
+		if o.ID == id {
+			b.orders = append(b.orders[:i], b.orders[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// CancelAll cancels every open order
+func (b *Broker) CancelAll() {
+	b.orders = b.orders[:0]
+}
+
+// OpenOrders returns the open orders in submission order
+func (b *Broker) OpenOrders() []Order {
+	return append([]Order(nil), b.orders...)
+}
+
+// Position returns the current position
+func (b *Broker) Position() Position {
+	return b.pos
+}
+
+// Cash returns the current cash balance
+func (b *Broker) Cash() float64 {
+	return b.cash
+}
+
+// Equity returns the cash balance plus the position marked to the close of the last bar
+func (b *Broker) Equity() float64 {
+	return b.cash + b.pos.Qty*b.bar.Close
+}
+
+// slip returns the price after adverse slippage for the side
+func (b *Broker) slip(side Side, price float64) float64 {
+	return price + float64(side)*(b.config.Slippage+price*b.config.SlippageRate)
+}
+
+// fillPrice returns the price an order fills at in bar, ok false if it does not fill
+func (b *Broker) fillPrice(o Order, bar Bar) (float64, bool) {
+	switch o.Type {
+	case OrderMarket:
+		return b.slip(o.Side, bar.Open), true
+	case OrderLimit:
+		if o.Side == Buy {
+			switch {
+			case bar.Open <= o.Price:
+				return bar.Open, true
+			case bar.Low <= o.Price:
+				return o.Price, true
+			}
+		} else {
+			switch {
+			case bar.Open >= o.Price:
+				return bar.Open, true
+			case bar.High >= o.Price:
+				return o.Price, true
+			}
+		}
+	case OrderStop:
+		if o.Side == Buy {
+			switch {
+			case bar.Open >= o.Price:
+				return b.slip(o.Side, bar.Open), true
+			case bar.High >= o.Price:
+				return b.slip(o.Side, o.Price), true
+			}
+		} else {
+			switch {
+			case bar.Open <= o.Price:
+				return b.slip(o.Side, bar.Open), true
+			case bar.Low <= o.Price:
+				return b.slip(o.Side, o.Price), true
+			}
+		}
+	}
+
+	return 0.0, false
+}
+
+// match fills the open orders that execute in bar, in submission order
+func (b *Broker) match(bar Bar) {
+	open := b.orders[:0]
+
+	for _, o := range b.orders {
+		price, ok := b.fillPrice(o, bar)
+		if !ok {
+			open = append(open, o)
+			continue
+		}
+
+		b.fill(o.ID, o.Side, o.Qty, price, bar.Start)
+	}
+
+	b.orders = open
+}
+
+// fill executes qty at price, updating the cash, position and trades
+func (b *Broker) fill(id int, side Side, qty float64, price float64, t time.Time) {
+	commission := qty*b.config.CommissionPerUnit + qty*price*b.config.CommissionRate
+	b.fills = append(b.fills, Fill{OrderID: id, Side: side, Qty: qty, Price: price, Commission: commission, Time: t})
+
+	b.cash -= float64(side)*qty*price + commission
+
+	signed := float64(side) * qty
+	if b.pos.Qty == 0.0 {
+		b.open(side, qty, price, t)
+	}
+
+	switch {
+	case b.pos.Qty*signed > 0.0: // increase
+		b.trade.Commission += commission
+		total := b.pos.Qty + signed
+		b.pos.AvgPrice = (b.pos.AvgPrice*b.pos.Qty + price*signed) / total
+		b.pos.Qty = total
+		b.trade.EntryPrice = b.pos.AvgPrice
+		b.trade.Qty = math.Max(b.trade.Qty, math.Abs(total))
+	case b.pos.Qty == 0.0: // opened above
+		b.trade.Commission += commission
+		b.pos = Position{Qty: signed, AvgPrice: price}
+	default: // reduce, close or flip
+		// on a flip the commission is shared by the closed and the opened trade in proportion to their quantities
+		closed := math.Min(qty, math.Abs(b.pos.Qty))
+		b.trade.Commission += commission * closed / qty
+		b.trade.PnL += closed * (price - b.pos.AvgPrice) * float64(b.trade.Side)
+		b.exits += closed * price
+		b.exited += closed
+		b.pos.Qty += float64(side) * closed
+
+		if b.pos.Qty == 0.0 {
+			b.close(t)
+		}
+
+		if rest := qty - closed; rest > 0.0 {
+			b.open(side, rest, price, t)
+			b.trade.Commission = commission * rest / qty
+			b.pos = Position{Qty: float64(side) * rest, AvgPrice: price}
+		}
+	}
+}
+
+// open opens a new trade
+func (b *Broker) open(side Side, qty float64, price float64, t time.Time) {
+	b.trade = &Trade{Side: side, Qty: qty, EntryTime: t, EntryPrice: price}
+	b.exits, b.exited = 0.0, 0.0
+}
+
+// close closes the open trade
+func (b *Broker) close(t time.Time) {
+	b.trade.ExitTime = t
+	b.trade.ExitPrice = b.exits / b.exited
+	b.trade.PnL -= b.trade.Commission
+	b.trades = append(b.trades, *b.trade)
+	b.trade = nil
+	b.pos = Position{}
+}
+
+// flatten cancels the open orders and closes the position at the close of the last bar
+func (b *Broker) flatten() {
+	b.CancelAll()
+
+	if b.pos.Qty == 0.0 {
+		return
+	}
+
+	side := Sell
+	if b.pos.Qty < 0.0 {
+		side = Buy
+	}
+
+	b.fill(0, side, math.Abs(b.pos.Qty), b.slip(side, b.bar.Close), b.bar.End)
+}
+
+// BacktestResult is the result of a backtest
+// Equity and Positions have one value per bar, at the end of the bar.
+type BacktestResult struct {
+	Fills     []Fill   `json:"fills"`
+	Trades    []Trade  `json:"trades"`
+	Equity    Series   `json:"equity"`
+	Positions Series   `json:"positions"`
+	Position  Position `json:"position"` // position at the end of the backtest
+}
+
+// Backtest runs a strategy over bars in ascending time order
+// Returns ErrInvalidBacktest if the config has a negative or NaN value
+func Backtest(bars []Bar, s Strategy, c BacktestConfig) (*BacktestResult, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	b := &Broker{config: c, cash: c.InitialCash}
+	res := &BacktestResult{}
+
+	for i, bar := range bars {
+		b.match(bar)
+		b.bar = bar
+
+		s.OnBar(bar, b)
+
+		if c.FlattenAtEnd && i == len(bars)-1 {
+			b.flatten()
+		}
+
+		res.Equity.Times = append(res.Equity.Times, bar.End)
+		res.Equity.Values = append(res.Equity.Values, b.Equity())
+		res.Positions.Times = append(res.Positions.Times, bar.End)
+		res.Positions.Values = append(res.Positions.Values, b.pos.Qty)
+	}
+
+	res.Fills = b.fills
+	res.Trades = b.trades
+	res.Position = b.pos
+
+	return res, nil
+}
+
+// BacktestTicks runs a strategy over ticks in ascending time order
+// Each tick is replayed as a bar of one tick, so orders fill at the price of the next tick.
+func BacktestTicks(ticks []Tick, s Strategy, c BacktestConfig) (*BacktestResult, error) {
+	bars := make([]Bar, len(ticks))
+	for i, t := range ticks {
+		bars[i].add(t)
+	}
+
+	return Backtest(bars, s, c)
+}
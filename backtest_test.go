@@ -0,0 +1,351 @@
+package technical
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockBars returns one minute bars of the per second mock series
+func mockBars() []Bar {
+	series := readMockSeries64("mock/test_series.txt")
+	times := mockSessionTimes(len(series))
+
+	ticks := make([]Tick, len(series))
+	for i, v := range series {
+		ticks[i] = Tick{Time: times[i], Price: v, Size: 1}
+	}
+
+	bars, _ := TimeBars(ticks, time.Minute)
+
+	return bars
+}
+
+// testBars creates bars one minute apart from open, high, low, close quadruples
+func testBars(ohlc ...[4]float64) []Bar {
+	bars := make([]Bar, len(ohlc))
+	for i, p := range ohlc {
+		start := mockSessionOpen.Add(time.Duration(i) * time.Minute)
+		bars[i] = Bar{Start: start, End: start.Add(time.Minute), Open: p[0], High: p[1], Low: p[2], Close: p[3], Ticks: 1}
+	}
+
+	return bars
+}
+
+// scriptStrategy submits the orders of each bar index
+type scriptStrategy map[int][]Order
+
+func (s scriptStrategy) OnBar(b Bar, broker *Broker) {
+	for _, o := range s[int(b.Start.Sub(mockSessionOpen)/time.Minute)] {
+		broker.Submit(o)
+	}
+}
+
+// bollingerReversion buys closes below the lower Bollinger Band and sells at the midpoint
+type bollingerReversion struct {
+	bb  *BollingerEMAStream64
+	qty float64
+}
+
+func (s *bollingerReversion) OnBar(b Bar, broker *Broker) {
+	s.bb.Update(b.Close)
+	if !s.bb.Ready() {
+		return
+	}
+
+	bound := s.bb.Bound()
+	pos := broker.Position().Qty
+
+	switch {
+	case pos == 0.0 && b.Close < bound.Lower:
+		broker.Market(Buy, s.qty)
+	case pos > 0.0 && b.Close >= bound.Midpoint:
+		broker.Market(Sell, pos)
+	}
+}
+
+// atrStop buys closes above an EMA and trails a stop k ATRs below the highest close
+type atrStop struct {
+	ema  *EMAStream64
+	atr  *ATRStream64
+	k    float64
+	qty  float64
+	high float64
+	stop int
+}
+
+func (s *atrStop) OnBar(b Bar, broker *Broker) {
+	s.ema.Update(b.Close)
+	s.atr.UpdateBar(b)
+	if !s.ema.Ready() || !s.atr.Ready() {
+		return
+	}
+
+	if broker.Position().Qty == 0.0 {
+		s.stop = 0
+		if len(broker.OpenOrders()) == 0 && b.Close > s.ema.Value() {
+			broker.Market(Buy, s.qty)
+			s.high = b.Close
+		}
+
+		return
+	}
+
+	// trail the stop
+	s.high = math.Max(s.high, b.Close)
+	broker.Cancel(s.stop)
+	s.stop, _ = broker.Stop(Sell, broker.Position().Qty, s.high-s.k*s.atr.Value())
+}
+
+func TestBacktestConfig(t *testing.T) {
+	_, err := Backtest(nil, scriptStrategy{}, BacktestConfig{InitialCash: -1})
+	assert.Equal(t, ErrInvalidBacktest, err)
+
+	_, err = Backtest(nil, scriptStrategy{}, BacktestConfig{Slippage: math.NaN()})
+	assert.Equal(t, ErrInvalidBacktest, err)
+
+	res, err := Backtest(nil, scriptStrategy{}, BacktestConfig{})
+	assert.Nil(t, err)
+	assert.Empty(t, res.Equity.Values)
+}
+
+func TestBrokerSubmit(t *testing.T) {
+	b := &Broker{}
+
+	_, err := b.Market(Buy, 0)
+	assert.Equal(t, ErrInvalidOrder, err)
+	_, err = b.Limit(Sell, 1, 0)
+	assert.Equal(t, ErrInvalidOrder, err)
+	_, err = b.Submit(Order{Side: 0, Qty: 1})
+	assert.Equal(t, ErrInvalidOrder, err)
+
+	id1, _ := b.Market(Buy, 1)
+	id2, _ := b.Stop(Sell, 1, 9)
+	assert.Equal(t, 1, id1)
+	assert.Equal(t, 2, id2)
+	assert.Len(t, b.OpenOrders(), 2)
+
+	assert.True(t, b.Cancel(id1))
+	assert.False(t, b.Cancel(id1))
+	assert.Equal(t, id2, b.OpenOrders()[0].ID)
+
+	b.CancelAll()
+	assert.Empty(t, b.OpenOrders())
+}
+
+func TestBacktestMarket(t *testing.T) {
+	bars := testBars(
+		[4]float64{10, 11, 9, 10},
+		[4]float64{10.5, 12, 10, 11},
+		[4]float64{11, 13, 11, 12},
+		[4]float64{12.5, 13, 12, 12},
+	)
+
+	s := scriptStrategy{
+		0: {{Side: Buy, Type: OrderMarket, Qty: 10}},
+		2: {{Side: Sell, Type: OrderMarket, Qty: 10}},
+	}
+
+	res, err := Backtest(bars, s, BacktestConfig{InitialCash: 1000, CommissionPerUnit: 0.1, Slippage: 0.05})
+	assert.Nil(t, err)
+
+	// filled at the open of the next bar, with slippage against the order
+	assert.Len(t, res.Fills, 2)
+	assert.Equal(t, bars[1].Start, res.Fills[0].Time)
+	assert.InDelta(t, 10.55, res.Fills[0].Price, 1e-9)
+	assert.InDelta(t, 12.45, res.Fills[1].Price, 1e-9)
+	assert.InDelta(t, 1.0, res.Fills[0].Commission, 1e-9)
+
+	assert.Len(t, res.Trades, 1)
+	tr := res.Trades[0]
+	assert.Equal(t, Buy, tr.Side)
+	assert.Equal(t, 10.0, tr.Qty)
+	assert.Equal(t, bars[3].Start, tr.ExitTime)
+	assert.InDelta(t, 10*(12.45-10.55)-2, tr.PnL, 1e-9)
+
+	// equity marked to the close of each bar
+	assert.Equal(t, []float64{0, 10, 10, 0}, res.Positions.Values)
+	assert.InDelta(t, 1000.0, res.Equity.Values[0], 1e-9)
+	assert.InDelta(t, 1000-105.5-1+110, res.Equity.Values[1], 1e-9)
+	assert.InDelta(t, 1000+tr.PnL, res.Equity.Values[3], 1e-9)
+	assert.Equal(t, bars[3].End, res.Equity.Times[3])
+}
+
+func TestBacktestLimitStop(t *testing.T) {
+	bars := testBars(
+		[4]float64{10, 10, 10, 10},
+		[4]float64{10, 10.5, 9.5, 10},  // buy limit 9.8 fills at 9.8
+		[4]float64{10, 10.2, 9.9, 10},  // sell stop 9.5 does not fill
+		[4]float64{9.0, 9.2, 8.5, 9.0}, // sell stop gaps, fills at the open
+		[4]float64{9.0, 9.5, 8.9, 9.1}, // buy limit 9.2 fills at the open
+		[4]float64{9.3, 9.8, 9.3, 9.5}, // sell limit 9.6 fills at 9.6
+	)
+
+	s := scriptStrategy{
+		0: {{Side: Buy, Type: OrderLimit, Qty: 1, Price: 9.8}},
+		1: {{Side: Sell, Type: OrderStop, Qty: 1, Price: 9.5}},
+		3: {{Side: Buy, Type: OrderLimit, Qty: 2, Price: 9.2}},
+		4: {{Side: Sell, Type: OrderLimit, Qty: 2, Price: 9.6}},
+	}
+
+	res, _ := Backtest(bars, s, BacktestConfig{InitialCash: 100, Slippage: 0.01})
+
+	assert.Len(t, res.Fills, 4)
+	assert.Equal(t, 9.8, res.Fills[0].Price)
+	assert.Equal(t, bars[3].Start, res.Fills[1].Time)
+	assert.InDelta(t, 8.99, res.Fills[1].Price, 1e-9)
+	assert.Equal(t, 9.0, res.Fills[2].Price)
+	assert.Equal(t, 9.6, res.Fills[3].Price)
+
+	assert.Len(t, res.Trades, 2)
+	assert.InDelta(t, 8.99-9.8, res.Trades[0].PnL, 1e-9)
+	assert.InDelta(t, 2*0.6, res.Trades[1].PnL, 1e-9)
+}
+
+func TestBacktestFlip(t *testing.T) {
+	bars := testBars(
+		[4]float64{10, 10, 10, 10},
+		[4]float64{10, 10, 10, 10},
+		[4]float64{12, 12, 12, 12},
+		[4]float64{11, 11, 11, 11},
+		[4]float64{10, 10, 10, 10},
+	)
+
+	s := scriptStrategy{
+		0: {{Side: Buy, Type: OrderMarket, Qty: 1}, {Side: Buy, Type: OrderMarket, Qty: 1}},
+		1: {{Side: Sell, Type: OrderMarket, Qty: 3}},
+	}
+
+	res, _ := Backtest(bars, s, BacktestConfig{InitialCash: 100, FlattenAtEnd: true})
+
+	// long 2 closed at 12, short 1 opened at 12 and flattened at the final close of 10
+	assert.Len(t, res.Trades, 2)
+	assert.Equal(t, Trade{
+		Side: Buy, Qty: 2, EntryTime: bars[1].Start, ExitTime: bars[2].Start, EntryPrice: 10, ExitPrice: 12, PnL: 4,
+	}, res.Trades[0])
+	assert.Equal(t, Trade{
+		Side: Sell, Qty: 1, EntryTime: bars[2].Start, ExitTime: bars[4].End, EntryPrice: 12, ExitPrice: 10, PnL: 2,
+	}, res.Trades[1])
+	assert.Equal(t, Position{}, res.Position)
+	assert.Equal(t, []float64{0, 2, -1, -1, 0}, res.Positions.Values)
+	assert.Equal(t, 106.0, res.Equity.Values[4])
+}
+
+func TestBacktestFlipCommission(t *testing.T) {
+	bars := testBars(
+		[4]float64{10, 10, 10, 10},
+		[4]float64{10, 10, 10, 10},
+		[4]float64{10, 10, 10, 10},
+		[4]float64{9, 9, 9, 9},
+	)
+
+	s := scriptStrategy{
+		0: {{Side: Buy, Type: OrderMarket, Qty: 1}},
+		1: {{Side: Sell, Type: OrderMarket, Qty: 101}},
+	}
+
+	res, _ := Backtest(bars, s, BacktestConfig{InitialCash: 10000, CommissionPerUnit: 1, FlattenAtEnd: true})
+
+	// the sell of 101 costs 101, 1 for the closed long and 100 for the opened short
+	assert.Len(t, res.Trades, 2)
+	assert.Equal(t, 2.0, res.Trades[0].Commission)
+	assert.Equal(t, -2.0, res.Trades[0].PnL)
+
+	// the short pays 100 to enter and 100 to flatten for a gain of 100
+	assert.Equal(t, 200.0, res.Trades[1].Commission)
+	assert.Equal(t, -100.0, res.Trades[1].PnL)
+
+	// the commissions of the trades are the commissions of the fills
+	var fills float64
+	for _, f := range res.Fills {
+		fills += f.Commission
+	}
+
+	assert.Equal(t, fills, res.Trades[0].Commission+res.Trades[1].Commission)
+	assert.Equal(t, 10000+res.Trades[0].PnL+res.Trades[1].PnL, res.Equity.Values[3])
+}
+
+func TestBacktestTicks(t *testing.T) {
+	ticks := mockTicks([]int{0, 1, 2}, []float64{10, 11, 12}, []float64{1, 1, 1})
+
+	s := StrategyFunc(func(b Bar, broker *Broker) {
+		if broker.Position().Qty == 0 {
+			broker.Market(Buy, 1)
+		}
+	})
+
+	res, _ := BacktestTicks(ticks, s, BacktestConfig{InitialCash: 100})
+	assert.Len(t, res.Fills, 1)
+	assert.Equal(t, 11.0, res.Fills[0].Price)
+	assert.Equal(t, []float64{100, 100, 101}, res.Equity.Values)
+}
+
+func TestBacktestDeterministic(t *testing.T) {
+	bars := mockBars()
+
+	run := func() *BacktestResult {
+		bb, _ := NewBollingerEMAStream64(20, 0, 2)
+		res, _ := Backtest(bars, &bollingerReversion{bb: bb, qty: 100}, BacktestConfig{InitialCash: 100000})
+		return res
+	}
+
+	first := run()
+	assert.NotEmpty(t, first.Trades)
+	assert.Equal(t, first, run())
+}
+
+// Example of a Bollinger Band mean reversion strategy on one minute bars of the mock series
+func ExampleBacktest() {
+	bb, _ := NewBollingerEMAStream64(20, 0, 2)
+	s := &bollingerReversion{bb: bb, qty: 100}
+
+	res, _ := Backtest(mockBars(), s, BacktestConfig{
+		InitialCash:       100000,
+		CommissionPerUnit: 0.005,
+		Slippage:          0.01,
+		FlattenAtEnd:      true,
+	})
+
+	pnl := 0.0
+	for _, tr := range res.Trades {
+		pnl += tr.PnL
+	}
+
+	fmt.Printf("trades: %d\n", len(res.Trades))
+	fmt.Printf("pnl: %.2f\n", pnl)
+	fmt.Printf("equity: %.2f\n", res.Equity.Values[len(res.Equity.Values)-1])
+	// Output:
+	// trades: 5
+	// pnl: -235.00
+	// equity: 99765.00
+}
+
+// Example of a trend strategy with an ATR trailing stop on one minute bars of the mock series
+func ExampleBacktest_atrStop() {
+	ema, _ := NewEMAStream64(30, 0)
+	atr, _ := NewATRStream64(14)
+	s := &atrStop{ema: ema, atr: atr, k: 3, qty: 100}
+
+	res, _ := Backtest(mockBars(), s, BacktestConfig{
+		InitialCash:    100000,
+		CommissionRate: 0.0001,
+		SlippageRate:   0.0001,
+		FlattenAtEnd:   true,
+	})
+
+	pnl := 0.0
+	for _, tr := range res.Trades {
+		pnl += tr.PnL
+	}
+
+	fmt.Printf("trades: %d\n", len(res.Trades))
+	fmt.Printf("pnl: %.2f\n", pnl)
+	fmt.Printf("equity: %.2f\n", res.Equity.Values[len(res.Equity.Values)-1])
+	// Output:
+	// trades: 7
+	// pnl: -6.56
+	// equity: 99993.44
+}
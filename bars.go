@@ -0,0 +1,390 @@
+package technical
+
+import (
+	"math"
+	"time"
+)
+
+/*
+* Bars aggregate a raw stream of ticks (trades) into Open, High, Low, Close, Volume (OHLCV) summaries.
+*
+* A bar can be closed by time (time bars), by a number of ticks (tick bars), by traded size (volume bars)
+* or by traded notional value (dollar bars). Aggregators are streaming and emit a bar when it closes,
+* so an indicator fed from an aggregator never sees a bar before it is complete.
+ */
+
+// Tick represents a single trade
+type Tick struct {
+	Time  time.Time `json:"time"`
+	Price float64   `json:"price"`
+	Size  float64   `json:"size"`
+}
+
+// Bar represents an OHLCV bar aggregated from ticks
+// For time bars Start and End are the bounds of the time bucket [Start, End).
+// For all other bars they are the times of the first and last tick of the bar.
+type Bar struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume float64   `json:"volume"`
+	Ticks  int       `json:"ticks"`
+}
+
+// add aggregates the tick into the bar
+func (b *Bar) add(t Tick) {
+	if b.Ticks == 0 {
+		b.Start = t.Time
+		b.Open = t.Price
+		b.High = t.Price
+		b.Low = t.Price
+	}
+
+	b.High = math.Max(b.High, t.Price)
+	b.Low = math.Min(b.Low, t.Price)
+	b.Close = t.Price
+	b.End = t.Time
+	b.Volume += t.Size
+	b.Ticks++
+}
+
+// Range returns the high low range of the bar
+func (b Bar) Range() float64 {
+	return b.High - b.Low
+}
+
+// TypicalPrice returns the average of the high, low and close of the bar
+func (b Bar) TypicalPrice() float64 {
+	return (b.High + b.Low + b.Close) / 3.0
+}
+
+// BarAggregator aggregates a stream of ticks into bars
+type BarAggregator interface {
+	// Update adds the next tick. If the tick closes a bar the closed bar is returned with ok true.
+	Update(t Tick) (bar Bar, ok bool)
+	// Current returns the in progress bar, ok false if no tick has been added since the last bar closed.
+	Current() (bar Bar, ok bool)
+	// Flush closes and returns the in progress bar, ok false if there is none.
+	Flush() (bar Bar, ok bool)
+	// Reset discards the in progress bar.
+	Reset()
+}
+
+// TimeBarAggregator aggregates ticks into bars of a fixed time interval
+// Intervals are aligned to the zero time, i.e. to the clock for intervals that divide a day.
+// Intervals without ticks produce no bar.
+type TimeBarAggregator struct {
+	d   time.Duration
+	cur Bar
+}
+
+// NewTimeBarAggregator creates a TimeBarAggregator with interval d
+// Returns ErrInvalidBarSize if d <= 0
+func NewTimeBarAggregator(d time.Duration) (*TimeBarAggregator, error) {
+	if d <= 0 {
+		return nil, ErrInvalidBarSize
+	}
+
+	return &TimeBarAggregator{d: d}, nil
+}
+
+// Update adds the next tick
+// A tick at or after the end of the in progress bar closes it. Ticks before the start of the in progress bar are ignored.
+func (a *TimeBarAggregator) Update(t Tick) (Bar, bool) {
+	var (
+		closed Bar
+		ok     bool
+	)
+
+	if a.cur.Ticks > 0 {
+		if t.Time.Before(a.cur.Start) {
+			return closed, false
+		}
+
+		if !t.Time.Before(a.cur.End) {
+			closed, ok = a.cur, true
+			a.cur = Bar{}
+		}
+	}
+
+	start := t.Time.Truncate(a.d)
+	a.cur.add(t)
+	a.cur.Start = start
+	a.cur.End = start.Add(a.d)
+
+	return closed, ok
+}
+
+// Current returns the in progress bar
+func (a *TimeBarAggregator) Current() (Bar, bool) {
+	return a.cur, a.cur.Ticks > 0
+}
+
+// Flush closes and returns the in progress bar
+func (a *TimeBarAggregator) Flush() (Bar, bool) {
+	b, ok := a.Current()
+	a.cur = Bar{}
+
+	return b, ok
+}
+
+// Reset discards the in progress bar
+func (a *TimeBarAggregator) Reset() {
+	a.cur = Bar{}
+}
+
+// thresholdBarAggregator closes a bar once a measure of its ticks reaches a threshold
+type thresholdBarAggregator struct {
+	threshold float64
+	measure   func(t Tick) float64
+	total     float64
+	cur       Bar
+}
+
+// Update adds the next tick, the tick that reaches the threshold is included in the closed bar
+func (a *thresholdBarAggregator) Update(t Tick) (Bar, bool) {
+	a.cur.add(t)
+	a.total += a.measure(t)
+
+	if a.total < a.threshold {
+		return Bar{}, false
+	}
+
+	return a.Flush()
+}
+
+// Current returns the in progress bar
+func (a *thresholdBarAggregator) Current() (Bar, bool) {
+	return a.cur, a.cur.Ticks > 0
+}
+
+// Flush closes and returns the in progress bar
+func (a *thresholdBarAggregator) Flush() (Bar, bool) {
+	b, ok := a.Current()
+	a.Reset()
+
+	return b, ok
+}
+
+// Reset discards the in progress bar
+func (a *thresholdBarAggregator) Reset() {
+	a.cur = Bar{}
+	a.total = 0.0
+}
+
+// TickBarAggregator aggregates every n ticks into a bar
+type TickBarAggregator struct {
+	thresholdBarAggregator
+}
+
+// NewTickBarAggregator creates a TickBarAggregator of n ticks per bar
+// Returns ErrInvalidBarSize if n <= 0
+func NewTickBarAggregator(n int) (*TickBarAggregator, error) {
+	if n <= 0 {
+		return nil, ErrInvalidBarSize
+	}
+
+	return &TickBarAggregator{thresholdBarAggregator{
+		threshold: float64(n),
+		measure:   func(Tick) float64 { return 1.0 },
+	}}, nil
+}
+
+// VolumeBarAggregator aggregates ticks into bars of at least v traded size
+// Ticks are not split across bars, so a bar may exceed v.
+type VolumeBarAggregator struct {
+	thresholdBarAggregator
+}
+
+// NewVolumeBarAggregator creates a VolumeBarAggregator of v traded size per bar
+// Returns ErrInvalidBarSize if v <= 0
+func NewVolumeBarAggregator(v float64) (*VolumeBarAggregator, error) {
+	if !(v > 0.0) {
+		return nil, ErrInvalidBarSize
+	}
+
+	return &VolumeBarAggregator{thresholdBarAggregator{
+		threshold: v,
+		measure:   func(t Tick) float64 { return t.Size },
+	}}, nil
+}
+
+// DollarBarAggregator aggregates ticks into bars of at least v traded value (price * size)
+// Ticks are not split across bars, so a bar may exceed v.
+type DollarBarAggregator struct {
+	thresholdBarAggregator
+}
+
+// NewDollarBarAggregator creates a DollarBarAggregator of v traded value per bar
+// Returns ErrInvalidBarSize if v <= 0
+func NewDollarBarAggregator(v float64) (*DollarBarAggregator, error) {
+	if !(v > 0.0) {
+		return nil, ErrInvalidBarSize
+	}
+
+	return &DollarBarAggregator{thresholdBarAggregator{
+		threshold: v,
+		measure:   func(t Tick) float64 { return t.Price * t.Size },
+	}}, nil
+}
+
+// AggregateBars aggregates a list of ticks into bars using the given aggregator
+// Assumes ascending time order
+// The in progress bar after the last tick is flushed and included as the last bar.
+func AggregateBars(ticks []Tick, agg BarAggregator) []Bar {
+	var bars []Bar
+
+	for _, t := range ticks {
+		if b, ok := agg.Update(t); ok {
+			bars = append(bars, b)
+		}
+	}
+
+	if b, ok := agg.Flush(); ok {
+		bars = append(bars, b)
+	}
+
+	return bars
+}
+
+// TimeBars aggregates a list of ticks into bars of time interval d, see TimeBarAggregator
+// Returns ErrInvalidBarSize if d <= 0
+func TimeBars(ticks []Tick, d time.Duration) ([]Bar, error) {
+	agg, err := NewTimeBarAggregator(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return AggregateBars(ticks, agg), nil
+}
+
+// TickBars aggregates a list of ticks into bars of n ticks, see TickBarAggregator
+// Returns ErrInvalidBarSize if n <= 0
+func TickBars(ticks []Tick, n int) ([]Bar, error) {
+	agg, err := NewTickBarAggregator(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return AggregateBars(ticks, agg), nil
+}
+
+// VolumeBars aggregates a list of ticks into bars of v traded size, see VolumeBarAggregator
+// Returns ErrInvalidBarSize if v <= 0
+func VolumeBars(ticks []Tick, v float64) ([]Bar, error) {
+	agg, err := NewVolumeBarAggregator(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return AggregateBars(ticks, agg), nil
+}
+
+// DollarBars aggregates a list of ticks into bars of v traded value, see DollarBarAggregator
+// Returns ErrInvalidBarSize if v <= 0
+func DollarBars(ticks []Tick, v float64) ([]Bar, error) {
+	agg, err := NewDollarBarAggregator(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return AggregateBars(ticks, agg), nil
+}
+
+// SeriesTicks creates a list of ticks from a series of prices and their timestamps
+// Each tick has a size of 1. Returns nil if the lengths of series and times differ.
+func SeriesTicks(series []float64, times []time.Time) []Tick {
+	if len(series) != len(times) {
+		return nil
+	}
+
+	ticks := make([]Tick, len(series))
+	for i, v := range series {
+		ticks[i] = Tick{Time: times[i], Price: v, Size: 1.0}
+	}
+
+	return ticks
+}
+
+// PeriodBars creates a bar of each complete period of s values of a flat series, the periods of StaticATR64
+// Non positive values are gaps as in TrueRange64, and a period of gaps is a flat bar at the prior close
+// without ticks. Each value is a tick of size 1 and the bars have no times. Returns nil if s <= 0.
+func PeriodBars(series []float64, s int) []Bar {
+	if s <= 0 {
+		return nil
+	}
+
+	bars := make([]Bar, 0, len(series)/s)
+	last := 0.0
+	for i := 0; i+s <= len(series); i += s {
+		var b Bar
+		for _, v := range series[i : i+s] {
+			if v > 0.0 {
+				b.add(Tick{Price: v, Size: 1.0})
+			}
+		}
+
+		if b.Ticks == 0 {
+			b.Open, b.High, b.Low, b.Close = last, last, last, last
+		}
+
+		last = b.Close
+		bars = append(bars, b)
+	}
+
+	return bars
+}
+
+// BarOpens returns the open of each bar
+func BarOpens(bars []Bar) []float64 {
+	xs := make([]float64, len(bars))
+	for i, b := range bars {
+		xs[i] = b.Open
+	}
+
+	return xs
+}
+
+// BarHighs returns the high of each bar
+func BarHighs(bars []Bar) []float64 {
+	xs := make([]float64, len(bars))
+	for i, b := range bars {
+		xs[i] = b.High
+	}
+
+	return xs
+}
+
+// BarLows returns the low of each bar
+func BarLows(bars []Bar) []float64 {
+	xs := make([]float64, len(bars))
+	for i, b := range bars {
+		xs[i] = b.Low
+	}
+
+	return xs
+}
+
+// BarCloses returns the close of each bar
+// The closes can be passed directly to the series functions such as EwmaSeries64 or StaticBollingerSMA64.
+func BarCloses(bars []Bar) []float64 {
+	xs := make([]float64, len(bars))
+	for i, b := range bars {
+		xs[i] = b.Close
+	}
+
+	return xs
+}
+
+// BarVolumes returns the volume of each bar
+func BarVolumes(bars []Bar) []float64 {
+	xs := make([]float64, len(bars))
+	for i, b := range bars {
+		xs[i] = b.Volume
+	}
+
+	return xs
+}
@@ -0,0 +1,237 @@
+package technical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockTicks returns ticks at the given second offsets from mockSessionOpen
+func mockTicks(secs []int, prices []float64, sizes []float64) []Tick {
+	ticks := make([]Tick, len(secs))
+	for i, s := range secs {
+		ticks[i] = Tick{
+			Time:  mockSessionOpen.Add(time.Duration(s) * time.Second),
+			Price: prices[i],
+			Size:  sizes[i],
+		}
+	}
+
+	return ticks
+}
+
+func TestBar(t *testing.T) {
+	b := Bar{High: 12, Low: 9, Close: 9}
+	assert.Equal(t, 3.0, b.Range())
+	assert.Equal(t, 10.0, b.TypicalPrice())
+}
+
+func TestTimeBarAggregator(t *testing.T) {
+	_, err := NewTimeBarAggregator(0)
+	assert.Equal(t, ErrInvalidBarSize, err)
+
+	agg, err := NewTimeBarAggregator(time.Minute)
+	assert.NoError(t, err)
+
+	_, ok := agg.Current()
+	assert.False(t, ok)
+
+	ticks := mockTicks(
+		[]int{0, 10, 59, 60, 200},
+		[]float64{10, 12, 9, 11, 13},
+		[]float64{1, 2, 3, 4, 5},
+	)
+
+	for _, tk := range ticks[:3] {
+		_, ok = agg.Update(tk)
+		assert.False(t, ok)
+	}
+
+	cur, ok := agg.Current()
+	assert.True(t, ok)
+	assert.Equal(t, 3, cur.Ticks)
+
+	// closes on the first tick of the next interval
+	b, ok := agg.Update(ticks[3])
+	assert.True(t, ok)
+	assert.Equal(t, Bar{
+		Start:  mockSessionOpen,
+		End:    mockSessionOpen.Add(time.Minute),
+		Open:   10,
+		High:   12,
+		Low:    9,
+		Close:  9,
+		Volume: 6,
+		Ticks:  3,
+	}, b)
+
+	// late tick is ignored
+	_, ok = agg.Update(ticks[0])
+	assert.False(t, ok)
+
+	// empty intervals produce no bars
+	b, ok = agg.Update(ticks[4])
+	assert.True(t, ok)
+	assert.Equal(t, 11.0, b.Close)
+	assert.Equal(t, 1, b.Ticks)
+
+	b, ok = agg.Flush()
+	assert.True(t, ok)
+	assert.Equal(t, mockSessionOpen.Add(3*time.Minute), b.Start)
+	assert.Equal(t, mockSessionOpen.Add(4*time.Minute), b.End)
+
+	_, ok = agg.Flush()
+	assert.False(t, ok)
+
+	agg.Update(ticks[0])
+	agg.Reset()
+	_, ok = agg.Current()
+	assert.False(t, ok)
+}
+
+func TestTickBarAggregator(t *testing.T) {
+	_, err := NewTickBarAggregator(0)
+	assert.Equal(t, ErrInvalidBarSize, err)
+
+	agg, err := NewTickBarAggregator(2)
+	assert.NoError(t, err)
+
+	ticks := mockTicks(
+		[]int{0, 1, 2},
+		[]float64{10, 12, 9},
+		[]float64{1, 1, 1},
+	)
+
+	_, ok := agg.Update(ticks[0])
+	assert.False(t, ok)
+
+	// emits on the tick that completes the bar
+	b, ok := agg.Update(ticks[1])
+	assert.True(t, ok)
+	assert.Equal(t, Bar{
+		Start:  ticks[0].Time,
+		End:    ticks[1].Time,
+		Open:   10,
+		High:   12,
+		Low:    10,
+		Close:  12,
+		Volume: 2,
+		Ticks:  2,
+	}, b)
+
+	_, ok = agg.Current()
+	assert.False(t, ok)
+
+	agg.Update(ticks[2])
+	b, ok = agg.Current()
+	assert.True(t, ok)
+	assert.Equal(t, 9.0, b.Open)
+}
+
+func TestVolumeBarAggregator(t *testing.T) {
+	_, err := NewVolumeBarAggregator(0)
+	assert.Equal(t, ErrInvalidBarSize, err)
+
+	agg, err := NewVolumeBarAggregator(5)
+	assert.NoError(t, err)
+
+	ticks := mockTicks(
+		[]int{0, 1, 2, 3},
+		[]float64{10, 12, 9, 11},
+		[]float64{2, 2, 3, 1},
+	)
+
+	bars := AggregateBars(ticks, agg)
+	assert.Len(t, bars, 2)
+	assert.Equal(t, 7.0, bars[0].Volume)
+	assert.Equal(t, 3, bars[0].Ticks)
+	assert.Equal(t, 1.0, bars[1].Volume)
+}
+
+func TestDollarBarAggregator(t *testing.T) {
+	_, err := NewDollarBarAggregator(-1)
+	assert.Equal(t, ErrInvalidBarSize, err)
+
+	agg, err := NewDollarBarAggregator(30)
+	assert.NoError(t, err)
+
+	ticks := mockTicks(
+		[]int{0, 1, 2, 3},
+		[]float64{10, 12, 9, 11},
+		[]float64{2, 1, 1, 3},
+	)
+
+	// 20, 32 closes; 9, 42 closes
+	bars := AggregateBars(ticks, agg)
+	assert.Len(t, bars, 2)
+	assert.Equal(t, 2, bars[0].Ticks)
+	assert.Equal(t, 12.0, bars[0].Close)
+	assert.Equal(t, 2, bars[1].Ticks)
+	assert.Equal(t, 11.0, bars[1].Close)
+}
+
+func TestBatchBars(t *testing.T) {
+	testseries := readMockSeries64("./mock/test_series.txt")
+	ticks := SeriesTicks(testseries, mockSessionTimes(len(testseries)))
+	assert.Len(t, ticks, len(testseries))
+	assert.Nil(t, SeriesTicks(testseries, nil))
+
+	// one bar per minute of the session
+	bars, err := TimeBars(ticks, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, bars, 390)
+	assert.Equal(t, testseries[59], bars[0].Close)
+	assert.Equal(t, 60.0, bars[0].Volume)
+
+	_, err = TimeBars(ticks, 0)
+	assert.Equal(t, ErrInvalidBarSize, err)
+
+	bars, err = TickBars(ticks, 300)
+	assert.NoError(t, err)
+	assert.Len(t, bars, 78)
+
+	_, err = TickBars(ticks, 0)
+	assert.Equal(t, ErrInvalidBarSize, err)
+
+	bars, err = VolumeBars(ticks, 600)
+	assert.NoError(t, err)
+	assert.Len(t, bars, 39)
+
+	_, err = VolumeBars(ticks, 0)
+	assert.Equal(t, ErrInvalidBarSize, err)
+
+	bars, err = DollarBars(ticks, 1e5)
+	assert.NoError(t, err)
+	assert.True(t, len(bars) > 0)
+
+	_, err = DollarBars(ticks, 0)
+	assert.Equal(t, ErrInvalidBarSize, err)
+}
+
+func TestPeriodBars(t *testing.T) {
+	// a period with a gap, a period of gaps and an incomplete period
+	series := []float64{10, 11, 9, 0, 0, 0, 0, 0, 12, 13, 11, 12, 5}
+
+	assert.Equal(t, []Bar{
+		{Open: 10, High: 11, Low: 9, Close: 9, Volume: 3, Ticks: 3},
+		{Open: 9, High: 9, Low: 9, Close: 9},
+		{Open: 12, High: 13, Low: 11, Close: 12, Volume: 4, Ticks: 4},
+	}, PeriodBars(series, 4))
+
+	assert.Nil(t, PeriodBars(series, 0))
+	assert.Empty(t, PeriodBars(series[:3], 4))
+}
+
+func TestBarFields(t *testing.T) {
+	bars := []Bar{
+		{Open: 1, High: 4, Low: 0.5, Close: 2, Volume: 10},
+		{Open: 2, High: 5, Low: 1.5, Close: 3, Volume: 20},
+	}
+
+	assert.Equal(t, []float64{1, 2}, BarOpens(bars))
+	assert.Equal(t, []float64{4, 5}, BarHighs(bars))
+	assert.Equal(t, []float64{0.5, 1.5}, BarLows(bars))
+	assert.Equal(t, []float64{2, 3}, BarCloses(bars))
+	assert.Equal(t, []float64{10, 20}, BarVolumes(bars))
+}
@@ -121,6 +121,7 @@ func RollingBollingerConst32(period []float32, k float32, a float32) Bound32 {
 // Parameters:
 //		period: list of float values
 //		a (alpha): multiplier on the standard deviation of the period
+// Returns an empty Bound64 for an empty period
 func RollingBollingerSMA64(period []float64, a float64) Bound64 {
 	if period == nil || len(period) == 0 {
 		return Bound64{}
@@ -144,11 +145,12 @@ func RollingBollingerSMA32(period []float32, a float32) Bound32 {
 //
 // Parameters:
 //		period: list of float values
-//		y (lambda): smoothing factor for EWMA
+//		y (lambda): raw smoothing factor for EWMA, see RollingBollingerEMASmoothed64 for a Smoothing
 //		v: current underlying value in the series
 //		last: last EMA midpoint of the prior bound. The first bound midpoint should be computed using a Simple Average
 //		a (alpha): multiplier on the standard deviation of the period
 // CONSTAINT: 0.0 < y < 1.0
+// Returns an empty Bound64 for an empty period
 func RollingBollingerEMA64(period []float64, y float64, v float64, last float64, a float64) Bound64 {
 	var (
 		b Bound64
@@ -189,8 +191,9 @@ func RollingBollingerEMA32(period []float32, y float32, v float32, last float32,
 //		lb: lookback to derive a period
 //		k: static midpoint of the bound for a period
 //		a (alpha): multiplier on the standard deviation of the period
+// Returns nil if the series is empty or lb <= 0. See StaticBollingerConstChecked64 to validate the parameters instead.
 func StaticBollingerConst64(series []float64, lb int, k float64, a float64) []Bound64 {
-	if series == nil || len(series) == 0 {
+	if series == nil || len(series) == 0 || lb <= 0 {
 		return nil
 	}
 
@@ -210,7 +213,7 @@ func StaticBollingerConst64(series []float64, lb int, k float64, a float64) []Bo
 
 // StaticBollingerConst32 is 32 bit version of StaticBollingerConst64
 func StaticBollingerConst32(series []float32, lb int, k float32, a float32) []Bound32 {
-	if series == nil || len(series) == 0 {
+	if series == nil || len(series) == 0 || lb <= 0 {
 		return nil
 	}
 
@@ -236,8 +239,9 @@ func StaticBollingerConst32(series []float32, lb int, k float32, a float32) []Bo
 //		series: data series
 //		lb: lookback to derive a period
 //		a (alpha): multiplier on the standard deviation of the period
+// Returns nil if the series is empty or lb <= 0. See StaticBollingerSMAChecked64 to validate the parameters instead.
 func StaticBollingerSMA64(series []float64, lb int, a float64) []Bound64 {
-	if series == nil || len(series) == 0 {
+	if series == nil || len(series) == 0 || lb <= 0 {
 		return nil
 	}
 
@@ -259,7 +263,7 @@ func StaticBollingerSMA64(series []float64, lb int, a float64) []Bound64 {
 
 // StaticBollingerSMA32 is 32 bit version of StaticBollingerSMA64
 func StaticBollingerSMA32(series []float32, lb int, a float32) []Bound32 {
-	if series == nil || len(series) == 0 {
+	if series == nil || len(series) == 0 || lb <= 0 {
 		return nil
 	}
 
@@ -288,8 +292,10 @@ func StaticBollingerSMA32(series []float32, lb int, a float32) []Bound32 {
 //		lb: lookback to derive a period
 //		y (lambda): smoothing factor for rolling EWMA. If 0.0 use default formulaic calculation.
 //		a (alpha): multiplier on the standard deviation of the period
+// See StaticBollingerEMASmoothed64 to use a Smoothing for y
+// Returns nil if the series is empty or lb <= 0. See StaticBollingerEMAChecked64 to validate the parameters instead.
 func StaticBollingerEMA64(series []float64, lb int, y float64, a float64) []Bound64 {
-	if series == nil || len(series) == 0 {
+	if series == nil || len(series) == 0 || lb <= 0 {
 		return nil
 	}
 
@@ -298,9 +304,7 @@ func StaticBollingerEMA64(series []float64, lb int, y float64, a float64) []Boun
 		last float64
 	)
 
-	if y == 0.0 { // use default smoothing
-		y = 2.0 / float64(lb+1)
-	}
+	y = Smoothing(y).Lambda(lb) // 0.0 uses default smoothing
 
 	band := make([]Bound64, len(series))
 	for i, v := range series {
@@ -328,7 +332,7 @@ func StaticBollingerEMA64(series []float64, lb int, y float64, a float64) []Boun
 
 // StaticBollingerEMA32 is 32 bit version of StaticBollingerEMA64
 func StaticBollingerEMA32(series []float32, lb int, y float32, a float32) []Bound32 {
-	if series == nil || len(series) == 0 {
+	if series == nil || len(series) == 0 || lb <= 0 {
 		return nil
 	}
 
@@ -337,9 +341,7 @@ func StaticBollingerEMA32(series []float32, lb int, y float32, a float32) []Boun
 		last float32
 	)
 
-	if y == 0.0 { // use default smoothing
-		y = 2.0 / float32(lb+1)
-	}
+	y = Smoothing(y).Lambda32(lb) // 0.0 uses default smoothing
 
 	band := make([]Bound32, len(series))
 	for i, v := range series {
@@ -364,3 +366,78 @@ func StaticBollingerEMA32(series []float32, lb int, y float32, a float32) []Boun
 
 	return band
 }
+
+// StaticBollingerConstChecked64 is StaticBollingerConst64 returning an error for invalid parameters
+// Returns ErrEmptySeries, ErrInvalidLookback if lb <= 0 or lb > len(series) and ErrInvalidMultiplier if a < 0
+func StaticBollingerConstChecked64(series []float64, lb int, k float64, a float64) ([]Bound64, error) {
+	if err := checkBollinger(len(series), lb, a); err != nil {
+		return nil, err
+	}
+
+	return StaticBollingerConst64(series, lb, k, a), nil
+}
+
+// StaticBollingerConstChecked32 is 32 bit version of StaticBollingerConstChecked64
+func StaticBollingerConstChecked32(series []float32, lb int, k float32, a float32) ([]Bound32, error) {
+	if err := checkBollinger(len(series), lb, float64(a)); err != nil {
+		return nil, err
+	}
+
+	return StaticBollingerConst32(series, lb, k, a), nil
+}
+
+// StaticBollingerSMAChecked64 is StaticBollingerSMA64 returning an error for invalid parameters
+// Returns ErrEmptySeries, ErrInvalidLookback if lb <= 0 or lb > len(series) and ErrInvalidMultiplier if a < 0
+func StaticBollingerSMAChecked64(series []float64, lb int, a float64) ([]Bound64, error) {
+	if err := checkBollinger(len(series), lb, a); err != nil {
+		return nil, err
+	}
+
+	return StaticBollingerSMA64(series, lb, a), nil
+}
+
+// StaticBollingerSMAChecked32 is 32 bit version of StaticBollingerSMAChecked64
+func StaticBollingerSMAChecked32(series []float32, lb int, a float32) ([]Bound32, error) {
+	if err := checkBollinger(len(series), lb, float64(a)); err != nil {
+		return nil, err
+	}
+
+	return StaticBollingerSMA32(series, lb, a), nil
+}
+
+// StaticBollingerEMAChecked64 is StaticBollingerEMA64 returning an error for invalid parameters
+// Returns ErrEmptySeries, ErrInvalidLookback if lb <= 0 or lb > len(series), ErrInvalidMultiplier if a < 0
+// and ErrInvalidSmoothing if y is not 0.0 (default) and does not satisfy 0 < y < 1
+func StaticBollingerEMAChecked64(series []float64, lb int, y float64, a float64) ([]Bound64, error) {
+	if err := checkBollinger(len(series), lb, a); err != nil {
+		return nil, err
+	}
+
+	if err := Smoothing(y).Validate(); err != nil {
+		return nil, err
+	}
+
+	return StaticBollingerEMA64(series, lb, y, a), nil
+}
+
+// StaticBollingerEMAChecked32 is 32 bit version of StaticBollingerEMAChecked64
+func StaticBollingerEMAChecked32(series []float32, lb int, y float32, a float32) ([]Bound32, error) {
+	if err := checkBollinger(len(series), lb, float64(a)); err != nil {
+		return nil, err
+	}
+
+	if err := Smoothing(y).Validate(); err != nil {
+		return nil, err
+	}
+
+	return StaticBollingerEMA32(series, lb, y, a), nil
+}
+
+// checkBollinger validates the parameters of a static Bollinger Band
+func checkBollinger(size int, lb int, a float64) error {
+	if err := checkSeries(size, lb); err != nil {
+		return err
+	}
+
+	return checkMultiplier(a)
+}
@@ -501,3 +501,66 @@ func TestStaticBollingerEMA32(t *testing.T) {
 	band = StaticBollingerEMA32(testseries, lb, y, 2)
 	assert.Equal(t, float32(39.948578), band[len(band)-1].Midpoint)
 }
+
+func TestStaticBollingerChecked64(t *testing.T) {
+	l := []float64{1, 3, 5, 7, 9, 11, 13}
+
+	_, err := StaticBollingerConstChecked64(nil, 5, 0.0, 2.0)
+	assert.Equal(t, ErrEmptySeries, err)
+
+	_, err = StaticBollingerSMAChecked64(l, 0, 2.0)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	_, err = StaticBollingerSMAChecked64(l, 8, 2.0)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	_, err = StaticBollingerSMAChecked64(l, 5, -2.0)
+	assert.Equal(t, ErrInvalidMultiplier, err)
+
+	_, err = StaticBollingerEMAChecked64(l, 5, 1.0, 2.0)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	band, err := StaticBollingerConstChecked64(l, 5, 0.0, 2.0)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticBollingerConst64(l, 5, 0.0, 2.0), band)
+
+	band, err = StaticBollingerSMAChecked64(l, 5, 2.0)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticBollingerSMA64(l, 5, 2.0), band)
+
+	band, err = StaticBollingerEMAChecked64(l, 5, 0.0, 2.0)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticBollingerEMA64(l, 5, 0.0, 2.0), band)
+
+	// unchecked versions do not panic on an invalid lookback
+	assert.Nil(t, StaticBollingerConst64(l, 0, 0.0, 2.0))
+	assert.Nil(t, StaticBollingerSMA64(l, -1, 2.0))
+	assert.Nil(t, StaticBollingerEMA64(l, -1, 0.0, 2.0))
+}
+
+func TestStaticBollingerChecked32(t *testing.T) {
+	l := []float32{1, 3, 5, 7, 9, 11, 13}
+
+	_, err := StaticBollingerConstChecked32(l, -1, 0.0, 2.0)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	_, err = StaticBollingerSMAChecked32(nil, 5, 2.0)
+	assert.Equal(t, ErrEmptySeries, err)
+
+	_, err = StaticBollingerEMAChecked32(l, 5, 0.0, -2.0)
+	assert.Equal(t, ErrInvalidMultiplier, err)
+
+	band, err := StaticBollingerConstChecked32(l, 5, 0.0, 2.0)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticBollingerConst32(l, 5, 0.0, 2.0), band)
+
+	band, err = StaticBollingerSMAChecked32(l, 5, 2.0)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticBollingerSMA32(l, 5, 2.0), band)
+
+	band, err = StaticBollingerEMAChecked32(l, 5, 0.0, 2.0)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticBollingerEMA32(l, 5, 0.0, 2.0), band)
+
+	assert.Nil(t, StaticBollingerSMA32(l, 0, 2.0))
+}
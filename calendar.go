@@ -0,0 +1,350 @@
+package technical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+/*
+* A trading session calendar describes when a market is open: regular hours on weekdays,
+* early closes (half days) and holidays.
+*
+* Indicators computed over a stream that spans several sessions would otherwise carry their state across
+* the overnight gap. A SessionGuard uses a Calendar to detect session boundaries and resets or gap adjusts
+* the streams it guards.
+ */
+
+const calendarDateLayout = "2006-01-02"
+
+// Session represents a single trading session [Open, Close)
+type Session struct {
+	Open  time.Time `json:"open"`
+	Close time.Time `json:"close"`
+}
+
+// Contains reports whether t is within the session
+func (s Session) Contains(t time.Time) bool {
+	return !t.Before(s.Open) && t.Before(s.Close)
+}
+
+// Calendar is a trading session calendar
+// Sessions are held on weekdays that are not holidays, from Open to Close after midnight in Location
+// or to the early close of the date if it has one.
+type Calendar struct {
+	Location    *time.Location
+	Open        time.Duration // time of day the session opens
+	Close       time.Duration // time of day the session closes
+	holidays    map[string]bool
+	earlyCloses map[string]time.Duration
+}
+
+// NewCalendar creates a Calendar with regular hours from open to close as times of day in loc
+// Returns ErrInvalidSession if open is not before close or either is outside of a day
+func NewCalendar(loc *time.Location, open time.Duration, close time.Duration) (*Calendar, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if open < 0 || close > 24*time.Hour || open >= close {
+		return nil, ErrInvalidSession
+	}
+
+	return &Calendar{
+		Location:    loc,
+		Open:        open,
+		Close:       close,
+		holidays:    make(map[string]bool),
+		earlyCloses: make(map[string]time.Duration),
+	}, nil
+}
+
+// AddHoliday marks the date as a holiday with no session
+func (c *Calendar) AddHoliday(date time.Time) {
+	c.holidays[date.In(c.Location).Format(calendarDateLayout)] = true
+}
+
+// AddEarlyClose marks the date as a half day closing at close as a time of day
+// Returns ErrInvalidSession if close is not after the regular open
+func (c *Calendar) AddEarlyClose(date time.Time, close time.Duration) error {
+	if close <= c.Open || close > 24*time.Hour {
+		return ErrInvalidSession
+	}
+
+	c.earlyCloses[date.In(c.Location).Format(calendarDateLayout)] = close
+
+	return nil
+}
+
+// IsHoliday reports whether the date is a holiday
+func (c *Calendar) IsHoliday(date time.Time) bool {
+	return c.holidays[date.In(c.Location).Format(calendarDateLayout)]
+}
+
+// clock returns the time of day d on the date in the calendar location
+func (c *Calendar) clock(date time.Time, d time.Duration) time.Time {
+	y, m, day := date.Date()
+
+	// the wall clock time is normalized before zone conversion so this is correct across DST changes
+	return time.Date(y, m, day, 0, 0, 0, int(d), c.Location)
+}
+
+// SessionOn returns the session held on the date of t in the calendar location
+// ok is false if there is no session on the date, i.e. a weekend or holiday
+func (c *Calendar) SessionOn(t time.Time) (s Session, ok bool) {
+	date := t.In(c.Location)
+
+	if wd := date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return s, false
+	}
+
+	key := date.Format(calendarDateLayout)
+	if c.holidays[key] {
+		return s, false
+	}
+
+	close := c.Close
+	if ec, ok := c.earlyCloses[key]; ok {
+		close = ec
+	}
+
+	return Session{Open: c.clock(date, c.Open), Close: c.clock(date, close)}, true
+}
+
+// SessionAt returns the session containing t
+// ok is false if t is outside of every session
+func (c *Calendar) SessionAt(t time.Time) (s Session, ok bool) {
+	s, ok = c.SessionOn(t)
+	if !ok || !s.Contains(t) {
+		return Session{}, false
+	}
+
+	return s, true
+}
+
+// NextSession returns the first session that opens after t
+// Searches up to a year ahead, ok is false if there is none.
+func (c *Calendar) NextSession(t time.Time) (s Session, ok bool) {
+	date := t.In(c.Location)
+	for i := 0; i <= 366; i++ {
+		s, ok = c.SessionOn(date.AddDate(0, 0, i))
+		if ok && s.Open.After(t) {
+			return s, true
+		}
+	}
+
+	return Session{}, false
+}
+
+// LoadCalendar reads a Calendar from r
+// The format is one directive per line, blank lines and lines starting with # are ignored:
+//
+//	timezone America/New_York
+//	open 09:30
+//	close 16:00
+//	holiday 2018-07-04
+//	early_close 2018-07-03 13:00
+//
+// timezone, open and close default to UTC, 09:30 and 16:00 and must precede holidays and early closes.
+func LoadCalendar(r io.Reader) (*Calendar, error) {
+	var (
+		cal   *Calendar
+		loc   = time.UTC
+		open  = 9*time.Hour + 30*time.Minute
+		close = 16 * time.Hour
+	)
+
+	// calendar is created on the first holiday or early close, or at the end of the file
+	build := func() error {
+		if cal != nil {
+			return nil
+		}
+
+		var err error
+		cal, err = NewCalendar(loc, open, close)
+
+		return err
+	}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		err := fmt.Errorf("technical: calendar line %d: invalid directive %q", n, line)
+
+		switch {
+		case fields[0] == "timezone" && len(fields) == 2 && cal == nil:
+			l, lerr := time.LoadLocation(fields[1])
+			if lerr != nil {
+				return nil, fmt.Errorf("technical: calendar line %d: %v", n, lerr)
+			}
+
+			loc = l
+		case fields[0] == "open" && len(fields) == 2 && cal == nil:
+			d, perr := parseClock(fields[1])
+			if perr != nil {
+				return nil, err
+			}
+
+			open = d
+		case fields[0] == "close" && len(fields) == 2 && cal == nil:
+			d, perr := parseClock(fields[1])
+			if perr != nil {
+				return nil, err
+			}
+
+			close = d
+		case fields[0] == "holiday" && len(fields) == 2:
+			if berr := build(); berr != nil {
+				return nil, berr
+			}
+
+			date, perr := time.ParseInLocation(calendarDateLayout, fields[1], cal.Location)
+			if perr != nil {
+				return nil, err
+			}
+
+			cal.AddHoliday(date)
+		case fields[0] == "early_close" && len(fields) == 3:
+			if berr := build(); berr != nil {
+				return nil, berr
+			}
+
+			date, perr := time.ParseInLocation(calendarDateLayout, fields[1], cal.Location)
+			if perr != nil {
+				return nil, err
+			}
+
+			d, perr := parseClock(fields[2])
+			if perr != nil {
+				return nil, err
+			}
+
+			if eerr := cal.AddEarlyClose(date, d); eerr != nil {
+				return nil, eerr
+			}
+		default:
+			return nil, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := build(); err != nil {
+		return nil, err
+	}
+
+	return cal, nil
+}
+
+// LoadCalendarFile reads a Calendar from the file at path, see LoadCalendar for the format
+func LoadCalendarFile(path string) (*Calendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return LoadCalendar(f)
+}
+
+// parseClock parses a HH:MM time of day
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// SessionMode determines what a SessionGuard does with its streams at a session boundary
+type SessionMode int
+
+const (
+	// SessionContinue carries the stream state across session boundaries unchanged
+	SessionContinue SessionMode = iota
+	// SessionReset resets the streams at the start of each session
+	SessionReset
+	// SessionGapAdjust shifts the stream state by the gap between the last value
+	// of the prior session and the first value of the new session
+	SessionGapAdjust
+)
+
+// SessionStream is a stream that can be reset or gap adjusted at a session boundary
+type SessionStream interface {
+	Reset()
+	AdjustGap(gap float64)
+}
+
+// SessionGuard detects session boundaries in a stream of timestamped values
+// and resets or gap adjusts the streams it guards according to its mode.
+type SessionGuard struct {
+	cal     *Calendar
+	mode    SessionMode
+	streams []SessionStream
+	cur     Session
+	last    float64
+	started bool
+}
+
+// NewSessionGuard creates a SessionGuard for the given streams
+func NewSessionGuard(cal *Calendar, mode SessionMode, streams ...SessionStream) *SessionGuard {
+	return &SessionGuard{cal: cal, mode: mode, streams: streams}
+}
+
+// Observe must be called with the time and value of every observation before the guarded streams are updated
+// If the observation starts a new session the guarded streams are reset or gap adjusted.
+// Returns false if t is outside of every session, in which case the streams should not be updated.
+func (g *SessionGuard) Observe(t time.Time, v float64) bool {
+	s, ok := g.cal.SessionAt(t)
+	if !ok {
+		return false
+	}
+
+	if g.started && !s.Open.Equal(g.cur.Open) {
+		switch g.mode {
+		case SessionReset:
+			for _, st := range g.streams {
+				st.Reset()
+			}
+		case SessionGapAdjust:
+			for _, st := range g.streams {
+				st.AdjustGap(v - g.last)
+			}
+		}
+	}
+
+	g.cur = s
+	g.last = v
+	g.started = true
+
+	return true
+}
+
+// ObserveBar observes a bar, see Observe
+// The bar is placed in the session by its start and the gap is measured from the last close to its open.
+func (g *SessionGuard) ObserveBar(b Bar) bool {
+	if !g.Observe(b.Start, b.Open) {
+		return false
+	}
+
+	g.last = b.Close
+
+	return true
+}
+
+// Session returns the session of the last observation
+func (g *SessionGuard) Session() (Session, bool) {
+	return g.cur, g.started
+}
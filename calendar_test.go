@@ -0,0 +1,182 @@
+package technical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendar(t *testing.T) {
+	_, err := NewCalendar(time.UTC, 16*time.Hour, 9*time.Hour)
+	assert.Equal(t, ErrInvalidSession, err)
+
+	cal, err := LoadCalendarFile("./mock/test_calendar.txt")
+	assert.NoError(t, err)
+
+	// mock session is a friday
+	s, ok := cal.SessionOn(mockSessionOpen.Add(time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, Session{Open: mockSessionOpen, Close: mockSessionOpen.Add(6*time.Hour + 30*time.Minute)}, s)
+
+	_, ok = cal.SessionAt(mockSessionOpen.Add(-time.Second))
+	assert.False(t, ok)
+	_, ok = cal.SessionAt(s.Close)
+	assert.False(t, ok)
+	s, ok = cal.SessionAt(s.Close.Add(-time.Second))
+	assert.True(t, ok)
+
+	// weekend
+	_, ok = cal.SessionOn(mockSessionOpen.AddDate(0, 0, 1))
+	assert.False(t, ok)
+
+	next, ok := cal.NextSession(mockSessionOpen)
+	assert.True(t, ok)
+	assert.Equal(t, mockSessionOpen.AddDate(0, 0, 3), next.Open)
+
+	// holiday and half day
+	july4 := time.Date(2018, time.July, 4, 12, 0, 0, 0, time.UTC)
+	assert.True(t, cal.IsHoliday(july4))
+	_, ok = cal.SessionOn(july4)
+	assert.False(t, ok)
+
+	s, ok = cal.SessionOn(july4.AddDate(0, 0, -1))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2018, time.July, 3, 13, 0, 0, 0, time.UTC), s.Close)
+
+	next, ok = cal.NextSession(s.Close)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2018, time.July, 5, 9, 30, 0, 0, time.UTC), next.Open)
+
+	assert.Equal(t, ErrInvalidSession, cal.AddEarlyClose(july4, 9*time.Hour))
+}
+
+func TestLoadCalendar(t *testing.T) {
+	cal, err := LoadCalendar(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, cal.Location)
+	assert.Equal(t, 9*time.Hour+30*time.Minute, cal.Open)
+	assert.Equal(t, 16*time.Hour, cal.Close)
+
+	cal, err = LoadCalendar(strings.NewReader("open 08:00\nclose 12:30\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 8*time.Hour, cal.Open)
+	assert.Equal(t, 12*time.Hour+30*time.Minute, cal.Close)
+
+	_, err = LoadCalendar(strings.NewReader("open 8am\n"))
+	assert.Error(t, err)
+
+	_, err = LoadCalendar(strings.NewReader("holiday 2018-07-04\nopen 08:00\n"))
+	assert.Error(t, err)
+
+	_, err = LoadCalendar(strings.NewReader("open 16:00\nclose 09:30\n"))
+	assert.Equal(t, ErrInvalidSession, err)
+
+	_, err = LoadCalendarFile("./mock/missing.txt")
+	assert.Error(t, err)
+}
+
+func TestSessionGuard(t *testing.T) {
+	cal, err := NewCalendar(time.UTC, 9*time.Hour+30*time.Minute, 16*time.Hour)
+	assert.NoError(t, err)
+
+	monday := mockSessionOpen.AddDate(0, 0, 3)
+	times := []time.Time{mockSessionOpen, mockSessionOpen.Add(time.Second), monday, monday.Add(time.Second)}
+	values := []float64{10, 12, 20, 22}
+
+	run := func(mode SessionMode) *EMAStream64 {
+		ema, _ := NewEMAStream64(2, 0)
+		guard := NewSessionGuard(cal, mode, ema)
+
+		// outside of the session
+		assert.False(t, guard.Observe(mockSessionOpen.Add(-time.Minute), 1))
+
+		for i, tm := range times {
+			assert.True(t, guard.Observe(tm, values[i]))
+			ema.Update(values[i])
+		}
+
+		s, ok := guard.Session()
+		assert.True(t, ok)
+		assert.Equal(t, monday, s.Open)
+
+		return ema
+	}
+
+	// continue: 11, then 11 + 2/3 * 9 = 17, 17 + 2/3 * 5
+	assert.InDelta(t, 17.0+10.0/3.0, run(SessionContinue).Value(), 1e-12)
+
+	// reset: average of the new session only
+	assert.Equal(t, 21.0, run(SessionReset).Value())
+
+	// gap adjust: 11 shifted by the gap of 8 to 19, 19 + 2/3 * 1, then + 2/3 * (22 - 19.666..)
+	assert.InDelta(t, 19.0+2.0/3.0+2.0/3.0*(22.0-19.0-2.0/3.0), run(SessionGapAdjust).Value(), 1e-12)
+}
+
+func TestSessionGuardBars(t *testing.T) {
+	cal, err := NewCalendar(time.UTC, 9*time.Hour+30*time.Minute, 16*time.Hour)
+	assert.NoError(t, err)
+
+	monday := mockSessionOpen.AddDate(0, 0, 3)
+	bars := []Bar{
+		{Start: mockSessionOpen, Open: 10, High: 11, Low: 9, Close: 10},
+		{Start: monday, Open: 20, High: 21, Low: 19, Close: 20},
+	}
+
+	atr, _ := NewATRStream64(2)
+	guard := NewSessionGuard(cal, SessionGapAdjust, atr)
+	for _, b := range bars {
+		assert.True(t, guard.ObserveBar(b))
+		atr.UpdateBar(b)
+	}
+
+	// the overnight gap is excluded from the true range
+	assert.Equal(t, 2.0, atr.Value())
+
+	atr.Reset()
+	guard = NewSessionGuard(cal, SessionContinue, atr)
+	for _, b := range bars {
+		guard.ObserveBar(b)
+		atr.UpdateBar(b)
+	}
+
+	assert.Equal(t, 6.5, atr.Value())
+}
+
+func TestAdjustGap(t *testing.T) {
+	series := []float64{1, 3, 2, 5, 4}
+	shifted := make([]float64, len(series))
+	for i, v := range series {
+		shifted[i] = v + 10
+	}
+
+	bb, _ := NewBollingerEMAStream64(3, 0, 2)
+	for _, v := range series {
+		bb.Update(v)
+	}
+
+	bb.AdjustGap(10)
+	bb.Update(6 + 10)
+
+	expected := StaticBollingerEMA64(append(shifted, 16), 3, 0, 2)
+	b := bb.Bound()
+	assert.InDelta(t, expected[len(expected)-1].Midpoint, b.Midpoint, 1e-9)
+	assert.InDelta(t, expected[len(expected)-1].Upper, b.Upper, 1e-9)
+
+	// during warm up
+	ema, _ := NewEMAStream64(3, 0)
+	ema.Update(1)
+	ema.AdjustGap(10)
+	ema.Update(13)
+	ema.Update(12)
+	assert.Equal(t, 12.0, ema.Value())
+
+	tb, _ := NewTimeBollingerStream64(time.Second, 2)
+	tb.Update(mockSessionOpen, 1)
+	tb.Update(mockSessionOpen.Add(time.Second), 3)
+	width := tb.Bound().Upper - tb.Bound().Lower
+	tb.AdjustGap(10)
+	assert.Equal(t, 12.0, tb.Bound().Midpoint)
+	assert.InDelta(t, width, tb.Bound().Upper-tb.Bound().Lower, 1e-12)
+}
@@ -0,0 +1,565 @@
+package technical
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+/*
+* Decimal is a fixed point decimal number for exact arithmetic on prices and amounts.
+*
+* A Decimal is an int64 mantissa scaled by 10^-scale, e.g. 1.005 is the mantissa 1005 at scale 3, so it rounds exactly,
+* where float rounding misrounds: 1.005 * 100 is 100.49999999999999 and 1.1 * 100 is 110.00000000000001,
+* so RoundUp64(1.1, 2) is 1.11 and 1.005 rounds half up to 1.00.
+* Arithmetic is computed on exact integers and returns ErrDecimalOverflow if the result does not fit in an int64 mantissa.
+* It is int64 arithmetic with explicit overflow checks, falling back to math/big only when an intermediate overflows
+* or a mantissa has to be rounded to fewer decimals, so the common same scale operations do not allocate.
+*
+* The float bridge converts Decimals to and from the float64 series used by the indicators,
+* rounding floats by their shortest decimal representation, i.e. 1.005 converts to exactly 1.005.
+ */
+
+// MaxDecimalScale is the largest number of decimals of a Decimal
+const MaxDecimalScale = 18
+
+// RoundingMode is how a Decimal is rounded to fewer decimals
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest, halves away from zero
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest, halves to the even neighbour (banker's rounding)
+	RoundHalfEven
+	// RoundCeil rounds up, towards positive infinity, as RoundUp64
+	RoundCeil
+	// RoundFloor rounds down, towards negative infinity, as RoundDown64
+	RoundFloor
+)
+
+// Decimal is a fixed point decimal number, an int64 mantissa scaled by 10^-scale
+// The zero Decimal is 0 at scale 0.
+type Decimal struct {
+	mantissa int64
+	scale    int
+}
+
+// NewDecimal creates the Decimal mantissa * 10^-scale, e.g. NewDecimal(1005, 3) is 1.005
+// Returns ErrInvalidScale if scale is not in [0, MaxDecimalScale]
+func NewDecimal(mantissa int64, scale int) (Decimal, error) {
+	if err := checkScale(scale); err != nil {
+		return Decimal{}, err
+	}
+
+	return Decimal{mantissa: mantissa, scale: scale}, nil
+}
+
+// checkScale validates the scale of a Decimal
+func checkScale(scale int) error {
+	if scale < 0 || scale > MaxDecimalScale {
+		return ErrInvalidScale
+	}
+
+	return nil
+}
+
+// ParseDecimal parses a decimal string such as "-12.340", keeping its number of decimals as the scale
+// Returns ErrInvalidDecimal for a malformed string or more than MaxDecimalScale decimals, and ErrDecimalOverflow
+func ParseDecimal(s string) (Decimal, error) {
+	m, scale, err := parseDecimalBig(s)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	if scale > MaxDecimalScale {
+		return Decimal{}, errorf(ErrInvalidDecimal, "%q has more than %d decimals", s, MaxDecimalScale)
+	}
+
+	return decimalFromBig(m, scale)
+}
+
+// parseDecimalBig parses a decimal string into an unbounded mantissa and scale
+func parseDecimalBig(s string) (*big.Int, int, error) {
+	digits := s
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		digits = digits[1:]
+	}
+
+	whole, frac := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		whole, frac = digits[:i], digits[i+1:]
+	}
+	if whole == "" && frac == "" {
+		return nil, 0, errorf(ErrInvalidDecimal, "%q", s)
+	}
+
+	for _, c := range whole + frac {
+		if c < '0' || c > '9' {
+			return nil, 0, errorf(ErrInvalidDecimal, "%q", s)
+		}
+	}
+
+	m, _ := new(big.Int).SetString(whole+frac, 10)
+	if strings.HasPrefix(s, "-") {
+		m.Neg(m)
+	}
+
+	return m, len(frac), nil
+}
+
+// DecimalFromFloat64 converts f to a Decimal at scale, rounding its shortest decimal representation with mode
+// e.g. 1.005 at scale 2 with RoundHalfUp is 1.01, where float rounding gives 1.00.
+// Returns ErrInvalidScale, ErrInvalidDecimal for NaN or Inf, and ErrDecimalOverflow
+func DecimalFromFloat64(f float64, scale int, mode RoundingMode) (Decimal, error) {
+	if err := checkScale(scale); err != nil {
+		return Decimal{}, err
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, errorf(ErrInvalidDecimal, "%v", f)
+	}
+
+	m, s, err := parseDecimalBig(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return decimalFromBig(rescaleBig(m, s, scale, mode), scale)
+}
+
+// decimalFromBig creates a Decimal from a mantissa, returning ErrDecimalOverflow if it does not fit in an int64
+func decimalFromBig(m *big.Int, scale int) (Decimal, error) {
+	if !m.IsInt64() {
+		return Decimal{}, ErrDecimalOverflow
+	}
+
+	return Decimal{mantissa: m.Int64(), scale: scale}, nil
+}
+
+// maxInt returns the larger of a and b
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// pow10Int64 holds the powers of ten that fit in an int64, 10^0 to 10^18
+var pow10Int64 = [...]int64{
+	1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000, 1000000000, 10000000000, 100000000000,
+	1000000000000, 10000000000000, 100000000000000, 1000000000000000, 10000000000000000, 100000000000000000,
+	1000000000000000000,
+}
+
+// addInt64 returns a + b, with ok false if it overflows
+func addInt64(a int64, b int64) (sum int64, ok bool) {
+	sum = a + b
+
+	return sum, (b >= 0) == (sum >= a)
+}
+
+// subInt64 returns a - b, with ok false if it overflows
+func subInt64(a int64, b int64) (diff int64, ok bool) {
+	diff = a - b
+
+	return diff, (b >= 0) == (diff <= a)
+}
+
+// mulInt64 returns a * b, with ok false if it overflows
+func mulInt64(a int64, b int64) (prod int64, ok bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	prod = a * b
+	if (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) || prod/b != a {
+		return 0, false
+	}
+
+	return prod, true
+}
+
+// alignInt64 returns the mantissas of d and o at the larger of their scales, with ok false if one overflows
+func alignInt64(d Decimal, o Decimal) (md int64, mo int64, scale int, ok bool) {
+	md, mo, scale, ok = d.mantissa, o.mantissa, d.scale, true
+	switch {
+	case d.scale < o.scale:
+		md, ok = mulInt64(md, pow10Int64[o.scale-d.scale])
+		scale = o.scale
+	case o.scale < d.scale:
+		mo, ok = mulInt64(mo, pow10Int64[d.scale-o.scale])
+	}
+
+	return md, mo, scale, ok
+}
+
+// pow10Big returns 10^n
+func pow10Big(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// divRound divides num by den rounding with mode
+func divRound(num *big.Int, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	sign := int64(num.Sign() * den.Sign())
+
+	switch mode {
+	case RoundCeil:
+		if sign > 0 {
+			q.Add(q, big.NewInt(1))
+		}
+	case RoundFloor:
+		if sign < 0 {
+			q.Sub(q, big.NewInt(1))
+		}
+	default:
+		half := new(big.Int).Abs(r)
+		half.Lsh(half, 1)
+
+		switch c := half.CmpAbs(den); {
+		case c > 0, c == 0 && mode == RoundHalfUp, c == 0 && q.Bit(0) == 1:
+			q.Add(q, big.NewInt(sign))
+		}
+	}
+
+	return q
+}
+
+// rescaleBig converts the mantissa m from scale from to scale to, rounding with mode
+func rescaleBig(m *big.Int, from int, to int, mode RoundingMode) *big.Int {
+	if to >= from {
+		return new(big.Int).Mul(m, pow10Big(to-from))
+	}
+
+	return divRound(m, pow10Big(from-to), mode)
+}
+
+// big returns the mantissa as a big.Int
+func (d Decimal) big() *big.Int {
+	return big.NewInt(d.mantissa)
+}
+
+// Mantissa returns the mantissa of the Decimal
+func (d Decimal) Mantissa() int64 {
+	return d.mantissa
+}
+
+// Scale returns the number of decimals of the Decimal
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+// Sign returns -1, 0 or 1 for a negative, zero or positive Decimal
+func (d Decimal) Sign() int {
+	switch {
+	case d.mantissa < 0:
+		return -1
+	case d.mantissa > 0:
+		return 1
+	}
+
+	return 0
+}
+
+// IsZero reports whether the Decimal is zero at any scale
+func (d Decimal) IsZero() bool {
+	return d.mantissa == 0
+}
+
+// Cmp compares d and o, returning -1 if d < o, 0 if d == o and 1 if d > o, regardless of their scales
+func (d Decimal) Cmp(o Decimal) int {
+	if md, mo, _, ok := alignInt64(d, o); ok {
+		switch {
+		case md < mo:
+			return -1
+		case md > mo:
+			return 1
+		}
+
+		return 0
+	}
+
+	scale := maxInt(d.scale, o.scale)
+
+	return rescaleBig(d.big(), d.scale, scale, RoundHalfUp).Cmp(rescaleBig(o.big(), o.scale, scale, RoundHalfUp))
+}
+
+// Rescale converts d to scale, rounding with mode if scale has fewer decimals
+// Returns ErrInvalidScale and ErrDecimalOverflow
+func (d Decimal) Rescale(scale int, mode RoundingMode) (Decimal, error) {
+	if err := checkScale(scale); err != nil {
+		return Decimal{}, err
+	}
+
+	return decimalFromBig(rescaleBig(d.big(), d.scale, scale, mode), scale)
+}
+
+// Neg returns -d
+// Returns ErrDecimalOverflow for the smallest mantissa
+func (d Decimal) Neg() (Decimal, error) {
+	if d.mantissa != math.MinInt64 {
+		return Decimal{mantissa: -d.mantissa, scale: d.scale}, nil
+	}
+
+	return decimalFromBig(new(big.Int).Neg(d.big()), d.scale)
+}
+
+// Add returns d + o at the larger scale of d and o
+// Returns ErrDecimalOverflow
+func (d Decimal) Add(o Decimal) (Decimal, error) {
+	if md, mo, scale, ok := alignInt64(d, o); ok {
+		if sum, ok := addInt64(md, mo); ok {
+			return Decimal{mantissa: sum, scale: scale}, nil
+		}
+	}
+
+	scale := maxInt(d.scale, o.scale)
+	sum := new(big.Int).Add(rescaleBig(d.big(), d.scale, scale, RoundHalfUp), rescaleBig(o.big(), o.scale, scale, RoundHalfUp))
+
+	return decimalFromBig(sum, scale)
+}
+
+// Sub returns d - o at the larger scale of d and o
+// Returns ErrDecimalOverflow
+func (d Decimal) Sub(o Decimal) (Decimal, error) {
+	if md, mo, scale, ok := alignInt64(d, o); ok {
+		if diff, ok := subInt64(md, mo); ok {
+			return Decimal{mantissa: diff, scale: scale}, nil
+		}
+	}
+
+	scale := maxInt(d.scale, o.scale)
+	diff := new(big.Int).Sub(rescaleBig(d.big(), d.scale, scale, RoundHalfUp), rescaleBig(o.big(), o.scale, scale, RoundHalfUp))
+
+	return decimalFromBig(diff, scale)
+}
+
+// Mul returns d * o exactly at the sum of the scales of d and o
+// A product with more than MaxDecimalScale decimals is rounded to MaxDecimalScale with RoundHalfEven.
+// Returns ErrDecimalOverflow
+func (d Decimal) Mul(o Decimal) (Decimal, error) {
+	scale := d.scale + o.scale
+	if scale <= MaxDecimalScale {
+		if prod, ok := mulInt64(d.mantissa, o.mantissa); ok {
+			return Decimal{mantissa: prod, scale: scale}, nil
+		}
+	}
+
+	prod := new(big.Int).Mul(d.big(), o.big())
+	if scale > MaxDecimalScale {
+		prod = rescaleBig(prod, scale, MaxDecimalScale, RoundHalfEven)
+		scale = MaxDecimalScale
+	}
+
+	return decimalFromBig(prod, scale)
+}
+
+// Div returns d / o at scale, rounding with mode
+// Returns ErrDivisionByZero, ErrInvalidScale and ErrDecimalOverflow
+func (d Decimal) Div(o Decimal, scale int, mode RoundingMode) (Decimal, error) {
+	if err := checkScale(scale); err != nil {
+		return Decimal{}, err
+	}
+
+	if o.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+
+	// d / o = (md / mo) * 10^(so - sd), scaled by 10^scale
+	num := new(big.Int).Mul(d.big(), pow10Big(o.scale+scale))
+	den := new(big.Int).Mul(o.big(), pow10Big(d.scale))
+
+	return decimalFromBig(divRound(num, den, mode), scale)
+}
+
+// Float64 returns the float64 nearest to the Decimal
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns the Decimal with all of its decimals, e.g. "-0.050" at scale 3
+func (d Decimal) String() string {
+	digits := strconv.FormatInt(d.mantissa, 10)
+
+	sign := ""
+	if d.mantissa < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	if d.scale == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= d.scale {
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+}
+
+// MarshalText encodes the Decimal as its String, e.g. in JSON
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a Decimal from a decimal string, see ParseDecimal
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = v
+
+	return nil
+}
+
+// decimalSums returns the count, sum and sum of squares of the mantissas of xs at the largest scale of xs
+func decimalSums(xs []Decimal) (n *big.Int, sum *big.Int, sumSq *big.Int, scale int) {
+	for _, x := range xs {
+		scale = maxInt(scale, x.scale)
+	}
+
+	sum, sumSq = new(big.Int), new(big.Int)
+	for _, x := range xs {
+		m := rescaleBig(x.big(), x.scale, scale, RoundHalfUp)
+		sum.Add(sum, m)
+		sumSq.Add(sumSq, m.Mul(m, m))
+	}
+
+	return big.NewInt(int64(len(xs))), sum, sumSq, scale
+}
+
+// sumInt64 returns the sum of xs on an int64 accumulator, with ok false if a partial sum overflows
+func sumInt64(xs []Decimal) (Decimal, bool) {
+	var sum Decimal
+	for _, x := range xs {
+		md, mo, scale, ok := alignInt64(sum, x)
+		if !ok {
+			return Decimal{}, false
+		}
+
+		if sum.mantissa, ok = addInt64(md, mo); !ok {
+			return Decimal{}, false
+		}
+
+		sum.scale = scale
+	}
+
+	return sum, true
+}
+
+// SumDecimal computes the exact sum of a given list of values at their largest scale
+// Returns the zero Decimal for an empty list and ErrDecimalOverflow
+func SumDecimal(xs []Decimal) (Decimal, error) {
+	if sum, ok := sumInt64(xs); ok {
+		return sum, nil
+	}
+
+	_, sum, _, scale := decimalSums(xs)
+
+	return decimalFromBig(sum, scale)
+}
+
+// SimpleAvgDecimal computes the simple average of a given list of values at scale, rounded with RoundHalfEven
+// The sum is accumulated exactly, so only the final division rounds.
+// Returns the zero Decimal for an empty list, ErrInvalidScale and ErrDecimalOverflow
+func SimpleAvgDecimal(xs []Decimal, scale int) (Decimal, error) {
+	if err := checkScale(scale); err != nil {
+		return Decimal{}, err
+	}
+
+	if len(xs) == 0 {
+		return Decimal{scale: scale}, nil
+	}
+
+	n, sum, _, s := decimalSums(xs)
+
+	return decimalFromBig(divRound(rescaleBig(sum, s, scale, RoundHalfUp), n, RoundHalfEven), scale)
+}
+
+// VarianceDecimal computes the population variance of a given list of values at scale, rounded with RoundHalfEven
+// The variance is (n * sum(x^2) - sum(x)^2) / n^2 computed on exact integer accumulators, so only the final division rounds.
+// Returns the zero Decimal for an empty list, ErrInvalidScale and ErrDecimalOverflow
+func VarianceDecimal(xs []Decimal, scale int) (Decimal, error) {
+	if err := checkScale(scale); err != nil {
+		return Decimal{}, err
+	}
+
+	if len(xs) == 0 {
+		return Decimal{scale: scale}, nil
+	}
+
+	return decimalFromBig(varianceBig(xs, scale), scale)
+}
+
+// varianceBig returns the mantissa of the population variance of xs at scale
+func varianceBig(xs []Decimal, scale int) *big.Int {
+	n, sum, sumSq, s := decimalSums(xs)
+
+	num := new(big.Int).Mul(n, sumSq)
+	num.Sub(num, new(big.Int).Mul(sum, sum))
+	num.Mul(num, pow10Big(scale))
+
+	den := new(big.Int).Mul(n, n)
+	den.Mul(den, pow10Big(2*s))
+
+	return divRound(num, den, RoundHalfEven)
+}
+
+// StdDevDecimal computes the population standard deviation of a given list of values at scale
+// The square root is computed on integers and rounded to the nearest.
+// Returns the zero Decimal for an empty list, ErrInvalidScale and ErrDecimalOverflow
+func StdDevDecimal(xs []Decimal, scale int) (Decimal, error) {
+	if err := checkScale(scale); err != nil {
+		return Decimal{}, err
+	}
+
+	if len(xs) == 0 {
+		return Decimal{scale: scale}, nil
+	}
+
+	// the root of the variance at twice the scale is the standard deviation at scale
+	v := varianceBig(xs, 2*scale)
+	r := new(big.Int).Sqrt(v)
+
+	// round to the nearest, r + 1 if v > r^2 + r
+	if lim := new(big.Int).Mul(r, r); lim.Add(lim, r).Cmp(v) < 0 {
+		r.Add(r, big.NewInt(1))
+	}
+
+	return decimalFromBig(r, scale)
+}
+
+// DecimalsToFloat64 converts a list of Decimals to a float64 series for the indicator functions
+func DecimalsToFloat64(xs []Decimal) []float64 {
+	series := make([]float64, len(xs))
+	for i, x := range xs {
+		series[i] = x.Float64()
+	}
+
+	return series
+}
+
+// DecimalsFromFloat64 converts a float64 series, e.g. an indicator output, to Decimals at scale, see DecimalFromFloat64
+// Returns the first error of a conversion
+func DecimalsFromFloat64(series []float64, scale int, mode RoundingMode) ([]Decimal, error) {
+	xs := make([]Decimal, len(series))
+	for i, v := range series {
+		x, err := DecimalFromFloat64(v, scale, mode)
+		if err != nil {
+			return nil, err
+		}
+
+		xs[i] = x
+	}
+
+	return xs, nil
+}
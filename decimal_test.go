@@ -0,0 +1,314 @@
+package technical
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dec parses a decimal string for tests
+func dec(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// decs parses decimal strings for tests
+func decs(ss ...string) []Decimal {
+	xs := make([]Decimal, len(ss))
+	for i, s := range ss {
+		xs[i] = dec(s)
+	}
+
+	return xs
+}
+
+func TestNewDecimal(t *testing.T) {
+	d, err := NewDecimal(1005, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.005", d.String())
+	assert.Equal(t, int64(1005), d.Mantissa())
+	assert.Equal(t, 3, d.Scale())
+
+	_, err = NewDecimal(1, 19)
+	assert.Equal(t, ErrInvalidScale, err)
+	_, err = NewDecimal(1, -1)
+	assert.Equal(t, ErrInvalidScale, err)
+
+	assert.Equal(t, "0", Decimal{}.String())
+}
+
+func TestParseDecimal(t *testing.T) {
+	for s, want := range map[string]string{
+		"1.005":   "1.005",
+		"-12.340": "-12.340",
+		"+7":      "7",
+		"0.05":    "0.05",
+		"-0.001":  "-0.001",
+		".5":      "0.5",
+		"3.":      "3",
+	} {
+		d, err := ParseDecimal(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, want, d.String(), s)
+	}
+
+	for _, s := range []string{"", ".", "-", "1.2.3", "1e3", "abc", "0.1234567890123456789"} {
+		_, err := ParseDecimal(s)
+		assert.Equal(t, ErrInvalidDecimal, Cause(err), s)
+	}
+
+	_, err := ParseDecimal("99999999999999999999")
+	assert.Equal(t, ErrDecimalOverflow, err)
+}
+
+func TestDecimalFromFloat64(t *testing.T) {
+	// float rounding misrounds 1.005, it is 100.49999999999999 cents
+	f := 1.005
+	assert.Equal(t, 1.0, math.Round(f*100)/100)
+
+	d, err := DecimalFromFloat64(1.005, 2, RoundHalfUp)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.01", d.String())
+
+	d, _ = DecimalFromFloat64(1.005, 2, RoundHalfEven)
+	assert.Equal(t, "1.00", d.String())
+	d, _ = DecimalFromFloat64(1.001, 2, RoundCeil)
+	assert.Equal(t, "1.01", d.String())
+	d, _ = DecimalFromFloat64(-1.001, 2, RoundCeil)
+	assert.Equal(t, "-1.00", d.String())
+	d, _ = DecimalFromFloat64(-1.001, 2, RoundFloor)
+	assert.Equal(t, "-1.01", d.String())
+	d, _ = DecimalFromFloat64(0.1+0.2, 8, RoundHalfUp)
+	assert.Equal(t, "0.30000000", d.String())
+	d, _ = DecimalFromFloat64(1e-30, 4, RoundHalfUp)
+	assert.Equal(t, "0.0000", d.String())
+
+	_, err = DecimalFromFloat64(math.NaN(), 2, RoundHalfUp)
+	assert.Equal(t, ErrInvalidDecimal, Cause(err))
+	_, err = DecimalFromFloat64(1, 20, RoundHalfUp)
+	assert.Equal(t, ErrInvalidScale, err)
+	_, err = DecimalFromFloat64(1e20, 2, RoundHalfUp)
+	assert.Equal(t, ErrDecimalOverflow, err)
+
+	assert.Equal(t, 1.005, dec("1.005").Float64())
+	assert.Equal(t, -0.3, dec("-0.30").Float64())
+}
+
+func TestDecimalRound(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		mode RoundingMode
+		want string
+	}{
+		{"2.345", RoundHalfUp, "2.35"},
+		{"-2.345", RoundHalfUp, "-2.35"},
+		{"2.345", RoundHalfEven, "2.34"},
+		{"2.355", RoundHalfEven, "2.36"},
+		{"-2.345", RoundHalfEven, "-2.34"},
+		{"2.341", RoundCeil, "2.35"},
+		{"-2.349", RoundCeil, "-2.34"},
+		{"2.349", RoundFloor, "2.34"},
+		{"-2.341", RoundFloor, "-2.35"},
+		{"2.340", RoundCeil, "2.34"},
+	} {
+		d, err := dec(c.in).Rescale(2, c.mode)
+		assert.Nil(t, err)
+		assert.Equal(t, c.want, d.String(), "%s %d", c.in, c.mode)
+	}
+
+	d, _ := dec("1.5").Rescale(4, RoundHalfUp)
+	assert.Equal(t, "1.5000", d.String())
+
+	_, err := dec("1.5").Rescale(19, RoundHalfUp)
+	assert.Equal(t, ErrInvalidScale, err)
+	_, err = dec("9223372036854775807").Rescale(1, RoundHalfUp)
+	assert.Equal(t, ErrDecimalOverflow, err)
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := dec("10.25"), dec("0.125")
+
+	sum, _ := a.Add(b)
+	assert.Equal(t, "10.375", sum.String())
+
+	diff, _ := b.Sub(a)
+	assert.Equal(t, "-10.125", diff.String())
+
+	prod, _ := a.Mul(b)
+	assert.Equal(t, "1.28125", prod.String())
+
+	quo, _ := a.Div(b, 4, RoundHalfUp)
+	assert.Equal(t, "82.0000", quo.String())
+
+	third, _ := dec("1").Div(dec("3"), 6, RoundHalfUp)
+	assert.Equal(t, "0.333333", third.String())
+	twoThirds, _ := dec("-2").Div(dec("3"), 2, RoundHalfUp)
+	assert.Equal(t, "-0.67", twoThirds.String())
+
+	neg, _ := a.Neg()
+	assert.Equal(t, "-10.25", neg.String())
+
+	// 0.1 + 0.2 is exactly 0.3
+	sum, _ = dec("0.1").Add(dec("0.2"))
+	assert.Equal(t, 0, sum.Cmp(dec("0.3")))
+	assert.Equal(t, 0, dec("0.30").Cmp(dec("0.3")))
+	assert.Equal(t, -1, dec("0.29").Cmp(dec("0.3")))
+	assert.Equal(t, 1, dec("1").Cmp(dec("0.999")))
+
+	assert.Equal(t, -1, neg.Sign())
+	assert.Equal(t, 0, dec("0.00").Sign())
+	assert.True(t, dec("0.00").IsZero())
+
+	// products beyond the max scale are rounded
+	small := dec("0.000000001")
+	prod, _ = small.Mul(dec("0.0000000015"))
+	assert.Equal(t, MaxDecimalScale, prod.Scale())
+	assert.Equal(t, int64(2), prod.Mantissa())
+
+	_, err := a.Div(Decimal{}, 2, RoundHalfUp)
+	assert.Equal(t, ErrDivisionByZero, err)
+	_, err = dec("9223372036854775807").Add(dec("1"))
+	assert.Equal(t, ErrDecimalOverflow, err)
+	_, err = dec("-9223372036854775808").Neg()
+	assert.Equal(t, ErrDecimalOverflow, err)
+	_, err = dec("9223372036854775807").Mul(dec("2"))
+	assert.Equal(t, ErrDecimalOverflow, err)
+}
+
+func TestDecimalInt64(t *testing.T) {
+	// results at the edges of the int64 mantissa stay exact
+	hi, lo := dec("9223372036854775807"), dec("-9223372036854775808")
+	sum, err := hi.Add(lo)
+	assert.NoError(t, err)
+	assert.Equal(t, "-1", sum.String())
+	diff, err := lo.Sub(dec("-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(math.MinInt64+1), diff.Mantissa())
+	_, err = lo.Sub(dec("1"))
+	assert.Equal(t, ErrDecimalOverflow, err)
+	_, err = hi.Sub(dec("-1"))
+	assert.Equal(t, ErrDecimalOverflow, err)
+	_, err = lo.Mul(dec("-1"))
+	assert.Equal(t, ErrDecimalOverflow, err)
+	assert.Equal(t, 1, hi.Cmp(lo))
+
+	// rescaling that overflows an int64 falls back to exact big integers
+	assert.Equal(t, 1, hi.Cmp(dec("0.000000000000000001")))
+	assert.Equal(t, -1, lo.Cmp(dec("-0.5")))
+	_, err = dec("-922337203685477580.7").Add(dec("0.000000000000000001"))
+	assert.Equal(t, ErrDecimalOverflow, err)
+	sum, err = dec("10").Add(dec("-1.000000000000000000"))
+	assert.NoError(t, err)
+	assert.Equal(t, "9.000000000000000000", sum.String())
+
+	// a partial sum can overflow when the total does not
+	total, err := SumDecimal([]Decimal{hi, dec("1"), dec("-2")})
+	assert.NoError(t, err)
+	assert.Equal(t, "9223372036854775806", total.String())
+
+	// same scale arithmetic does not allocate
+	a, b := dec("101.25"), dec("0.75")
+	allocs := testing.AllocsPerRun(100, func() {
+		a.Add(b)
+		a.Sub(b)
+		a.Mul(b)
+		a.Cmp(b)
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+func TestDecimalJSON(t *testing.T) {
+	type order struct {
+		Price Decimal `json:"price"`
+	}
+
+	b, err := json.Marshal(order{Price: dec("101.250")})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"price":"101.250"}`, string(b))
+
+	var o order
+	assert.Nil(t, json.Unmarshal(b, &o))
+	assert.Equal(t, dec("101.250"), o.Price)
+
+	assert.Equal(t, ErrInvalidDecimal, Cause(json.Unmarshal([]byte(`{"price":"x"}`), &o)))
+}
+
+func TestDecimalStats(t *testing.T) {
+	xs := decs("1.10", "2.20", "3.3", "4.40")
+
+	sum, _ := SumDecimal(xs)
+	assert.Equal(t, "11.00", sum.String())
+
+	avg, _ := SimpleAvgDecimal(xs, 3)
+	assert.Equal(t, "2.750", avg.String())
+
+	// population variance 1.5125
+	v, _ := VarianceDecimal(xs, 4)
+	assert.Equal(t, "1.5125", v.String())
+	v, _ = VarianceDecimal(xs, 2)
+	assert.Equal(t, "1.51", v.String())
+
+	sd, _ := StdDevDecimal(xs, 6)
+	assert.Equal(t, "1.229837", sd.String())
+	assert.InDelta(t, StdDev64(DecimalsToFloat64(xs)), sd.Float64(), 1e-6)
+
+	// halves round to even
+	avg, _ = SimpleAvgDecimal(decs("0.01", "0.02"), 2)
+	assert.Equal(t, "0.02", avg.String())
+	avg, _ = SimpleAvgDecimal(decs("0.01", "0.04"), 2)
+	assert.Equal(t, "0.02", avg.String())
+
+	empty, _ := SimpleAvgDecimal(nil, 2)
+	assert.Equal(t, "0.00", empty.String())
+	empty, _ = VarianceDecimal(nil, 2)
+	assert.True(t, empty.IsZero())
+	empty, _ = StdDevDecimal(nil, 2)
+	assert.True(t, empty.IsZero())
+	empty, _ = SumDecimal(nil)
+	assert.Equal(t, Decimal{}, empty)
+
+	_, err := SimpleAvgDecimal(xs, -1)
+	assert.Equal(t, ErrInvalidScale, err)
+	_, err = VarianceDecimal(xs, 19)
+	assert.Equal(t, ErrInvalidScale, err)
+	_, err = StdDevDecimal(xs, 19)
+	assert.Equal(t, ErrInvalidScale, err)
+}
+
+func TestDecimalBridge(t *testing.T) {
+	series := readMockSeries64("mock/test_series.txt")[:1200]
+
+	xs, err := DecimalsFromFloat64(series, 2, RoundHalfUp)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, series, DecimalsToFloat64(xs), 0.005)
+
+	avg, _ := SimpleAvgDecimal(xs, 6)
+	assert.InDelta(t, SimpleAvg64(DecimalsToFloat64(xs)), avg.Float64(), 1e-6)
+
+	v, _ := VarianceDecimal(xs, 8)
+	assert.InDelta(t, Variance64(DecimalsToFloat64(xs)), v.Float64(), 1e-8)
+
+	// feed the float indicators and convert their output back
+	ema := EwmaSeries64(DecimalsToFloat64(xs), 0, 20)
+	out, err := DecimalsFromFloat64(ema, 4, RoundHalfEven)
+	assert.Nil(t, err)
+	assert.Len(t, out, len(ema))
+
+	_, err = DecimalsFromFloat64([]float64{1, math.Inf(1)}, 2, RoundHalfUp)
+	assert.Equal(t, ErrInvalidDecimal, Cause(err))
+}
+
+func TestDecimalRoundUp(t *testing.T) {
+	// RoundUp64 misrounds values on the grid, 1.1 * 100 is 110.00000000000001
+	assert.Equal(t, 1.11, RoundUp64(1.1, 2))
+
+	d, _ := DecimalFromFloat64(1.1, 2, RoundCeil)
+	assert.Equal(t, "1.10", d.String())
+	assert.Equal(t, 1.1, d.Float64())
+}
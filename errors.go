@@ -0,0 +1,153 @@
+package technical
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrEmptySeries is returned when a series or period has no values
+	ErrEmptySeries = errors.New("technical: empty series")
+
+	// ErrLengthMismatch is returned when paired series, such as values and their timestamps, differ in length
+	ErrLengthMismatch = errors.New("technical: series lengths do not match")
+
+	// ErrUnsortedSeries is returned when the timestamps of a series are not in ascending order
+	ErrUnsortedSeries = errors.New("technical: series timestamps are not in ascending order")
+
+	// ErrInvalidLookback is returned when a lookback is <= 0 or longer than the series
+	ErrInvalidLookback = errors.New("technical: invalid lookback, must satisfy 0 < lb <= len(series)")
+
+	// ErrInvalidSmoothing is returned when a smoothing factor does not satisfy 0 < y < 1
+	ErrInvalidSmoothing = errors.New("technical: invalid smoothing factor, must satisfy 0 < y < 1")
+
+	// ErrInvalidMultiplier is returned when a standard deviation or standard error multiplier is negative or NaN
+	ErrInvalidMultiplier = errors.New("technical: invalid multiplier, must satisfy a >= 0")
+
+	// ErrInvalidPeriods is returned when a number of periods (n) is <= 0
+	ErrInvalidPeriods = errors.New("technical: invalid number of periods, must satisfy n > 0")
+
+	// ErrInvalidPeriodSize is returned when a period size (s) is <= 0
+	ErrInvalidPeriodSize = errors.New("technical: invalid period size, must satisfy s > 0")
+
+	// ErrInvalidHalfLife is returned when a half life is <= 0
+	ErrInvalidHalfLife = errors.New("technical: invalid half life, must satisfy halfLife > 0")
+
+	// ErrInvalidBarSize is returned when a bar interval, tick count, volume or value is <= 0
+	ErrInvalidBarSize = errors.New("technical: invalid bar size, must be > 0")
+
+	// ErrInvalidSession is returned when a session does not open before it closes within a day
+	ErrInvalidSession = errors.New("technical: invalid session, must satisfy 0 <= open < close <= 24h")
+
+	// ErrInvalidTimeframe is returned when a timeframe has no name or aggregator, a nil indicator or a duplicate name
+	ErrInvalidTimeframe = errors.New("technical: invalid timeframe, must have a unique name and an aggregator")
+
+	// ErrUnknownIndicator is returned when an indicator name is not registered
+	ErrUnknownIndicator = errors.New("technical: unknown indicator")
+
+	// ErrInvalidParam is returned when an indicator parameter is unknown, of the wrong type or out of range
+	ErrInvalidParam = errors.New("technical: invalid indicator parameter")
+
+	// ErrInvalidSpec is returned when an indicator spec has no name or constructor, or its name is already registered
+	ErrInvalidSpec = errors.New("technical: invalid indicator spec")
+
+	// ErrInvalidNode is returned when a pipeline node does not exist or is not of the required kind
+	ErrInvalidNode = errors.New("technical: invalid pipeline node")
+
+	// ErrInvalidExpr is returned when an expression has a syntax error or calls a function with the wrong arguments
+	ErrInvalidExpr = errors.New("technical: invalid expression")
+
+	// ErrInvalidHysteresis is returned when an event detector hysteresis or tolerance is negative or NaN
+	ErrInvalidHysteresis = errors.New("technical: invalid hysteresis, must satisfy h >= 0")
+
+	// ErrInvalidBacktest is returned when a backtest cash, commission or slippage is negative or NaN
+	ErrInvalidBacktest = errors.New("technical: invalid backtest config, cash, commission and slippage must be >= 0")
+
+	// ErrInvalidOrder is returned when an order has a non positive quantity, an invalid side or a non positive limit or stop price
+	ErrInvalidOrder = errors.New("technical: invalid order")
+
+	// ErrInvalidConfidence is returned when a VaR or Expected Shortfall confidence level does not satisfy 0 < c < 1
+	ErrInvalidConfidence = errors.New("technical: invalid confidence, must satisfy 0 < c < 1")
+
+	// ErrInvalidMethod is returned when a VaR or Expected Shortfall method is unknown
+	ErrInvalidMethod = errors.New("technical: invalid method")
+
+	// ErrInvalidTickSize is returned when a tick size is negative, NaN or not representable as a fraction
+	ErrInvalidTickSize = errors.New("technical: invalid tick size")
+
+	// ErrInvalidPrice is returned when a price to count in ticks is NaN, infinite or more than 2^53 ticks
+	ErrInvalidPrice = errors.New("technical: invalid price, must be finite and at most 2^53 ticks")
+
+	// ErrInvalidScale is returned when a Decimal scale does not satisfy 0 <= scale <= MaxDecimalScale
+	ErrInvalidScale = errors.New("technical: invalid decimal scale, must satisfy 0 <= scale <= 18")
+
+	// ErrInvalidDecimal is returned when a string or float cannot be converted to a Decimal
+	ErrInvalidDecimal = errors.New("technical: invalid decimal")
+
+	// ErrDecimalOverflow is returned when the result of a Decimal operation does not fit in an int64 mantissa
+	ErrDecimalOverflow = errors.New("technical: decimal overflow")
+
+	// ErrDivisionByZero is returned when a Decimal is divided by zero
+	ErrDivisionByZero = errors.New("technical: division by zero")
+
+	// ErrInvalidAcceleration is returned when a Parabolic SAR acceleration step or maximum does not satisfy 0 < step <= max
+	ErrInvalidAcceleration = errors.New("technical: invalid acceleration, must satisfy 0 < step <= max")
+
+	// ErrInvalidAnchor is returned when an anchored VWAP is re-anchored before its current anchor
+	ErrInvalidAnchor = errors.New("technical: invalid anchor, must not precede the current anchor")
+
+	// ErrMissingData is returned by a MissingPolicy with mode MissingError when a value is missing
+	ErrMissingData = errors.New("technical: missing data")
+)
+
+// detailError is a sentinel error with the details of an occurrence, e.g. the name of an invalid parameter
+type detailError struct {
+	err    error
+	detail string
+}
+
+// errorf returns the sentinel error err with the formatted details
+func errorf(err error, format string, args ...interface{}) error {
+	return &detailError{err: err, detail: fmt.Sprintf(format, args...)}
+}
+
+func (e *detailError) Error() string {
+	return e.err.Error() + ": " + e.detail
+}
+
+// Unwrap returns the sentinel error, so errors.Is matches it on Go 1.13 and later
+func (e *detailError) Unwrap() error {
+	return e.err
+}
+
+// Cause returns the sentinel error of err, e.g. ErrInvalidParam of an invalid parameter error with its details
+// Errors without details are returned as is, so Cause(err) == ErrInvalidParam holds for any such error.
+func Cause(err error) error {
+	if e, ok := err.(*detailError); ok {
+		return e.err
+	}
+
+	return err
+}
+
+// checkSeries validates a series of size values with a lookback lb
+func checkSeries(size int, lb int) error {
+	if size == 0 {
+		return ErrEmptySeries
+	}
+
+	if lb <= 0 || lb > size {
+		return ErrInvalidLookback
+	}
+
+	return nil
+}
+
+// checkMultiplier validates a standard deviation or standard error multiplier a
+func checkMultiplier(a float64) error {
+	if !(a >= 0.0) {
+		return ErrInvalidMultiplier
+	}
+
+	return nil
+}
@@ -0,0 +1,352 @@
+package technical
+
+import (
+	"time"
+)
+
+/*
+* Event detectors turn indicator values into discrete events: when one series crosses another,
+* e.g. a MACD line and its signal line or a price and its EMA, and when a series touches, breaks out of,
+* re-enters or walks along a band such as a Bollinger Band.
+*
+* Detectors take a hysteresis h so a series oscillating around a level does not emit an event on every value:
+* after a crossover the series must move more than h past the level in the opposite direction to cross back.
+ */
+
+// EventType is the type of a detected event
+type EventType int
+
+const (
+	// EventCrossAbove is emitted when a series crosses above another series or level
+	EventCrossAbove EventType = iota
+	// EventCrossBelow is emitted when a series crosses below another series or level
+	EventCrossBelow
+	// EventTouchUpper is emitted when a series inside a band reaches its upper bound
+	EventTouchUpper
+	// EventTouchLower is emitted when a series inside a band reaches its lower bound
+	EventTouchLower
+	// EventBreakAbove is emitted when a series moves above the upper bound of a band
+	EventBreakAbove
+	// EventBreakBelow is emitted when a series moves below the lower bound of a band
+	EventBreakBelow
+	// EventReenterFromAbove is emitted when a series above a band moves back inside it
+	EventReenterFromAbove
+	// EventReenterFromBelow is emitted when a series below a band moves back inside it
+	EventReenterFromBelow
+	// EventWalkUpper is emitted when a series has been at or above the upper bound of a band for a number of consecutive values
+	EventWalkUpper
+	// EventWalkLower is emitted when a series has been at or below the lower bound of a band for a number of consecutive values
+	EventWalkLower
+	// EventReversalUp is emitted when a trend indicator such as the Parabolic SAR reverses from a down trend to an up trend
+	EventReversalUp
+	// EventReversalDown is emitted when a trend indicator such as the Parabolic SAR reverses from an up trend to a down trend
+	EventReversalDown
+)
+
+var eventTypeNames = [...]string{
+	"cross_above",
+	"cross_below",
+	"touch_upper",
+	"touch_lower",
+	"break_above",
+	"break_below",
+	"reenter_from_above",
+	"reenter_from_below",
+	"walk_upper",
+	"walk_lower",
+	"reversal_up",
+	"reversal_down",
+}
+
+// String returns the name of the event type
+func (t EventType) String() string {
+	if t < 0 || int(t) >= len(eventTypeNames) {
+		return "unknown"
+	}
+
+	return eventTypeNames[t]
+}
+
+// MarshalText encodes the event type as its name, e.g. in JSON
+func (t EventType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// Event is a detected event
+// Index is the index of the value in its series or stream. Time is zero for events of untimed values.
+type Event struct {
+	Type  EventType `json:"type"`
+	Index int       `json:"index"`
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"` // value of the series
+	Level float64   `json:"level"` // value of the level or bound the event is relative to
+}
+
+// StampEvents sets the time of each event from the timestamps of the series it was detected on
+// Events with an index outside of times are left unchanged.
+func StampEvents(events []Event, times []time.Time) {
+	for i := range events {
+		if j := events[i].Index; j >= 0 && j < len(times) {
+			events[i].Time = times[j]
+		}
+	}
+}
+
+// crossState is the side of a level a series is on
+type crossState int
+
+const (
+	crossUnknown crossState = iota
+	crossAbove
+	crossBelow
+)
+
+// CrossDetector64 detects crossovers of a stream of values over a stream of levels
+type CrossDetector64 struct {
+	h     float64
+	state crossState
+	n     int
+}
+
+// NewCrossDetector64 creates a CrossDetector64 with hysteresis h
+// A crossover above requires the value to exceed the level by more than h, and a crossover below to be under it by more than h.
+// Returns ErrInvalidHysteresis if h < 0
+func NewCrossDetector64(h float64) (*CrossDetector64, error) {
+	if !(h >= 0.0) {
+		return nil, ErrInvalidHysteresis
+	}
+
+	return &CrossDetector64{h: h}, nil
+}
+
+// Update adds the next value v and level and returns the crossover event if v crossed the level
+// The first side of the level the values are on does not count as a crossover.
+func (d *CrossDetector64) Update(v float64, level float64) (Event, bool) {
+	return d.UpdateAt(time.Time{}, v, level)
+}
+
+// UpdateAt is Update for a value and level observed at time t
+func (d *CrossDetector64) UpdateAt(t time.Time, v float64, level float64) (Event, bool) {
+	var (
+		e  = Event{Index: d.n, Time: t, Value: v, Level: level}
+		ok bool
+	)
+
+	d.n++
+
+	switch diff := v - level; {
+	case diff > d.h && d.state != crossAbove:
+		e.Type, ok = EventCrossAbove, d.state == crossBelow
+		d.state = crossAbove
+	case diff < -d.h && d.state != crossBelow:
+		e.Type, ok = EventCrossBelow, d.state == crossAbove
+		d.state = crossBelow
+	}
+
+	return e, ok
+}
+
+// Reset clears the detector
+func (d *CrossDetector64) Reset() {
+	d.state, d.n = crossUnknown, 0
+}
+
+// Crossovers64 detects the crossovers of series a over series b with hysteresis h, see CrossDetector64
+// Returns nil if the lengths of a and b differ or h < 0
+func Crossovers64(a []float64, b []float64, h float64) []Event {
+	d, err := NewCrossDetector64(h)
+	if err != nil || len(a) != len(b) {
+		return nil
+	}
+
+	var events []Event
+	for i, v := range a {
+		if e, ok := d.Update(v, b[i]); ok {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+// Crossovers32 is 32 bit version of Crossovers64
+func Crossovers32(a []float32, b []float32, h float32) []Event {
+	if len(a) != len(b) {
+		return nil
+	}
+
+	a64 := make([]float64, len(a))
+	b64 := make([]float64, len(b))
+	for i := range a {
+		a64[i], b64[i] = float64(a[i]), float64(b[i])
+	}
+
+	return Crossovers64(a64, b64, float64(h))
+}
+
+// LevelCrossovers64 detects the crossovers of a series over a constant level with hysteresis h, see CrossDetector64
+// Returns nil if h < 0
+func LevelCrossovers64(series []float64, level float64, h float64) []Event {
+	levels := make([]float64, len(series))
+	for i := range levels {
+		levels[i] = level
+	}
+
+	return Crossovers64(series, levels, h)
+}
+
+// BandDetector64 detects band events of a stream of values and bounds, such as a Bollinger Band
+// Empty bounds, i.e. before a band is ready, are ignored.
+type BandDetector64 struct {
+	h        float64
+	tol      float64
+	walk     int
+	state    crossState // crossAbove above the band, crossBelow below it, crossUnknown inside it
+	touching crossState // bound the value is touching while inside the band
+	upper    int        // consecutive values at or above the upper bound
+	lower    int        // consecutive values at or below the lower bound
+	n        int
+}
+
+// NewBandDetector64 creates a BandDetector64
+// Returns ErrInvalidHysteresis if h < 0 or tol < 0, and ErrInvalidPeriods if walk < 0
+//
+// Parameters:
+//
+//	h: hysteresis, a break requires the value to be more than h beyond a bound and a re-entry more than h inside it
+//	tol: tolerance, a value within tol of a bound touches it
+//	walk: number of consecutive values at or beyond a bound to walk the band, 0 to not detect walks
+func NewBandDetector64(h float64, tol float64, walk int) (*BandDetector64, error) {
+	if !(h >= 0.0) || !(tol >= 0.0) {
+		return nil, ErrInvalidHysteresis
+	}
+
+	if walk < 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	return &BandDetector64{h: h, tol: tol, walk: walk}, nil
+}
+
+// Update adds the next value v and bound b and returns the events of the value in the order they occurred
+func (d *BandDetector64) Update(v float64, b Bound64) []Event {
+	return d.UpdateAt(time.Time{}, v, b)
+}
+
+// UpdateAt is Update for a value and bound observed at time t
+func (d *BandDetector64) UpdateAt(t time.Time, v float64, b Bound64) []Event {
+	i := d.n
+	d.n++
+
+	if b == (Bound64{}) {
+		return nil
+	}
+
+	var events []Event
+	emit := func(typ EventType, level float64) {
+		events = append(events, Event{Type: typ, Index: i, Time: t, Value: v, Level: level})
+	}
+
+	// re-entries before breaks, so a move from above to below the band re-enters then breaks
+	switch {
+	case d.state == crossAbove && v < b.Upper-d.h:
+		emit(EventReenterFromAbove, b.Upper)
+		d.state = crossUnknown
+	case d.state == crossBelow && v > b.Lower+d.h:
+		emit(EventReenterFromBelow, b.Lower)
+		d.state = crossUnknown
+	}
+
+	switch {
+	case d.state != crossAbove && v > b.Upper+d.h:
+		emit(EventBreakAbove, b.Upper)
+		d.state, d.touching = crossAbove, crossUnknown
+	case d.state != crossBelow && v < b.Lower-d.h:
+		emit(EventBreakBelow, b.Lower)
+		d.state, d.touching = crossBelow, crossUnknown
+	}
+
+	// touches while inside the band, once until the value moves away from the bound
+	if d.state == crossUnknown {
+		switch {
+		case v >= b.Upper-d.tol:
+			if d.touching != crossAbove {
+				emit(EventTouchUpper, b.Upper)
+			}
+
+			d.touching = crossAbove
+		case v <= b.Lower+d.tol:
+			if d.touching != crossBelow {
+				emit(EventTouchLower, b.Lower)
+			}
+
+			d.touching = crossBelow
+		default:
+			d.touching = crossUnknown
+		}
+	}
+
+	// walks count every value at or beyond a bound, inside or outside the band, and are emitted once per walk
+	if v >= b.Upper-d.tol {
+		d.upper++
+	} else {
+		d.upper = 0
+	}
+
+	if v <= b.Lower+d.tol {
+		d.lower++
+	} else {
+		d.lower = 0
+	}
+
+	if d.walk > 0 && d.upper == d.walk {
+		emit(EventWalkUpper, b.Upper)
+	}
+
+	if d.walk > 0 && d.lower == d.walk {
+		emit(EventWalkLower, b.Lower)
+	}
+
+	return events
+}
+
+// Reset clears the detector
+func (d *BandDetector64) Reset() {
+	*d = BandDetector64{h: d.h, tol: d.tol, walk: d.walk}
+}
+
+// BandEvents64 detects the band events of a series and its bounds, see BandDetector64
+// Returns nil if the lengths of series and bounds differ or the parameters are invalid
+func BandEvents64(series []float64, bounds []Bound64, h float64, tol float64, walk int) []Event {
+	d, err := NewBandDetector64(h, tol, walk)
+	if err != nil || len(series) != len(bounds) {
+		return nil
+	}
+
+	var events []Event
+	for i, v := range series {
+		events = append(events, d.Update(v, bounds[i])...)
+	}
+
+	return events
+}
+
+// BandEvents32 is 32 bit version of BandEvents64
+func BandEvents32(series []float32, bounds []Bound32, h float32, tol float32, walk int) []Event {
+	if len(series) != len(bounds) {
+		return nil
+	}
+
+	series64 := make([]float64, len(series))
+	bounds64 := make([]Bound64, len(bounds))
+	for i, v := range series {
+		series64[i] = float64(v)
+		bounds64[i] = Bound64{
+			Lower:    float64(bounds[i].Lower),
+			Midpoint: float64(bounds[i].Midpoint),
+			Upper:    float64(bounds[i].Upper),
+		}
+	}
+
+	return BandEvents64(series64, bounds64, float64(h), float64(tol), walk)
+}
@@ -0,0 +1,180 @@
+package technical
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventType(t *testing.T) {
+	assert.Equal(t, "cross_above", EventCrossAbove.String())
+	assert.Equal(t, "walk_lower", EventWalkLower.String())
+	assert.Equal(t, "reversal_down", EventReversalDown.String())
+	assert.Equal(t, "unknown", EventType(99).String())
+
+	data, err := json.Marshal(Event{Type: EventBreakAbove, Index: 3})
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"type":"break_above"`)
+}
+
+func TestCrossovers64(t *testing.T) {
+	events := Crossovers64([]float64{1, 2, 3, 2, 1}, []float64{2, 2, 2, 2, 2}, 0)
+	assert.Equal(t, []Event{
+		{Type: EventCrossAbove, Index: 2, Value: 3, Level: 2},
+		{Type: EventCrossBelow, Index: 4, Value: 1, Level: 2},
+	}, events)
+
+	// hysteresis suppresses flapping around the level
+	series := []float64{0, 1.1, 0.9, 1.1, 0.9, 2.5, 0.9, -0.5}
+	assert.Len(t, LevelCrossovers64(series, 1, 0), 6)
+
+	events = LevelCrossovers64(series, 1, 0.5)
+	assert.Len(t, events, 2)
+	assert.Equal(t, EventCrossAbove, events[0].Type)
+	assert.Equal(t, 5, events[0].Index)
+	assert.Equal(t, EventCrossBelow, events[1].Type)
+	assert.Equal(t, 7, events[1].Index)
+
+	assert.Nil(t, Crossovers64([]float64{1}, nil, 0))
+	assert.Nil(t, Crossovers64([]float64{1}, []float64{1}, -1))
+
+	assert.Equal(t, Crossovers64([]float64{1, 2, 3, 2, 1}, []float64{2, 2, 2, 2, 2}, 0),
+		Crossovers32([]float32{1, 2, 3, 2, 1}, []float32{2, 2, 2, 2, 2}, 0))
+	assert.Nil(t, Crossovers32([]float32{1}, nil, 0))
+}
+
+func TestCrossoversMACD(t *testing.T) {
+	testseries := readMockSeries64("./mock/test_series.txt")
+	times := mockSessionTimes(len(testseries))
+
+	p := NewPipeline(nil)
+	macd, sig, _, _ := p.MACD(120, 260, 90, PipelineInput)
+
+	var macds, signals []float64
+	for _, v := range testseries {
+		p.Update(v)
+		if p.Ready(sig) {
+			macds = append(macds, p.Value(macd))
+			signals = append(signals, p.Value(sig))
+		}
+	}
+
+	events := Crossovers64(macds, signals, 0.001)
+	assert.True(t, len(events) > 0)
+
+	// crossovers alternate
+	for i := 1; i < len(events); i++ {
+		assert.NotEqual(t, events[i-1].Type, events[i].Type)
+	}
+
+	offset := len(testseries) - len(macds)
+	StampEvents(events, times[offset:])
+	assert.Equal(t, times[offset+events[0].Index], events[0].Time)
+}
+
+func TestCrossDetector64(t *testing.T) {
+	_, err := NewCrossDetector64(-1)
+	assert.Equal(t, ErrInvalidHysteresis, err)
+
+	d, err := NewCrossDetector64(0)
+	assert.NoError(t, err)
+
+	_, ok := d.UpdateAt(mockSessionOpen, 1, 2)
+	assert.False(t, ok)
+
+	e, ok := d.UpdateAt(mockSessionOpen.Add(time.Second), 3, 2)
+	assert.True(t, ok)
+	assert.Equal(t, Event{Type: EventCrossAbove, Index: 1, Time: mockSessionOpen.Add(time.Second), Value: 3, Level: 2}, e)
+
+	d.Reset()
+	_, ok = d.Update(1, 2)
+	assert.False(t, ok)
+}
+
+func TestBandEvents64(t *testing.T) {
+	b := Bound64{Lower: 9, Midpoint: 10, Upper: 11}
+	series := []float64{0, 10, 10.95, 11, 11.5, 12, 11.8, 10.5, 9, 8, 10}
+	bounds := make([]Bound64, len(series))
+	for i := 1; i < len(bounds); i++ {
+		bounds[i] = b
+	}
+
+	events := BandEvents64(series, bounds, 0.2, 0.1, 3)
+
+	types := make([]EventType, len(events))
+	indices := make([]int, len(events))
+	for i, e := range events {
+		types[i], indices[i] = e.Type, e.Index
+	}
+
+	assert.Equal(t, []EventType{
+		EventTouchUpper,
+		EventBreakAbove,
+		EventWalkUpper,
+		EventReenterFromAbove,
+		EventTouchLower,
+		EventBreakBelow,
+		EventReenterFromBelow,
+	}, types)
+	assert.Equal(t, []int{2, 4, 4, 7, 8, 9, 10}, indices)
+	assert.Equal(t, 11.0, events[1].Level)
+
+	// straight through the band re-enters then breaks
+	events = BandEvents64([]float64{12, 8}, []Bound64{b, b}, 0, 0, 0)
+	assert.Len(t, events, 3)
+	assert.Equal(t, EventReenterFromAbove, events[1].Type)
+	assert.Equal(t, EventBreakBelow, events[2].Type)
+
+	assert.Nil(t, BandEvents64(series, nil, 0, 0, 0))
+	assert.Nil(t, BandEvents64(series, bounds, -1, 0, 0))
+
+	b32 := Bound32{Lower: 9, Midpoint: 10, Upper: 11}
+	assert.Len(t, BandEvents32([]float32{12, 8}, []Bound32{b32, b32}, 0, 0, 0), 3)
+	assert.Nil(t, BandEvents32([]float32{12}, nil, 0, 0, 0))
+}
+
+func TestBandDetector64(t *testing.T) {
+	_, err := NewBandDetector64(0, -1, 0)
+	assert.Equal(t, ErrInvalidHysteresis, err)
+	_, err = NewBandDetector64(0, 0, -1)
+	assert.Equal(t, ErrInvalidPeriods, err)
+
+	testseries := readMockSeries64("./mock/test_series.txt")
+	bounds := StaticBollingerSMA64(testseries, 1200, 2)
+
+	d, err := NewBandDetector64(0, 0, 0)
+	assert.NoError(t, err)
+
+	var events []Event
+	for i, v := range testseries {
+		events = append(events, d.UpdateAt(mockSessionOpen.Add(time.Duration(i)*time.Second), v, bounds[i])...)
+	}
+
+	assert.True(t, len(events) > 0)
+	assert.Equal(t, mockSessionOpen.Add(time.Duration(events[0].Index)*time.Second), events[0].Time)
+
+	// same events as the series form without timestamps
+	for i := range events {
+		events[i].Time = time.Time{}
+	}
+
+	assert.Equal(t, BandEvents64(testseries, bounds, 0, 0, 0), events)
+
+	// breaks and re-entries alternate
+	var last EventType = -1
+	for _, e := range events {
+		switch e.Type {
+		case EventBreakAbove, EventBreakBelow:
+			assert.NotEqual(t, EventBreakAbove, last)
+			assert.NotEqual(t, EventBreakBelow, last)
+			last = e.Type
+		case EventReenterFromAbove, EventReenterFromBelow:
+			last = e.Type
+		}
+	}
+
+	d.Reset()
+	assert.Nil(t, d.Update(1, Bound64{}))
+}
@@ -0,0 +1,829 @@
+package technical
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+* A small expression language for defining indicators and signals in config, e.g.
+*
+*	close > boll_upper(close, 20, 2.0) && rsi(close, 14) > 70
+*
+* Expressions support arithmetic (+ - * /), comparisons (< <= > >= == !=), logic (&& || !),
+* crossovers (cross_above, cross_below), lookback indexing (x[n] is x n values ago) and the indicators
+* of the package. Comparisons and logic produce 1.0 for true and 0.0 for false.
+*
+* Expressions compile to Pipeline nodes, so they are evaluated in O(1) per value (a lag x[n] keeps n values
+* in a ring buffer) and share identical sub-expressions and indicators with everything else compiled into the same pipeline.
+ */
+
+// exprFunc describes a function of the expression language
+// Indicator functions take a series as the first argument and constant parameters after it.
+type exprFunc struct {
+	series   int       // number of series arguments
+	params   []string  // names of the constant parameters
+	defaults []float64 // defaults of trailing parameters that can be omitted
+	compile  func(p *Pipeline, in []Node, params []float64) (Node, error)
+}
+
+// exprIndicator compiles to the named registry indicator with positional params
+func exprIndicator(name string, params []string, defaults []float64) exprFunc {
+	return exprFunc{
+		series:   1,
+		params:   params,
+		defaults: defaults,
+		compile: func(p *Pipeline, in []Node, args []float64) (Node, error) {
+			ps := make(Params, len(args))
+			for i, v := range args {
+				ps[params[i]] = v
+			}
+
+			return p.Build(name, ps, in[0])
+		},
+	}
+}
+
+// exprBand compiles to the upper (side 1) or lower (side -1) bound or the midpoint (side 0) of a Bollinger Band
+func exprBand(side int) exprFunc {
+	ind := exprIndicator("bollinger_ema", []string{"lb", "a", "smoothing"}, []float64{2, 0})
+	build := ind.compile
+
+	ind.compile = func(p *Pipeline, in []Node, args []float64) (Node, error) {
+		n, err := build(p, in, args)
+		if err != nil || side == 0 {
+			return n, err
+		}
+
+		upper, lower, err := p.Band(n)
+		if side > 0 {
+			return upper, err
+		}
+
+		return lower, err
+	}
+
+	return ind
+}
+
+// exprMACD compiles to the MACD line (0), signal line (1) or histogram (2)
+func exprMACD(line int) exprFunc {
+	return exprFunc{
+		series:   1,
+		params:   []string{"fast", "slow", "signal"},
+		defaults: []float64{12, 26, 9},
+		compile: func(p *Pipeline, in []Node, args []float64) (Node, error) {
+			if err := checkIntArgs(args...); err != nil {
+				return 0, err
+			}
+
+			macd, sig, hist, err := p.MACD(int(args[0]), int(args[1]), int(args[2]), in[0])
+
+			return [3]Node{macd, sig, hist}[line], err
+		},
+	}
+}
+
+// exprKeltner compiles to the upper (side 1) or lower (side -1) bound or the midpoint (side 0) of a Keltner Channel
+func exprKeltner(side int) exprFunc {
+	return exprFunc{
+		series:   1,
+		params:   []string{"lb", "n", "k"},
+		defaults: []float64{2},
+		compile: func(p *Pipeline, in []Node, args []float64) (Node, error) {
+			if err := checkIntArgs(args[:2]...); err != nil {
+				return 0, err
+			}
+
+			mid, upper, lower, err := p.Keltner(int(args[0]), int(args[1]), args[2], in[0])
+
+			return [3]Node{lower, mid, upper}[side+1], err
+		},
+	}
+}
+
+// exprCombine compiles to a function of series arguments
+func exprCombine(series int, f func(xs []float64) float64) exprFunc {
+	return exprFunc{
+		series: series,
+		compile: func(p *Pipeline, in []Node, _ []float64) (Node, error) {
+			return p.Combine(f, in...)
+		},
+	}
+}
+
+// exprCross compiles to a crossover of a above b (dir 1), below b (dir -1) or either (dir 0)
+// A crossover is true on the value where the sign of a - b changes from the previous value.
+func exprCross(dir int) exprFunc {
+	return exprFunc{
+		series: 2,
+		compile: func(p *Pipeline, in []Node, _ []float64) (Node, error) {
+			d, err := p.Diff(in[0], in[1])
+			if err != nil {
+				return 0, err
+			}
+
+			prev, err := p.lag(d, 1)
+			if err != nil {
+				return 0, err
+			}
+
+			return p.Combine(func(xs []float64) float64 {
+				above := xs[0] > 0.0 && xs[1] <= 0.0
+				below := xs[0] < 0.0 && xs[1] >= 0.0
+
+				return exprBool((dir >= 0 && above) || (dir <= 0 && below))
+			}, d, prev)
+		},
+	}
+}
+
+// checkIntArgs validates that the args are integers of at most MaxLookback in magnitude
+func checkIntArgs(args ...float64) error {
+	for _, v := range args {
+		if v != math.Trunc(v) || math.Abs(v) > MaxLookback {
+			return errorf(ErrInvalidParam, "expected an integer, got %v", v)
+		}
+	}
+
+	return nil
+}
+
+var exprFuncs = map[string]exprFunc{
+	"ema":           exprIndicator("ema", []string{"lb", "smoothing"}, []float64{0}),
+	"ewma":          exprIndicator("ema", []string{"lb", "smoothing"}, []float64{0}),
+	"boll":          exprBand(0),
+	"boll_upper":    exprBand(1),
+	"boll_lower":    exprBand(-1),
+	"atr":           exprIndicator("atr", []string{"n"}, nil),
+	"rsi":           exprIndicator("rsi", []string{"n"}, nil),
+	"zscore":        exprIndicator("zscore", []string{"lb"}, nil),
+	"linreg":        exprIndicator("linreg", []string{"lb"}, nil),
+	"momentum":      exprIndicator("momentum", []string{"n"}, nil),
+	"roc":           exprIndicator("roc", []string{"n"}, nil),
+	"trix":          exprIndicator("trix", []string{"lb"}, nil),
+	"ppo":           exprIndicator("ppo", []string{"fast", "slow"}, nil),
+	"cmo":           exprIndicator("cmo", []string{"n"}, nil),
+	"cci":           exprIndicator("cci", []string{"n"}, nil),
+	"williams_r":    exprIndicator("williams_r", []string{"n"}, nil),
+	"aroon":         exprIndicator("aroon", []string{"n"}, nil),
+	"ultimate":      exprIndicator("ultimate", []string{"short", "medium", "long"}, []float64{7, 14, 28}),
+	"psar":          exprIndicator("psar", []string{"step", "max"}, []float64{DefaultSARStep, DefaultSARMax}),
+	"supertrend":    exprIndicator("supertrend", []string{"n", "k"}, []float64{10, 3}),
+	"macd":          exprMACD(0),
+	"macd_signal":   exprMACD(1),
+	"macd_hist":     exprMACD(2),
+	"keltner":       exprKeltner(0),
+	"keltner_upper": exprKeltner(1),
+	"keltner_lower": exprKeltner(-1),
+	"cross_above":   exprCross(1),
+	"cross_below":   exprCross(-1),
+	"cross":         exprCross(0),
+	"abs":           exprCombine(1, func(xs []float64) float64 { return math.Abs(xs[0]) }),
+	"min":           exprCombine(2, func(xs []float64) float64 { return math.Min(xs[0], xs[1]) }),
+	"max":           exprCombine(2, func(xs []float64) float64 { return math.Max(xs[0], xs[1]) }),
+}
+
+// exprInputs are the names of the values fed to the pipeline
+var exprInputs = map[string]bool{"close": true, "price": true, "value": true}
+
+// exprBool converts a boolean to 1.0 or 0.0
+func exprBool(b bool) float64 {
+	if b {
+		return 1.0
+	}
+
+	return 0.0
+}
+
+// expr is a node of a parsed expression
+type expr interface {
+	String() string
+}
+
+type numberExpr struct{ v float64 }
+
+type inputExpr struct{}
+
+type unaryExpr struct {
+	op string
+	x  expr
+}
+
+type binaryExpr struct {
+	op   string
+	x, y expr
+}
+
+type callExpr struct {
+	fn   string
+	args []expr
+}
+
+type indexExpr struct {
+	x expr
+	n int
+}
+
+func (e numberExpr) String() string {
+	return strconv.FormatFloat(e.v, 'g', -1, 64)
+}
+
+func (e inputExpr) String() string {
+	return "close"
+}
+
+func (e unaryExpr) String() string {
+	return "(" + e.op + e.x.String() + ")"
+}
+
+func (e binaryExpr) String() string {
+	return "(" + e.x.String() + " " + e.op + " " + e.y.String() + ")"
+}
+
+func (e indexExpr) String() string {
+	return e.x.String() + "[" + strconv.Itoa(e.n) + "]"
+}
+
+func (e callExpr) String() string {
+	args := make([]string, len(e.args))
+	for i, a := range e.args {
+		args[i] = a.String()
+	}
+
+	return e.fn + "(" + strings.Join(args, ", ") + ")"
+}
+
+// exprToken is a lexical token of an expression
+type exprToken struct {
+	kind byte // 'n' number, 'i' identifier, 'o' operator or punctuation, 0 end
+	text string
+	num  float64
+	pos  int
+}
+
+// lexExpr splits an expression into tokens
+func lexExpr(src string) ([]exprToken, error) {
+	var toks []exprToken
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+
+			if j < len(src) && (src[j] == 'e' || src[j] == 'E') {
+				j++
+				if j < len(src) && (src[j] == '+' || src[j] == '-') {
+					j++
+				}
+
+				for j < len(src) && src[j] >= '0' && src[j] <= '9' {
+					j++
+				}
+			}
+
+			v, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, exprError(i, "invalid number %q", src[i:j])
+			}
+
+			toks = append(toks, exprToken{kind: 'n', text: src[i:j], num: v, pos: i})
+			i = j
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] >= 'a' && src[j] <= 'z' || src[j] >= 'A' && src[j] <= 'Z' || src[j] >= '0' && src[j] <= '9') {
+				j++
+			}
+
+			toks = append(toks, exprToken{kind: 'i', text: strings.ToLower(src[i:j]), pos: i})
+			i = j
+		default:
+			op := ""
+			for _, o := range []string{"&&", "||", "<=", ">=", "==", "!=", "+", "-", "*", "/", "<", ">", "!", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+
+			if op == "" {
+				return nil, exprError(i, "unexpected %q", c)
+			}
+
+			toks = append(toks, exprToken{kind: 'o', text: op, pos: i})
+			i += len(op)
+		}
+	}
+
+	return append(toks, exprToken{pos: len(src)}), nil
+}
+
+// exprError returns an ErrInvalidExpr error at position pos of the source
+func exprError(pos int, format string, args ...interface{}) error {
+	return errorf(ErrInvalidExpr, "%s at %d", fmt.Sprintf(format, args...), pos)
+}
+
+// exprParser is a recursive descent parser of expressions
+type exprParser struct {
+	toks []exprToken
+	i    int
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.toks[p.i]
+}
+
+func (p *exprParser) next() exprToken {
+	t := p.toks[p.i]
+	if t.kind != 0 {
+		p.i++
+	}
+
+	return t
+}
+
+// accept consumes the next token if it is one of the operators
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != 'o' {
+		return "", false
+	}
+
+	for _, op := range ops {
+		if t.text == op {
+			p.i++
+			return op, true
+		}
+	}
+
+	return "", false
+}
+
+func (p *exprParser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		t := p.peek()
+		return exprError(t.pos, "expected %q", op)
+	}
+
+	return nil
+}
+
+// binary parses a left associative binary expression of operators ops over operands parsed by operand
+func (p *exprParser) binary(operand func() (expr, error), ops ...string) (expr, error) {
+	x, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return x, nil
+		}
+
+		y, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		x = binaryExpr{op: op, x: x, y: y}
+	}
+}
+
+// or parses the lowest precedence expression
+// Precedence from lowest to highest is || && comparison + - * / unary postfix
+func (p *exprParser) or() (expr, error) {
+	return p.binary(p.and, "||")
+}
+
+func (p *exprParser) and() (expr, error) {
+	return p.binary(p.cmp, "&&")
+}
+
+func (p *exprParser) cmp() (expr, error) {
+	return p.binary(p.add, "<=", ">=", "==", "!=", "<", ">")
+}
+
+func (p *exprParser) add() (expr, error) {
+	return p.binary(p.mul, "+", "-")
+}
+
+func (p *exprParser) mul() (expr, error) {
+	return p.binary(p.unary, "*", "/")
+}
+
+func (p *exprParser) unary() (expr, error) {
+	if op, ok := p.accept("-", "!"); ok {
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return unaryExpr{op: op, x: x}, nil
+	}
+
+	return p.postfix()
+}
+
+func (p *exprParser) postfix() (expr, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.accept("["); !ok {
+			return x, nil
+		}
+
+		t := p.next()
+		if t.kind != 'n' || t.num != math.Trunc(t.num) || t.num < 0 || t.num > MaxLookback {
+			return nil, exprError(t.pos, "lookback index must be a non negative integer of at most %d", MaxLookback)
+		}
+
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+
+		x = indexExpr{x: x, n: int(t.num)}
+	}
+}
+
+func (p *exprParser) primary() (expr, error) {
+	t := p.next()
+
+	switch t.kind {
+	case 'n':
+		return numberExpr{t.num}, nil
+	case 'i':
+		if _, ok := p.accept("("); !ok {
+			if exprInputs[t.text] {
+				return inputExpr{}, nil
+			}
+
+			return nil, exprError(t.pos, "unknown identifier %q", t.text)
+		}
+
+		if _, ok := exprFuncs[t.text]; !ok {
+			return nil, exprError(t.pos, "unknown function %q", t.text)
+		}
+
+		call := callExpr{fn: t.text}
+		if _, ok := p.accept(")"); ok {
+			return call, nil
+		}
+
+		for {
+			arg, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+
+			call.args = append(call.args, arg)
+
+			if _, ok := p.accept(")"); ok {
+				return call, nil
+			}
+
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	case 'o':
+		if t.text == "(" {
+			x, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+
+			return x, p.expect(")")
+		}
+	}
+
+	return nil, exprError(t.pos, "unexpected %q", t.text)
+}
+
+// parseExpr parses the source of an expression
+func parseExpr(src string) (expr, error) {
+	toks, err := lexExpr(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{toks: toks}
+
+	x, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != 0 {
+		return nil, exprError(t.pos, "unexpected %q", t.text)
+	}
+
+	return x, nil
+}
+
+// ValidateExpr parses the source of an expression and reports any syntax error, see Pipeline.Compile
+func ValidateExpr(src string) error {
+	_, err := parseExpr(src)
+	return err
+}
+
+// lagStream delays a stream by n values
+type lagStream struct {
+	buf   []float64
+	count int
+}
+
+func (s *lagStream) Update(v float64) {
+	s.buf[s.count%len(s.buf)] = v
+	s.count++
+}
+
+// Value returns the value n values before the last
+func (s *lagStream) Value() float64 {
+	if !s.Ready() {
+		return 0.0
+	}
+
+	return s.buf[s.count%len(s.buf)]
+}
+
+func (s *lagStream) Ready() bool {
+	return s.count >= len(s.buf)
+}
+
+func (s *lagStream) Reset() {
+	s.count = 0
+}
+
+func (s *lagStream) WarmupPeriod() int {
+	return len(s.buf)
+}
+
+// lag adds a node of the value of input n values ago
+func (p *Pipeline) lag(input Node, n int) (Node, error) {
+	if n == 0 {
+		return input, nil
+	}
+
+	return p.Apply(&lagStream{buf: make([]float64, n+1)}, input)
+}
+
+// Compile compiles an expression into nodes of the pipeline and returns the node of its value
+// Sub-expressions already compiled into the pipeline are shared.
+// Returns an ErrInvalidExpr error for a syntax error and ErrInvalidParam for invalid function arguments
+func (p *Pipeline) Compile(src string) (Node, error) {
+	x, err := parseExpr(src)
+	if err != nil {
+		return 0, err
+	}
+
+	c, err := p.compileExpr(x)
+	if err != nil {
+		return 0, err
+	}
+
+	if !c.konst {
+		return c.node, nil
+	}
+
+	return p.constant(c.v)
+}
+
+// constant adds a node of the constant v, ready with the input
+func (p *Pipeline) constant(v float64) (Node, error) {
+	return p.Combine(func([]float64) float64 { return v }, PipelineInput)
+}
+
+// exprOperand is a compiled expression, either a node or a constant folded at compile time
+type exprOperand struct {
+	node  Node
+	konst bool
+	v     float64
+}
+
+// compileExpr compiles the expression x, sharing the node of an identical expression
+func (p *Pipeline) compileExpr(x expr) (exprOperand, error) {
+	key := x.String()
+	if n, ok := p.exprs[key]; ok {
+		return exprOperand{node: n}, nil
+	}
+
+	c, err := p.compileNew(x)
+	if err != nil {
+		return c, err
+	}
+
+	if !c.konst {
+		p.exprs[key] = c.node
+	}
+
+	return c, nil
+}
+
+func (p *Pipeline) compileNew(x expr) (exprOperand, error) {
+	switch e := x.(type) {
+	case numberExpr:
+		return exprOperand{konst: true, v: e.v}, nil
+	case inputExpr:
+		return exprOperand{node: PipelineInput}, nil
+	case unaryExpr:
+		c, err := p.compileExpr(e.x)
+		if err != nil {
+			return c, err
+		}
+
+		f := func(v float64) float64 { return -v }
+		if e.op == "!" {
+			f = func(v float64) float64 { return exprBool(v == 0.0) }
+		}
+
+		if c.konst {
+			return exprOperand{konst: true, v: f(c.v)}, nil
+		}
+
+		n, err := p.Combine(func(xs []float64) float64 { return f(xs[0]) }, c.node)
+
+		return exprOperand{node: n}, err
+	case binaryExpr:
+		return p.compileBinary(e)
+	case indexExpr:
+		c, err := p.compileExpr(e.x)
+		if err != nil || c.konst {
+			return c, err
+		}
+
+		n, err := p.lag(c.node, e.n)
+
+		return exprOperand{node: n}, err
+	case callExpr:
+		return p.compileCall(e)
+	}
+
+	return exprOperand{}, errorf(ErrInvalidExpr, "%s", x)
+}
+
+// exprBinaryOps are the functions of the binary operators
+var exprBinaryOps = map[string]func(a, b float64) float64{
+	"+":  func(a, b float64) float64 { return a + b },
+	"-":  func(a, b float64) float64 { return a - b },
+	"*":  func(a, b float64) float64 { return a * b },
+	"/":  func(a, b float64) float64 { return a / b },
+	"<":  func(a, b float64) float64 { return exprBool(a < b) },
+	"<=": func(a, b float64) float64 { return exprBool(a <= b) },
+	">":  func(a, b float64) float64 { return exprBool(a > b) },
+	">=": func(a, b float64) float64 { return exprBool(a >= b) },
+	"==": func(a, b float64) float64 { return exprBool(a == b) },
+	"!=": func(a, b float64) float64 { return exprBool(a != b) },
+	"&&": func(a, b float64) float64 { return exprBool(a != 0.0 && b != 0.0) },
+	"||": func(a, b float64) float64 { return exprBool(a != 0.0 || b != 0.0) },
+}
+
+func (p *Pipeline) compileBinary(e binaryExpr) (exprOperand, error) {
+	f := exprBinaryOps[e.op]
+
+	a, err := p.compileExpr(e.x)
+	if err != nil {
+		return a, err
+	}
+
+	b, err := p.compileExpr(e.y)
+	if err != nil {
+		return b, err
+	}
+
+	var n Node
+
+	switch {
+	case a.konst && b.konst:
+		return exprOperand{konst: true, v: f(a.v, b.v)}, nil
+	case a.konst:
+		n, err = p.Combine(func(xs []float64) float64 { return f(a.v, xs[0]) }, b.node)
+	case b.konst:
+		n, err = p.Combine(func(xs []float64) float64 { return f(xs[0], b.v) }, a.node)
+	default:
+		n, err = p.Combine(func(xs []float64) float64 { return f(xs[0], xs[1]) }, a.node, b.node)
+	}
+
+	return exprOperand{node: n}, err
+}
+
+func (p *Pipeline) compileCall(e callExpr) (exprOperand, error) {
+	fn := exprFuncs[e.fn]
+
+	nargs := fn.series + len(fn.params)
+	if len(e.args) > nargs || len(e.args) < nargs-len(fn.defaults) || len(e.args) < fn.series {
+		return exprOperand{}, errorf(ErrInvalidExpr, "wrong number of arguments to %s", e.fn)
+	}
+
+	inputs := make([]Node, fn.series)
+	for i := range inputs {
+		c, err := p.compileExpr(e.args[i])
+		if err != nil {
+			return c, err
+		}
+
+		if c.konst {
+			// e.g. the level of a crossover
+			if c.node, err = p.constant(c.v); err != nil {
+				return c, err
+			}
+		}
+
+		inputs[i] = c.node
+	}
+
+	params := make([]float64, len(fn.params))
+	for i := range params {
+		if j := fn.series + i; j < len(e.args) {
+			c, err := p.compileExpr(e.args[j])
+			if err != nil {
+				return c, err
+			}
+
+			if !c.konst {
+				return exprOperand{}, errorf(ErrInvalidExpr, "%s of %s must be a constant", fn.params[i], e.fn)
+			}
+
+			params[i] = c.v
+			continue
+		}
+
+		params[i] = fn.defaults[len(fn.defaults)-len(fn.params)+i]
+	}
+
+	n, err := fn.compile(p, inputs, params)
+
+	return exprOperand{node: n}, err
+}
+
+// ExprIndicator is an Indicator computing an expression, see Pipeline.Compile
+type ExprIndicator struct {
+	src string
+	p   *Pipeline
+	out Node
+}
+
+// NewExprIndicator compiles an expression into a new pipeline of the indicators of DefaultRegistry
+func NewExprIndicator(src string) (*ExprIndicator, error) {
+	p := NewPipeline(nil)
+
+	out, err := p.Compile(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExprIndicator{src: src, p: p, out: out}, nil
+}
+
+// Update adds the next value of the stream
+func (e *ExprIndicator) Update(v float64) {
+	e.p.Update(v)
+}
+
+// Value returns the current value of the expression
+func (e *ExprIndicator) Value() float64 {
+	return e.p.Value(e.out)
+}
+
+// Ready reports whether every indicator of the expression is ready
+func (e *ExprIndicator) Ready() bool {
+	return e.p.Ready(e.out)
+}
+
+// WarmupPeriod returns the number of values before the expression is ready
+func (e *ExprIndicator) WarmupPeriod() int {
+	return e.p.WarmupPeriod(e.out)
+}
+
+// Reset clears the stream
+func (e *ExprIndicator) Reset() {
+	e.p.Reset()
+}
+
+// String returns the source of the expression
+func (e *ExprIndicator) String() string {
+	return e.src
+}
+
+// EvalExprSeries computes an expression for each value of a series
+// Values before the expression is ready are 0.0, as with the static series functions.
+func EvalExprSeries(src string, series []float64) ([]float64, error) {
+	e, err := NewExprIndicator(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return UpdateSeries(e, series), nil
+}
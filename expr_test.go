@@ -0,0 +1,161 @@
+package technical
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExprSyntax(t *testing.T) {
+	assert.NoError(t, ValidateExpr("close > boll_upper(close, 20, 2.0) && rsi(close, 14) > 70"))
+	assert.NoError(t, ValidateExpr("-(close - ema(close, 5))[2] * 1e-3 >= .5 || !cross_above(close, 1)"))
+
+	for _, src := range []string{"", "close >", "foo(close)", "volume", "close[1.5]", "close[-1]", "close[1e15]", "1 $ 2", "(close", "ema(close,)", "close close"} {
+		err := ValidateExpr(src)
+		assert.Equal(t, ErrInvalidExpr, Cause(err), "%q", src)
+	}
+
+	// argument errors are found on compile
+	p := NewPipeline(nil)
+	for _, src := range []string{"ema(close)", "rsi(close, 14, 3)", "ema(close, close)", "min(close)"} {
+		_, err := p.Compile(src)
+		assert.Equal(t, ErrInvalidExpr, Cause(err), "%q", src)
+	}
+
+	for _, src := range []string{"ema(close, 0)", "rsi(close, 2.5)", "macd(close, 12.5)", "keltner(close, 20, 14, -1)",
+		"ema(close, 1e15)", "macd(close, 12, 1e15)", "keltner(close, 1e300, 14)"} {
+		_, err := p.Compile(src)
+		assert.Error(t, err, "%q", src)
+	}
+}
+
+func TestExprArithmetic(t *testing.T) {
+	series := []float64{1, 2, 4, 3, 1, 2}
+
+	cases := []struct {
+		src      string
+		expected []float64
+	}{
+		{"1 + 2 * 3", []float64{7, 7, 7, 7, 7, 7}},
+		{"(1 + 2) * 3 - 10 / 5", []float64{7, 7, 7, 7, 7, 7}},
+		{"-close + 1", []float64{0, -1, -3, -2, 0, -1}},
+		{"close >= 2 && close != 4", []float64{0, 1, 0, 1, 0, 1}},
+		{"!(close > 2) || close == 4", []float64{1, 1, 1, 0, 1, 1}},
+		{"close - close[1]", []float64{0, 1, 2, -1, -2, 1}},
+		{"price[2]", []float64{0, 0, 1, 2, 4, 3}},
+		{"abs(close - 3) + max(close, 2) - min(close, 2)", []float64{3, 1, 3, 1, 3, 1}},
+		{"cross_above(close, 2)", []float64{0, 0, 1, 0, 0, 0}},
+		{"cross_below(close, 2)", []float64{0, 0, 0, 0, 1, 0}},
+		{"cross(close, close[1])", []float64{0, 0, 0, 1, 0, 1}},
+	}
+
+	for _, c := range cases {
+		actual, err := EvalExprSeries(c.src, series)
+		assert.NoError(t, err, c.src)
+		assert.Equal(t, c.expected, actual, c.src)
+	}
+
+	_, err := EvalExprSeries("close +", series)
+	assert.Error(t, err)
+}
+
+func TestExprIndicators(t *testing.T) {
+	testseries := readMockSeries64("./mock/test_series.txt")[:2000]
+
+	src := "close > boll_upper(close, 20, 2.0) && rsi(close, 14) > 70"
+	e, err := NewExprIndicator(src)
+	assert.NoError(t, err)
+	assert.Equal(t, src, e.String())
+	assert.Equal(t, 20, e.WarmupPeriod())
+
+	actual := UpdateSeries(e, testseries)
+
+	bounds := StaticBollingerEMA64(testseries, 20, 0, 2)
+	rsis := RSISeries64(testseries, 14)
+	signals := 0
+	for i := 19; i < len(testseries); i++ {
+		expected := exprBool(testseries[i] > bounds[i].Upper && rsis[i] > 70)
+		assert.Equal(t, expected, actual[i], "%d", i)
+		signals += int(expected)
+	}
+
+	assert.True(t, signals > 0)
+
+	e.Reset()
+	assert.False(t, e.Ready())
+
+	macd, err := EvalExprSeries("macd_hist(close)", testseries)
+	assert.NoError(t, err)
+	p := NewPipeline(nil)
+	_, _, hist, _ := p.MACD(12, 26, 9, PipelineInput)
+	for _, v := range testseries {
+		p.Update(v)
+	}
+	assert.Equal(t, p.Value(hist), macd[len(macd)-1])
+
+	keltner, err := EvalExprSeries("keltner_upper(close, 20, 14) - keltner(close, 20, 14, 2)", testseries)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*StaticATR64(testseries, 14, 1), keltner[len(keltner)-1], 1e-9)
+
+	for src, warmup := range map[string]int{
+		"ema(close, 10)":                    10,
+		"rsi(close, 14)":                    15,
+		"ema(rsi(close, 14), 5)":            19,
+		"close[3]":                          4,
+		"cross_above(close, ema(close, 3))": 4,
+		"zscore(linreg(close, 5), 10)":      14,
+	} {
+		e, err := NewExprIndicator(src)
+		assert.NoError(t, err)
+		assert.Equal(t, warmup, e.WarmupPeriod(), src)
+
+		// ready exactly after the warm up
+		for i, v := range testseries[:warmup] {
+			e.Update(v)
+			assert.Equal(t, i == warmup-1, e.Ready(), src)
+		}
+	}
+}
+
+func TestExprBarIndicators(t *testing.T) {
+	testseries := readMockSeries64("./mock/test_series.txt")[:2000]
+
+	ultimate, _ := NewUltimateOscillatorStream64(7, 14, 28)
+	psar, _ := NewParabolicSARStream64(DefaultSARStep, DefaultSARMax)
+	supertrend, _ := NewSuperTrendStream64(5, 2, TickSize{})
+	for src, ind := range map[string]Indicator{
+		"ultimate(close)":         ultimate,
+		"psar(close)":             psar,
+		"supertrend(close, 5, 2)": supertrend,
+	} {
+		values, err := EvalExprSeries(src, testseries)
+		assert.NoError(t, err, src)
+		assert.Equal(t, UpdateSeries(ind, testseries), values, src)
+	}
+
+	_, err := NewExprIndicator("ultimate(close, 14, 7, 28)")
+	assert.Equal(t, ErrInvalidLookback, Cause(err))
+}
+
+func TestExprShared(t *testing.T) {
+	p := NewPipeline(nil)
+
+	a, err := p.Compile("ema(close, 20) > ema(close, 50)")
+	assert.NoError(t, err)
+	size := p.Len()
+
+	// the same expression and its sub-expressions are shared
+	b, err := p.Compile("ema(price, 20) > ema(close, 50)")
+	assert.NoError(t, err)
+	assert.Equal(t, a, b)
+	assert.Equal(t, size, p.Len())
+
+	_, err = p.Compile("cross_above(ema(close, 20), ema(close, 50))")
+	assert.NoError(t, err)
+	assert.Equal(t, size+3, p.Len())
+
+	// boll_upper and boll_lower share the band
+	_, err = p.Compile("boll_upper(close, 20) - boll_lower(close, 20, 2)")
+	assert.NoError(t, err)
+	assert.Equal(t, size+3+4, p.Len())
+}
@@ -0,0 +1,274 @@
+package technical
+
+import (
+	"math"
+)
+
+/*
+* Ichimoku Kinko Hyo describes the trend, momentum and support and resistance of bars with five lines:
+*
+*	Tenkan-sen (conversion line): midpoint of the highest high and lowest low of the last Tenkan bars
+*	Kijun-sen (base line): midpoint of the highest high and lowest low of the last Kijun bars
+*	Senkou span A (leading span A): (Tenkan + Kijun) / 2, plotted Displacement bars ahead
+*	Senkou span B (leading span B): midpoint of the highest high and lowest low of the last SenkouB bars, plotted Displacement bars ahead
+*	Chikou span (lagging span): the close, plotted Displacement bars behind
+*
+* The area between the two Senkou spans is the cloud (kumo), the cloud at a bar is the spans computed Displacement bars earlier.
+* The highest highs and lowest lows are rolling extremes in amortized O(1) per bar.
+ */
+
+// IchimokuConfig are the periods of the Ichimoku lines
+type IchimokuConfig struct {
+	Tenkan       int // periods of the conversion line, typically 9
+	Kijun        int // periods of the base line, typically 26
+	SenkouB      int // periods of leading span B, typically 52
+	Displacement int // number of bars the leading spans are plotted ahead and the lagging span behind, typically 26
+}
+
+// DefaultIchimoku is the traditional 9, 26, 52 Ichimoku with a displacement of 26
+var DefaultIchimoku = IchimokuConfig{Tenkan: 9, Kijun: 26, SenkouB: 52, Displacement: 26}
+
+// Validate returns ErrInvalidPeriods if a period is <= 0 or the displacement is < 0
+func (c IchimokuConfig) Validate() error {
+	if c.Tenkan <= 0 || c.Kijun <= 0 || c.SenkouB <= 0 || c.Displacement < 0 {
+		return ErrInvalidPeriods
+	}
+
+	return nil
+}
+
+// warmup returns the number of bars before all lines are computed
+func (c IchimokuConfig) warmup() int {
+	return maxInt(maxInt(c.Tenkan, c.Kijun), c.SenkouB)
+}
+
+// IchimokuSeries64 are the Ichimoku lines of a series of bars, each indexed by the bar it is plotted at
+// Tenkan, Kijun and Chikou have a value per bar. SenkouA and SenkouB have Displacement values more than the bars,
+// the spans plotted at the Displacement bars after the last bar. Chikou[i] is the close of bar i + Displacement,
+// so its last Displacement values are not known yet.
+// Values before a line is computed or not yet known are 0.0.
+type IchimokuSeries64 struct {
+	Tenkan  []float64
+	Kijun   []float64
+	SenkouA []float64
+	SenkouB []float64
+	Chikou  []float64
+	start   int // index of the first cloud
+}
+
+// Ichimoku64 computes the Ichimoku lines of bars, see IchimokuSeries64
+// Returns nil for an invalid config
+func Ichimoku64(bars []Bar, c IchimokuConfig) *IchimokuSeries64 {
+	s, err := NewIchimokuStream64(c)
+	if err != nil {
+		return nil
+	}
+
+	n, d := len(bars), c.Displacement
+	ichimoku := &IchimokuSeries64{
+		Tenkan:  make([]float64, n),
+		Kijun:   make([]float64, n),
+		SenkouA: make([]float64, n+d),
+		SenkouB: make([]float64, n+d),
+		Chikou:  make([]float64, n),
+		start:   c.warmup() - 1 + d,
+	}
+
+	for i, b := range bars {
+		s.UpdateBar(b)
+
+		ichimoku.Tenkan[i] = s.Tenkan()
+		ichimoku.Kijun[i] = s.Kijun()
+		ichimoku.SenkouA[i+d] = s.SenkouA()
+		ichimoku.SenkouB[i+d] = s.SenkouB()
+		if i >= d {
+			ichimoku.Chikou[i-d] = b.Close
+		}
+	}
+
+	return ichimoku
+}
+
+// Cloud returns the cloud plotted at each index of SenkouA and SenkouB, see IchimokuStream64.Cloud
+// Bounds before both spans are computed are empty.
+func (s *IchimokuSeries64) Cloud() []Bound64 {
+	bounds := make([]Bound64, len(s.SenkouA))
+	for i := s.start; i < len(bounds); i++ {
+		bounds[i] = cloud(s.SenkouA[i], s.SenkouB[i])
+	}
+
+	return bounds
+}
+
+// cloud returns the cloud of the leading spans a and b
+func cloud(a float64, b float64) Bound64 {
+	return Bound64{Upper: math.Max(a, b), Midpoint: (a + b) / 2.0, Lower: math.Min(a, b)}
+}
+
+// IchimokuStream64 computes the Ichimoku lines over a stream of bars
+// The Senkou spans of the last bar are plotted Displacement bars ahead and the Chikou span, the last close,
+// Displacement bars behind. The cloud of the last bar is the spans computed Displacement bars earlier.
+type IchimokuStream64 struct {
+	tenkanHighs  extremeWindow64
+	tenkanLows   extremeWindow64
+	kijunHighs   extremeWindow64
+	kijunLows    extremeWindow64
+	senkouHighs  extremeWindow64
+	senkouLows   extremeWindow64
+	spansA       window64 // leading spans of the last Displacement bars
+	spansB       window64
+	cloudA       float64
+	cloudB       float64
+	close        float64
+	n            int
+	displacement int
+}
+
+// NewIchimokuStream64 creates an IchimokuStream64
+// Returns ErrInvalidPeriods if a period is <= 0 or the displacement is < 0
+func NewIchimokuStream64(c IchimokuConfig) (*IchimokuStream64, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &IchimokuStream64{
+		tenkanHighs:  newMaxWindow64(c.Tenkan),
+		tenkanLows:   newMinWindow64(c.Tenkan),
+		kijunHighs:   newMaxWindow64(c.Kijun),
+		kijunLows:    newMinWindow64(c.Kijun),
+		senkouHighs:  newMaxWindow64(c.SenkouB),
+		senkouLows:   newMinWindow64(c.SenkouB),
+		spansA:       newWindow64(c.Displacement),
+		spansB:       newWindow64(c.Displacement),
+		displacement: c.Displacement,
+	}, nil
+}
+
+// Update adds the next value of the stream as a bar of one value
+func (s *IchimokuStream64) Update(v float64) {
+	s.UpdateBar(valueBar(v))
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *IchimokuStream64) UpdateBar(b Bar) {
+	s.tenkanHighs.push(b.High)
+	s.tenkanLows.push(b.Low)
+	s.kijunHighs.push(b.High)
+	s.kijunLows.push(b.Low)
+	s.senkouHighs.push(b.High)
+	s.senkouLows.push(b.Low)
+	s.close = b.Close
+	s.n++
+
+	spanA, spanB := s.SenkouA(), s.SenkouB()
+	if s.displacement == 0 {
+		s.cloudA, s.cloudB = spanA, spanB
+		return
+	}
+
+	// the spans pushed out of the delay windows were computed Displacement bars ago
+	if oldest, ok := s.spansA.push(spanA); ok {
+		s.cloudA = oldest
+	}
+
+	if oldest, ok := s.spansB.push(spanB); ok {
+		s.cloudB = oldest
+	}
+}
+
+// midpoint returns the midpoint of the extremes of highs and lows, 0.0 until the windows are full
+func midpoint(highs *extremeWindow64, lows *extremeWindow64) float64 {
+	if !highs.full() {
+		return 0.0
+	}
+
+	return (highs.value() + lows.value()) / 2.0
+}
+
+// Tenkan returns the current conversion line, 0.0 until Tenkan bars
+func (s *IchimokuStream64) Tenkan() float64 {
+	return midpoint(&s.tenkanHighs, &s.tenkanLows)
+}
+
+// Kijun returns the current base line, 0.0 until Kijun bars
+func (s *IchimokuStream64) Kijun() float64 {
+	return midpoint(&s.kijunHighs, &s.kijunLows)
+}
+
+// SenkouA returns the leading span A of the last bar, plotted Displacement bars ahead, 0.0 until Tenkan and Kijun bars
+func (s *IchimokuStream64) SenkouA() float64 {
+	if !s.tenkanHighs.full() || !s.kijunHighs.full() {
+		return 0.0
+	}
+
+	return (s.Tenkan() + s.Kijun()) / 2.0
+}
+
+// SenkouB returns the leading span B of the last bar, plotted Displacement bars ahead, 0.0 until SenkouB bars
+func (s *IchimokuStream64) SenkouB() float64 {
+	return midpoint(&s.senkouHighs, &s.senkouLows)
+}
+
+// Chikou returns the lagging span of the last bar, its close plotted Displacement bars behind
+func (s *IchimokuStream64) Chikou() float64 {
+	return s.close
+}
+
+// Cloud returns the cloud at the last bar, the leading spans computed Displacement bars earlier
+// Upper is the higher and Lower the lower of the spans, the Midpoint is halfway between.
+// The cloud is empty until the stream is ready.
+func (s *IchimokuStream64) Cloud() Bound64 {
+	if !s.Ready() {
+		return Bound64{}
+	}
+
+	return cloud(s.cloudA, s.cloudB)
+}
+
+// Bound returns the current cloud
+func (s *IchimokuStream64) Bound() Bound64 {
+	return s.Cloud()
+}
+
+// Value returns the current base line
+func (s *IchimokuStream64) Value() float64 {
+	return s.Kijun()
+}
+
+// Ready reports whether the cloud at the last bar is computed
+func (s *IchimokuStream64) Ready() bool {
+	return s.n >= s.WarmupPeriod()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *IchimokuStream64) WarmupPeriod() int {
+	return maxInt(maxInt(s.tenkanHighs.size(), s.kijunHighs.size()), s.senkouHighs.size()) + s.displacement
+}
+
+// Reset clears the stream
+func (s *IchimokuStream64) Reset() {
+	s.tenkanHighs.reset()
+	s.tenkanLows.reset()
+	s.kijunHighs.reset()
+	s.kijunLows.reset()
+	s.senkouHighs.reset()
+	s.senkouLows.reset()
+	s.spansA.reset()
+	s.spansB.reset()
+	s.cloudA, s.cloudB, s.close, s.n = 0.0, 0.0, 0.0, 0
+}
+
+// Clone returns an independent copy of the stream
+func (s *IchimokuStream64) Clone() *IchimokuStream64 {
+	c := *s
+	c.tenkanHighs = s.tenkanHighs.clone()
+	c.tenkanLows = s.tenkanLows.clone()
+	c.kijunHighs = s.kijunHighs.clone()
+	c.kijunLows = s.kijunLows.clone()
+	c.senkouHighs = s.senkouHighs.clone()
+	c.senkouLows = s.senkouLows.clone()
+	c.spansA = s.spansA.clone()
+	c.spansB = s.spansB.clone()
+
+	return &c
+}
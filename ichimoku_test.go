@@ -0,0 +1,113 @@
+package technical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// donchianMid returns the midpoint of the highest high and lowest low of the n bars up to i, 0.0 before n bars
+func donchianMid(bars []Bar, i int, n int) float64 {
+	if i+1 < n {
+		return 0.0
+	}
+
+	high, low := bars[i].High, bars[i].Low
+	for _, b := range bars[i-n+1 : i+1] {
+		high, low = math.Max(high, b.High), math.Min(low, b.Low)
+	}
+
+	return (high + low) / 2.0
+}
+
+func TestIchimokuConfig(t *testing.T) {
+	assert.Nil(t, DefaultIchimoku.Validate())
+	assert.Nil(t, IchimokuConfig{Tenkan: 1, Kijun: 1, SenkouB: 1}.Validate())
+	assert.Equal(t, ErrInvalidPeriods, IchimokuConfig{Tenkan: 9, Kijun: 26, Displacement: 26}.Validate())
+	assert.Equal(t, ErrInvalidPeriods, IchimokuConfig{Tenkan: 9, Kijun: 26, SenkouB: 52, Displacement: -1}.Validate())
+
+	_, err := NewIchimokuStream64(IchimokuConfig{})
+	assert.Equal(t, ErrInvalidPeriods, err)
+	assert.Nil(t, Ichimoku64(stopBars(), IchimokuConfig{}))
+}
+
+func TestIchimoku(t *testing.T) {
+	bars := stopBars()
+	c := IchimokuConfig{Tenkan: 2, Kijun: 3, SenkouB: 4, Displacement: 2}
+
+	ichimoku := Ichimoku64(bars, c)
+	assert.Len(t, ichimoku.Tenkan, len(bars))
+	assert.Len(t, ichimoku.Chikou, len(bars))
+
+	// the leading spans extend Displacement bars past the last bar
+	assert.Len(t, ichimoku.SenkouA, len(bars)+2)
+	assert.Len(t, ichimoku.SenkouB, len(bars)+2)
+
+	for i := range bars {
+		tenkan, kijun := donchianMid(bars, i, 2), donchianMid(bars, i, 3)
+		assert.Equal(t, tenkan, ichimoku.Tenkan[i])
+		assert.Equal(t, kijun, ichimoku.Kijun[i])
+
+		if i >= 2 {
+			assert.Equal(t, (tenkan+kijun)/2.0, ichimoku.SenkouA[i+2])
+		}
+
+		assert.Equal(t, donchianMid(bars, i, 4), ichimoku.SenkouB[i+2])
+	}
+
+	// the lagging span is the close 2 bars later, not known for the last 2 bars
+	assert.Equal(t, bars[2].Close, ichimoku.Chikou[0])
+	assert.Equal(t, bars[7].Close, ichimoku.Chikou[5])
+	assert.Equal(t, []float64{0, 0}, ichimoku.Chikou[6:])
+
+	// the first cloud is plotted 2 bars after span B is computed on bar 3
+	clouds := ichimoku.Cloud()
+	assert.Len(t, clouds, len(bars)+2)
+	assert.Equal(t, Bound64{}, clouds[4])
+	for i := 5; i < len(clouds); i++ {
+		a, b := ichimoku.SenkouA[i], ichimoku.SenkouB[i]
+		assert.Equal(t, Bound64{Upper: math.Max(a, b), Midpoint: (a + b) / 2.0, Lower: math.Min(a, b)}, clouds[i])
+	}
+
+	// the rally puts span A above span B
+	assert.Equal(t, ichimoku.SenkouA[6], clouds[6].Upper)
+	assert.Equal(t, 12.75, clouds[6].Upper)
+	assert.Equal(t, 12.0, clouds[6].Lower)
+}
+
+func TestIchimokuStream(t *testing.T) {
+	bars := mockBars()
+	ichimoku := Ichimoku64(bars, DefaultIchimoku)
+	clouds := ichimoku.Cloud()
+
+	s, _ := NewIchimokuStream64(DefaultIchimoku)
+	assert.Equal(t, 52+26, s.WarmupPeriod())
+
+	for i, b := range bars {
+		s.UpdateBar(b)
+
+		assert.Equal(t, i+1 >= 78, s.Ready())
+		assert.Equal(t, ichimoku.Tenkan[i], s.Tenkan())
+		assert.Equal(t, ichimoku.Kijun[i], s.Value())
+		assert.Equal(t, ichimoku.SenkouA[i+26], s.SenkouA())
+		assert.Equal(t, ichimoku.SenkouB[i+26], s.SenkouB())
+		assert.Equal(t, clouds[i], s.Bound())
+		assert.Equal(t, b.Close, s.Chikou())
+	}
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, 0.0, s.Tenkan())
+	assert.Equal(t, Bound64{}, s.Cloud())
+	assert.Equal(t, clouds[len(bars)-1], c.Cloud())
+
+	// without displacement the cloud is the spans of the bar
+	s, _ = NewIchimokuStream64(IchimokuConfig{Tenkan: 9, Kijun: 26, SenkouB: 52})
+	for _, b := range bars[:60] {
+		s.UpdateBar(b)
+	}
+
+	assert.Equal(t, cloud(s.SenkouA(), s.SenkouB()), s.Cloud())
+}
@@ -0,0 +1,83 @@
+package technical
+
+import (
+	"time"
+)
+
+// Indicator is a streaming indicator updated one value at a time
+// Every streaming indicator of the package implements Indicator, except time aware indicators which implement TimeIndicator
+// and volume indicators which are only updated with bars. Both adapt to a BarIndicator to be computed on a timeframe,
+// e.g. with TimeEMABarIndicator or VWAPBarIndicator, but are not in a Registry.
+type Indicator interface {
+	// Update adds the next value of the stream.
+	Update(v float64)
+	// Value returns the current value of the indicator. For band indicators this is the midpoint.
+	Value() float64
+	// Ready reports whether the indicator has seen enough values for Value to be meaningful.
+	Ready() bool
+	// Reset clears the indicator.
+	Reset()
+	// WarmupPeriod returns the number of values before the indicator is ready.
+	WarmupPeriod() int
+}
+
+// TimeIndicator is a time aware streaming indicator updated one timestamped value at a time
+type TimeIndicator interface {
+	// Update adds the value v observed at time t.
+	Update(t time.Time, v float64)
+	// Value returns the current value of the indicator. For band indicators this is the midpoint.
+	Value() float64
+	// Ready reports whether the indicator has seen enough values for Value to be meaningful.
+	Ready() bool
+	// Reset clears the indicator.
+	Reset()
+	// WarmupDuration returns the time after the first value before the indicator is ready.
+	WarmupDuration() time.Duration
+}
+
+// BandIndicator is an Indicator that also produces a Bollinger style Bound
+type BandIndicator interface {
+	Indicator
+	// Bound returns the current bound of the indicator.
+	Bound() Bound64
+}
+
+var (
+	_ Indicator     = (*EMAStream64)(nil)
+	_ BandIndicator = (*BollingerEMAStream64)(nil)
+	_ Indicator     = (*LinRegStream64)(nil)
+	_ Indicator     = (*ATRStream64)(nil)
+	_ Indicator     = (*RSIStream64)(nil)
+	_ Indicator     = (*ZScoreStream64)(nil)
+	_ Indicator     = (*ExprIndicator)(nil)
+	_ BandIndicator = (*ChandelierStream64)(nil)
+	_ Indicator     = (*ATRTrailingStopStream64)(nil)
+	_ BandIndicator = (*SuperTrendStream64)(nil)
+	_ Indicator     = (*ParabolicSARStream64)(nil)
+	_ BandIndicator = (*IchimokuStream64)(nil)
+	_ Indicator     = (*MomentumStream64)(nil)
+	_ Indicator     = (*ROCStream64)(nil)
+	_ Indicator     = (*TRIXStream64)(nil)
+	_ Indicator     = (*PPOStream64)(nil)
+	_ Indicator     = (*CMOStream64)(nil)
+	_ Indicator     = (*UltimateOscillatorStream64)(nil)
+	_ Indicator     = (*CCIStream64)(nil)
+	_ Indicator     = (*WilliamsRStream64)(nil)
+	_ Indicator     = (*AroonStream64)(nil)
+	_ TimeIndicator = (*TimeEMAStream64)(nil)
+	_ TimeIndicator = (*TimeBollingerStream64)(nil)
+)
+
+// UpdateSeries updates ind with every value of series and returns the indicator value after each
+// Values before the indicator is ready are 0.0, as with the static series functions.
+func UpdateSeries(ind Indicator, series []float64) []float64 {
+	res := make([]float64, len(series))
+	for i, v := range series {
+		ind.Update(v)
+		if ind.Ready() {
+			res[i] = ind.Value()
+		}
+	}
+
+	return res
+}
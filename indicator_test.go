@@ -0,0 +1,55 @@
+package technical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndicator(t *testing.T) {
+	testseries := readMockSeries64("./mock/test_series.txt")
+
+	ema, _ := NewEMAStream64(1200, 0)
+	bb, _ := NewBollingerEMAStream64(1200, 0, 2)
+	lr, _ := NewLinRegStream64(1200)
+	atr, _ := NewATRStream64(30)
+
+	for _, ind := range []Indicator{ema, bb, lr, atr} {
+		assert.False(t, ind.Ready())
+		for i, v := range testseries {
+			ind.Update(v)
+			if i+1 == ind.WarmupPeriod() {
+				assert.True(t, ind.Ready())
+			}
+		}
+
+		assert.True(t, ind.Ready())
+	}
+
+	assert.InDelta(t, 39.9488124468039, ema.Value(), 1e-9)
+	assert.Equal(t, bb.Bound().Midpoint, bb.Value())
+	assert.Equal(t, lr.Fit().Endpoint(), lr.Value())
+
+	// a period of one value is StaticATR64 with a period size of 1
+	atrseries := readMockSeries64("./mock/test_atr_series.txt")[:600]
+	atr.Reset()
+	UpdateSeries(atr, atrseries)
+	assert.InDelta(t, StaticATR64(atrseries, 30, 1), atr.Value(), 1e-12)
+
+	ema.Reset()
+	assert.Equal(t, EwmaSeries64(testseries, 0, 1200), UpdateSeries(ema, testseries))
+}
+
+func TestTimeIndicator(t *testing.T) {
+	ema, _ := NewTimeEMAStream64(time.Minute)
+	bb, _ := NewTimeBollingerStream64(time.Minute, 2)
+
+	for _, ind := range []TimeIndicator{ema, bb} {
+		assert.Equal(t, time.Minute, ind.WarmupDuration())
+		ind.Update(mockSessionOpen, 4)
+		ind.Update(mockSessionOpen.Add(time.Minute), 8)
+		assert.True(t, ind.Ready())
+		assert.Equal(t, 6.0, ind.Value())
+	}
+}
@@ -0,0 +1,539 @@
+package technical
+
+import (
+	"math"
+)
+
+/*
+* Linear Regression indicators fit an ordinary least squares line through the values of a period.
+*
+* The x axis of a period is the index of each value, 0 being the oldest and n-1 the most recent.
+* From the fitted line the slope, intercept, time series forecast (TSF) and coefficient of determination (R²)
+* are derived. A Linear Regression Channel is an envelope around the fitted line scaled by the standard error,
+* similar to how a Bollinger Band is scaled by the standard deviation.
+ */
+
+// LinReg64 represents a least squares line fitted over a period of N values
+type LinReg64 struct {
+	Slope     float64 `json:"slope"`
+	Intercept float64 `json:"intercept"`
+	R2        float64 `json:"r2"`
+	StdErr    float64 `json:"stdErr"`
+	N         int     `json:"n"`
+}
+
+// LinReg32 is 32 bit version of LinReg64
+type LinReg32 struct {
+	Slope     float32 `json:"slope"`
+	Intercept float32 `json:"intercept"`
+	R2        float32 `json:"r2"`
+	StdErr    float32 `json:"stdErr"`
+	N         int     `json:"n"`
+}
+
+// At returns the value of the fitted line at index x of the period
+func (l LinReg64) At(x float64) float64 {
+	return l.Intercept + l.Slope*x
+}
+
+// At is 32 bit version of LinReg64.At
+func (l LinReg32) At(x float32) float32 {
+	return l.Intercept + l.Slope*x
+}
+
+// Endpoint returns the value of the fitted line at the most recent value of the period
+func (l LinReg64) Endpoint() float64 {
+	if l.N == 0 {
+		return 0.0
+	}
+
+	return l.At(float64(l.N - 1))
+}
+
+// Endpoint is 32 bit version of LinReg64.Endpoint
+func (l LinReg32) Endpoint() float32 {
+	if l.N == 0 {
+		return 0.0
+	}
+
+	return l.At(float32(l.N - 1))
+}
+
+// Forecast returns the Time Series Forecast (TSF), the fitted line projected one value past the period
+func (l LinReg64) Forecast() float64 {
+	if l.N == 0 {
+		return 0.0
+	}
+
+	return l.At(float64(l.N))
+}
+
+// Forecast is 32 bit version of LinReg64.Forecast
+func (l LinReg32) Forecast() float32 {
+	if l.N == 0 {
+		return 0.0
+	}
+
+	return l.At(float32(l.N))
+}
+
+// linRegSums holds the running sums needed to fit a least squares line in O(1)
+// over a fixed size window where x is the index of the value in the window
+type linRegSums struct {
+	buf  []float64
+	head int // index of the oldest value in buf once full
+	n    int // number of values currently in the window
+	sy   float64
+	sxy  float64
+	syy  float64
+}
+
+func newLinRegSums(lb int) linRegSums {
+	return linRegSums{buf: make([]float64, lb)}
+}
+
+// push adds v as the most recent value of the window, dropping the oldest value if full
+func (s *linRegSums) push(v float64) {
+	lb := len(s.buf)
+
+	if s.n < lb {
+		s.buf[s.n] = v
+		s.sy += v
+		s.sxy += float64(s.n) * v
+		s.syy += v * v
+		s.n++
+		return
+	}
+
+	// every remaining value moves down one index when the oldest is dropped
+	// so sxy loses one of each remaining value: sum(y) - oldest
+	oldest := s.buf[s.head]
+	s.sxy -= s.sy - oldest
+	s.sy -= oldest
+	s.syy -= oldest * oldest
+
+	s.buf[s.head] = v
+	s.sy += v
+	s.sxy += float64(lb-1) * v
+	s.syy += v * v
+
+	s.head++
+	if s.head == lb {
+		// recompute the sums from scratch once per full rotation
+		// to keep the floating point drift of the running sums bounded
+		s.head = 0
+		s.resum()
+	}
+}
+
+// resum recomputes the running sums from the window values
+func (s *linRegSums) resum() {
+	s.sy, s.sxy, s.syy = 0.0, 0.0, 0.0
+	for i := 0; i < s.n; i++ {
+		v := s.buf[(s.head+i)%len(s.buf)]
+		s.sy += v
+		s.sxy += float64(i) * v
+		s.syy += v * v
+	}
+}
+
+// clone returns a deep copy of the sums
+func (s *linRegSums) clone() linRegSums {
+	c := *s
+	c.buf = append([]float64(nil), s.buf...)
+
+	return c
+}
+
+func (s *linRegSums) reset() {
+	for i := range s.buf {
+		s.buf[i] = 0.0
+	}
+
+	s.head, s.n = 0, 0
+	s.sy, s.sxy, s.syy = 0.0, 0.0, 0.0
+}
+
+// fit computes the least squares line of the values currently in the window
+func (s *linRegSums) fit() LinReg64 {
+	return linRegFromSums(s.n, s.sy, s.sxy, s.syy)
+}
+
+// linRegFromSums fits a least squares line from the sums of y, x*y and y*y over x = 0..n-1
+func linRegFromSums(n int, sy, sxy, syy float64) LinReg64 {
+	l := LinReg64{N: n}
+
+	if n == 0 {
+		return l
+	}
+
+	if n == 1 {
+		l.Intercept = sy
+		return l
+	}
+
+	fn := float64(n)
+	sx := fn * (fn - 1) / 2
+	sxx := (fn - 1) * fn * (2*fn - 1) / 6
+
+	covN := fn*sxy - sx*sy  // n^2 * covariance of x and y
+	varXN := fn*sxx - sx*sx // n^2 * variance of x
+	varYN := fn*syy - sy*sy // n^2 * variance of y
+
+	l.Slope = covN / varXN
+	l.Intercept = (sy - l.Slope*sx) / fn
+
+	if varYN > 0.0 {
+		l.R2 = (covN * covN) / (varXN * varYN)
+	}
+
+	if n > 2 {
+		// sum of squared residuals
+		sse := (varYN - l.Slope*covN) / fn
+		if sse > 0.0 {
+			l.StdErr = math.Sqrt(sse / (fn - 2))
+		}
+	}
+
+	return l
+}
+
+func (l LinReg64) to32() LinReg32 {
+	return LinReg32{
+		Slope:     float32(l.Slope),
+		Intercept: float32(l.Intercept),
+		R2:        float32(l.R2),
+		StdErr:    float32(l.StdErr),
+		N:         l.N,
+	}
+}
+
+// LinRegFit64 fits a least squares line through the given period
+// The x value of each element in the period is its index
+//
+// R2 is 0 if the period is flat. StdErr is 0 if the period has less than 3 values.
+func LinRegFit64(period []float64) LinReg64 {
+	var sy, sxy, syy float64
+
+	for i, v := range period {
+		sy += v
+		sxy += float64(i) * v
+		syy += v * v
+	}
+
+	return linRegFromSums(len(period), sy, sxy, syy)
+}
+
+// LinRegFit32 is 32 bit version of LinRegFit64
+func LinRegFit32(period []float32) LinReg32 {
+	var sy, sxy, syy float64
+
+	for i, v := range period {
+		sy += float64(v)
+		sxy += float64(i) * float64(v)
+		syy += float64(v) * float64(v)
+	}
+
+	return linRegFromSums(len(period), sy, sxy, syy).to32()
+}
+
+// LinRegSlope64 computes the slope of the least squares line of the given period
+func LinRegSlope64(period []float64) float64 {
+	return LinRegFit64(period).Slope
+}
+
+// LinRegSlope32 is 32 bit version of LinRegSlope64
+func LinRegSlope32(period []float32) float32 {
+	return LinRegFit32(period).Slope
+}
+
+// LinRegIntercept64 computes the intercept of the least squares line of the given period
+// i.e. the fitted value at the oldest value of the period
+func LinRegIntercept64(period []float64) float64 {
+	return LinRegFit64(period).Intercept
+}
+
+// LinRegIntercept32 is 32 bit version of LinRegIntercept64
+func LinRegIntercept32(period []float32) float32 {
+	return LinRegFit32(period).Intercept
+}
+
+// LinRegForecast64 computes the Time Series Forecast (TSF) of the given period
+// i.e. the least squares line projected one value past the most recent value
+func LinRegForecast64(period []float64) float64 {
+	return LinRegFit64(period).Forecast()
+}
+
+// LinRegForecast32 is 32 bit version of LinRegForecast64
+func LinRegForecast32(period []float32) float32 {
+	return LinRegFit32(period).Forecast()
+}
+
+// LinRegRSquared64 computes the coefficient of determination (R²) of the least squares line of the given period
+func LinRegRSquared64(period []float64) float64 {
+	return LinRegFit64(period).R2
+}
+
+// LinRegRSquared32 is 32 bit version of LinRegRSquared64
+func LinRegRSquared32(period []float32) float32 {
+	return LinRegFit32(period).R2
+}
+
+// LinRegBound64 creates a float64 Linear Regression Channel Bound for the given fitted line
+// The midpoint is the fitted value at the most recent value of the period
+//
+// Parameters:
+//
+//	l: the least squares line of the period
+//	a (alpha): multiplier on the standard error of the line
+func LinRegBound64(l LinReg64, a float64) Bound64 {
+	var b Bound64
+
+	leg := l.StdErr * a
+
+	b.Midpoint = l.Endpoint()
+	b.Lower = b.Midpoint - leg
+	b.Upper = b.Midpoint + leg
+
+	return b
+}
+
+// LinRegBound32 is 32 bit version of LinRegBound64
+func LinRegBound32(l LinReg32, a float32) Bound32 {
+	var b Bound32
+
+	leg := l.StdErr * a
+
+	b.Midpoint = l.Endpoint()
+	b.Lower = b.Midpoint - leg
+	b.Upper = b.Midpoint + leg
+
+	return b
+}
+
+// RollingLinRegChannel64 computes a float64 Linear Regression Channel Bound for a given period in a series
+// Usage: Call while iterating over a series of values to build a full Linear Regression Channel.
+//
+// Parameters:
+//
+//	period: list of float values
+//	a (alpha): multiplier on the standard error of the period
+//
+// Returns an empty Bound64 for an empty period
+func RollingLinRegChannel64(period []float64, a float64) Bound64 {
+	if len(period) == 0 {
+		return Bound64{}
+	}
+
+	return LinRegBound64(LinRegFit64(period), a)
+}
+
+// RollingLinRegChannel32 is 32 bit version of RollingLinRegChannel64
+func RollingLinRegChannel32(period []float32, a float32) Bound32 {
+	if len(period) == 0 {
+		return Bound32{}
+	}
+
+	return LinRegBound32(LinRegFit32(period), a)
+}
+
+// StaticLinReg64 fits a least squares line for every period of lookback lb in a series
+// If time series data, assumes ascending order.
+// Each line is computed in O(1) from running sums of the prior line.
+// Values before the first complete period are the zero LinReg64.
+// Returns nil if the series is empty or lb <= 0. See StaticLinRegChecked64 to validate the parameters instead.
+//
+// Parameters:
+//
+//	series: data series
+//	lb: lookback to derive a period
+func StaticLinReg64(series []float64, lb int) []LinReg64 {
+	if len(series) == 0 || lb <= 0 {
+		return nil
+	}
+
+	sums := newLinRegSums(lb)
+
+	fits := make([]LinReg64, len(series))
+	for i, v := range series {
+		sums.push(v)
+
+		j := i + 1 // offset by 1 bc of idx
+		if j < lb {
+			continue
+		}
+
+		fits[i] = sums.fit()
+	}
+
+	return fits
+}
+
+// StaticLinReg32 is 32 bit version of StaticLinReg64
+func StaticLinReg32(series []float32, lb int) []LinReg32 {
+	if len(series) == 0 || lb <= 0 {
+		return nil
+	}
+
+	sums := newLinRegSums(lb)
+
+	fits := make([]LinReg32, len(series))
+	for i, v := range series {
+		sums.push(float64(v))
+
+		j := i + 1 // offset by 1 bc of idx
+		if j < lb {
+			continue
+		}
+
+		fits[i] = sums.fit().to32()
+	}
+
+	return fits
+}
+
+// StaticLinRegChannel64 creates a float64 Linear Regression Channel using a static standard error multiplier and period lookback
+// If time series data, assumes ascending order.
+//
+// Parameters:
+//
+//	series: data series
+//	lb: lookback to derive a period
+//	a (alpha): multiplier on the standard error of the period
+//
+// Returns nil if the series is empty or lb <= 0. See StaticLinRegChannelChecked64 to validate the parameters instead.
+func StaticLinRegChannel64(series []float64, lb int, a float64) []Bound64 {
+	fits := StaticLinReg64(series, lb)
+	if fits == nil {
+		return nil
+	}
+
+	band := make([]Bound64, len(fits))
+	for i, l := range fits {
+		if l.N == 0 {
+			continue
+		}
+
+		band[i] = LinRegBound64(l, a)
+	}
+
+	return band
+}
+
+// StaticLinRegChannel32 is 32 bit version of StaticLinRegChannel64
+func StaticLinRegChannel32(series []float32, lb int, a float32) []Bound32 {
+	fits := StaticLinReg32(series, lb)
+	if fits == nil {
+		return nil
+	}
+
+	band := make([]Bound32, len(fits))
+	for i, l := range fits {
+		if l.N == 0 {
+			continue
+		}
+
+		band[i] = LinRegBound32(l, a)
+	}
+
+	return band
+}
+
+// StaticLinRegChecked64 is StaticLinReg64 returning an error for invalid parameters
+// Returns ErrEmptySeries and ErrInvalidLookback if lb <= 0 or lb > len(series)
+func StaticLinRegChecked64(series []float64, lb int) ([]LinReg64, error) {
+	if err := checkSeries(len(series), lb); err != nil {
+		return nil, err
+	}
+
+	return StaticLinReg64(series, lb), nil
+}
+
+// StaticLinRegChecked32 is 32 bit version of StaticLinRegChecked64
+func StaticLinRegChecked32(series []float32, lb int) ([]LinReg32, error) {
+	if err := checkSeries(len(series), lb); err != nil {
+		return nil, err
+	}
+
+	return StaticLinReg32(series, lb), nil
+}
+
+// StaticLinRegChannelChecked64 is StaticLinRegChannel64 returning an error for invalid parameters
+// Returns ErrEmptySeries, ErrInvalidLookback if lb <= 0 or lb > len(series) and ErrInvalidMultiplier if a < 0
+func StaticLinRegChannelChecked64(series []float64, lb int, a float64) ([]Bound64, error) {
+	if err := checkBollinger(len(series), lb, a); err != nil {
+		return nil, err
+	}
+
+	return StaticLinRegChannel64(series, lb, a), nil
+}
+
+// StaticLinRegChannelChecked32 is 32 bit version of StaticLinRegChannelChecked64
+func StaticLinRegChannelChecked32(series []float32, lb int, a float32) ([]Bound32, error) {
+	if err := checkBollinger(len(series), lb, float64(a)); err != nil {
+		return nil, err
+	}
+
+	return StaticLinRegChannel32(series, lb, a), nil
+}
+
+// LinRegStream64 fits a least squares line over the last lb values of a stream in O(1) per update
+type LinRegStream64 struct {
+	lb   int
+	sums linRegSums
+}
+
+// NewLinRegStream64 creates a LinRegStream64 with lookback lb
+// Returns ErrInvalidLookback if lb <= 0
+func NewLinRegStream64(lb int) (*LinRegStream64, error) {
+	if lb <= 0 {
+		return nil, ErrInvalidLookback
+	}
+
+	return &LinRegStream64{lb: lb, sums: newLinRegSums(lb)}, nil
+}
+
+// Update adds the next value of the stream
+func (s *LinRegStream64) Update(v float64) {
+	s.sums.push(v)
+}
+
+// Value returns the endpoint of the least squares line of the current period
+func (s *LinRegStream64) Value() float64 {
+	return s.sums.fit().Endpoint()
+}
+
+// Ready reports whether a full lookback of values has been seen
+func (s *LinRegStream64) Ready() bool {
+	return s.sums.n == s.lb
+}
+
+// WarmupPeriod returns the number of values before the stream is ready
+func (s *LinRegStream64) WarmupPeriod() int {
+	return s.lb
+}
+
+// Fit returns the least squares line of the current period
+// Before the stream is ready the line is fitted over the values seen so far
+func (s *LinRegStream64) Fit() LinReg64 {
+	return s.sums.fit()
+}
+
+// Channel returns the Linear Regression Channel Bound of the current period
+func (s *LinRegStream64) Channel(a float64) Bound64 {
+	if s.sums.n == 0 {
+		return Bound64{}
+	}
+
+	return LinRegBound64(s.sums.fit(), a)
+}
+
+// Reset clears all values from the stream
+func (s *LinRegStream64) Reset() {
+	s.sums.reset()
+}
+
+// Clone returns an independent copy of the stream
+func (s *LinRegStream64) Clone() *LinRegStream64 {
+	return &LinRegStream64{lb: s.lb, sums: s.sums.clone()}
+}
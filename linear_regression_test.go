@@ -0,0 +1,257 @@
+package technical
+
+import (
+	"bufio"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinRegFit64(t *testing.T) {
+	// empty
+	l := LinRegFit64(nil)
+	assert.Equal(t, LinReg64{}, l)
+	assert.Equal(t, 0.0, l.Forecast())
+
+	// single value
+	l = LinRegFit64([]float64{4})
+	assert.Equal(t, 0.0, l.Slope)
+	assert.Equal(t, 4.0, l.Intercept)
+	assert.Equal(t, 4.0, l.Forecast())
+
+	// perfect line
+	l = LinRegFit64([]float64{1, 3, 5, 7, 9})
+	assert.Equal(t, 2.0, l.Slope)
+	assert.Equal(t, 1.0, l.Intercept)
+	assert.Equal(t, 1.0, l.R2)
+	assert.Equal(t, 0.0, l.StdErr)
+	assert.Equal(t, 9.0, l.Endpoint())
+	assert.Equal(t, 11.0, l.Forecast())
+
+	// flat
+	l = LinRegFit64([]float64{5, 5, 5, 5})
+	assert.Equal(t, 0.0, l.Slope)
+	assert.Equal(t, 5.0, l.Intercept)
+	assert.Equal(t, 0.0, l.R2)
+
+	// noisy
+	l = LinRegFit64([]float64{2, 4, 3, 5, 6})
+	assert.InDelta(t, 0.9, l.Slope, 1e-12)
+	assert.InDelta(t, 2.2, l.Intercept, 1e-12)
+	assert.InDelta(t, 0.81, l.R2, 1e-12)
+	assert.InDelta(t, math.Sqrt(1.9/3), l.StdErr, 1e-12)
+	assert.InDelta(t, 5.8, l.Endpoint(), 1e-12)
+	assert.InDelta(t, 6.7, l.Forecast(), 1e-12)
+}
+
+func TestLinRegFit32(t *testing.T) {
+	// empty
+	l := LinRegFit32(nil)
+	assert.Equal(t, LinReg32{}, l)
+
+	// perfect line
+	l = LinRegFit32([]float32{1, 3, 5, 7, 9})
+	assert.Equal(t, float32(2.0), l.Slope)
+	assert.Equal(t, float32(1.0), l.Intercept)
+	assert.Equal(t, float32(1.0), l.R2)
+	assert.Equal(t, float32(11.0), l.Forecast())
+
+	// noisy
+	l = LinRegFit32([]float32{2, 4, 3, 5, 6})
+	assert.InDelta(t, 0.9, l.Slope, 1e-6)
+	assert.InDelta(t, 2.2, l.Intercept, 1e-6)
+	assert.InDelta(t, 0.81, l.R2, 1e-6)
+	assert.InDelta(t, 6.7, l.Forecast(), 1e-6)
+}
+
+func TestLinRegHelpers64(t *testing.T) {
+	p := []float64{2, 4, 3, 5, 6}
+
+	assert.InDelta(t, 0.9, LinRegSlope64(p), 1e-12)
+	assert.InDelta(t, 2.2, LinRegIntercept64(p), 1e-12)
+	assert.InDelta(t, 6.7, LinRegForecast64(p), 1e-12)
+	assert.InDelta(t, 0.81, LinRegRSquared64(p), 1e-12)
+}
+
+func TestLinRegHelpers32(t *testing.T) {
+	p := []float32{2, 4, 3, 5, 6}
+
+	assert.InDelta(t, 0.9, LinRegSlope32(p), 1e-6)
+	assert.InDelta(t, 2.2, LinRegIntercept32(p), 1e-6)
+	assert.InDelta(t, 6.7, LinRegForecast32(p), 1e-6)
+	assert.InDelta(t, 0.81, LinRegRSquared32(p), 1e-6)
+}
+
+func TestRollingLinRegChannel64(t *testing.T) {
+	assert.Equal(t, Bound64{}, RollingLinRegChannel64(nil, 2))
+
+	b := RollingLinRegChannel64([]float64{2, 4, 3, 5, 6}, 2)
+	leg := 2 * math.Sqrt(1.9/3)
+	assert.InDelta(t, 5.8, b.Midpoint, 1e-12)
+	assert.InDelta(t, 5.8-leg, b.Lower, 1e-12)
+	assert.InDelta(t, 5.8+leg, b.Upper, 1e-12)
+}
+
+func TestRollingLinRegChannel32(t *testing.T) {
+	assert.Equal(t, Bound32{}, RollingLinRegChannel32(nil, 2))
+
+	b := RollingLinRegChannel32([]float32{2, 4, 3, 5, 6}, 2)
+	leg := 2 * math.Sqrt(1.9/3)
+	assert.InDelta(t, 5.8, b.Midpoint, 1e-5)
+	assert.InDelta(t, 5.8-leg, b.Lower, 1e-5)
+	assert.InDelta(t, 5.8+leg, b.Upper, 1e-5)
+}
+
+func TestStaticLinReg64(t *testing.T) {
+	assert.Nil(t, StaticLinReg64(nil, 3))
+	assert.Nil(t, StaticLinReg64([]float64{1, 2}, 0))
+
+	s := []float64{1, 3, 5, 4, 2, 6, 8}
+	fits := StaticLinReg64(s, 3)
+	assert.Len(t, fits, len(s))
+	assert.Equal(t, LinReg64{}, fits[0])
+	assert.Equal(t, LinReg64{}, fits[1])
+
+	// running sums must match a direct fit of each period
+	for i := 2; i < len(s); i++ {
+		want := LinRegFit64(s[i-2 : i+1])
+		assert.InDelta(t, want.Slope, fits[i].Slope, 1e-12)
+		assert.InDelta(t, want.Intercept, fits[i].Intercept, 1e-12)
+		assert.InDelta(t, want.R2, fits[i].R2, 1e-12)
+		assert.InDelta(t, want.StdErr, fits[i].StdErr, 1e-12)
+	}
+
+	// full test series
+	var testseries []float64
+	f, err := os.Open("./mock/test_series.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		num, _ := strconv.ParseFloat(scanner.Text(), 64)
+		testseries = append(testseries, num)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	lb := 1200
+	fits = StaticLinReg64(testseries, lb)
+	want := LinRegFit64(testseries[len(testseries)-lb:])
+	last := fits[len(fits)-1]
+	assert.InDelta(t, want.Slope, last.Slope, 1e-9)
+	assert.InDelta(t, want.Intercept, last.Intercept, 1e-9)
+	assert.InDelta(t, want.R2, last.R2, 1e-9)
+}
+
+func TestStaticLinReg32(t *testing.T) {
+	assert.Nil(t, StaticLinReg32(nil, 3))
+
+	s := []float32{1, 3, 5, 4, 2, 6, 8}
+	fits := StaticLinReg32(s, 3)
+	assert.Len(t, fits, len(s))
+	assert.Equal(t, LinReg32{}, fits[1])
+
+	for i := 2; i < len(s); i++ {
+		want := LinRegFit32(s[i-2 : i+1])
+		assert.InDelta(t, want.Slope, fits[i].Slope, 1e-6)
+		assert.InDelta(t, want.Intercept, fits[i].Intercept, 1e-6)
+	}
+}
+
+func TestStaticLinRegChannel64(t *testing.T) {
+	assert.Nil(t, StaticLinRegChannel64(nil, 5, 2))
+
+	band := StaticLinRegChannel64([]float64{9, 2, 4, 3, 5, 6}, 5, 2)
+	leg := 2 * math.Sqrt(1.9/3)
+	assert.Equal(t, Bound64{}, band[3])
+	assert.InDelta(t, 5.8, band[5].Midpoint, 1e-12)
+	assert.InDelta(t, 5.8-leg, band[5].Lower, 1e-12)
+	assert.InDelta(t, 5.8+leg, band[5].Upper, 1e-12)
+}
+
+func TestStaticLinRegChannel32(t *testing.T) {
+	assert.Nil(t, StaticLinRegChannel32(nil, 5, 2))
+
+	band := StaticLinRegChannel32([]float32{9, 2, 4, 3, 5, 6}, 5, 2)
+	leg := 2 * math.Sqrt(1.9/3)
+	assert.Equal(t, Bound32{}, band[3])
+	assert.InDelta(t, 5.8, band[5].Midpoint, 1e-5)
+	assert.InDelta(t, 5.8+leg, band[5].Upper, 1e-5)
+}
+
+func TestStaticLinRegChecked64(t *testing.T) {
+	_, err := StaticLinRegChecked64(nil, 3)
+	assert.Equal(t, ErrEmptySeries, err)
+
+	_, err = StaticLinRegChecked64([]float64{1, 2}, 0)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	_, err = StaticLinRegChecked64([]float64{1, 2}, 3)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	fits, err := StaticLinRegChecked64([]float64{1, 3, 5}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, fits[2].Slope)
+}
+
+func TestStaticLinRegChecked32(t *testing.T) {
+	_, err := StaticLinRegChecked32([]float32{1, 2}, -1)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	fits, err := StaticLinRegChecked32([]float32{1, 3, 5}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(2.0), fits[2].Slope)
+}
+
+func TestStaticLinRegChannelChecked64(t *testing.T) {
+	_, err := StaticLinRegChannelChecked64([]float64{1, 2}, 2, -2)
+	assert.Equal(t, ErrInvalidMultiplier, err)
+
+	band, err := StaticLinRegChannelChecked64([]float64{1, 3, 5}, 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, band[2].Midpoint)
+}
+
+func TestStaticLinRegChannelChecked32(t *testing.T) {
+	_, err := StaticLinRegChannelChecked32([]float32{1, 2}, 2, -2)
+	assert.Equal(t, ErrInvalidMultiplier, err)
+
+	band, err := StaticLinRegChannelChecked32([]float32{1, 3, 5}, 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(5.0), band[2].Midpoint)
+}
+
+func TestLinRegStream64(t *testing.T) {
+	_, err := NewLinRegStream64(0)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	s, err := NewLinRegStream64(5)
+	assert.NoError(t, err)
+	assert.False(t, s.Ready())
+	assert.Equal(t, Bound64{}, s.Channel(2))
+
+	for _, v := range []float64{9, 2, 4, 3, 5} {
+		s.Update(v)
+	}
+	assert.True(t, s.Ready())
+
+	s.Update(6)
+	l := s.Fit()
+	assert.InDelta(t, 0.9, l.Slope, 1e-12)
+	assert.InDelta(t, 6.7, l.Forecast(), 1e-12)
+	assert.InDelta(t, 5.8, s.Channel(2).Midpoint, 1e-12)
+
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, LinReg64{}, s.Fit())
+}
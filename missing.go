@@ -0,0 +1,418 @@
+package technical
+
+import (
+	"math"
+)
+
+/*
+* Missing data handling.
+*
+* By default the package functions propagate missing values: a NaN or Inf in a period results in a NaN or Inf value,
+* as with plain float arithmetic. TrueRange64 additionally skips non positive values as gaps, the same as
+* a MissingPolicy of mode MissingSkip with ZeroIsMissing, so zero gaps are only filled or rejected through a policy.
+*
+* A MissingPolicy applies a consistent, configurable handling of missing values to the stats, Bollinger and ATR functions.
+* Its methods mirror the package functions of the same name. Series methods always return a series index aligned with
+* the input: when a missing value is skipped the indicator is not updated and the prior output is repeated.
+ */
+
+// MissingMode determines how a MissingPolicy handles missing values
+type MissingMode int
+
+const (
+	// MissingPropagate lets missing values propagate through computations as NaN
+	MissingPropagate MissingMode = iota
+	// MissingSkip drops missing values from computations
+	MissingSkip
+	// MissingForwardFill replaces missing values with the last valid value.
+	// Leading missing values with no prior valid value are skipped.
+	MissingForwardFill
+	// MissingError fails with ErrMissingData on any missing value
+	MissingError
+)
+
+// MissingPolicy configures how missing values are handled
+// A value is missing if it is NaN or ±Inf, or 0.0 if ZeroIsMissing is set.
+type MissingPolicy struct {
+	Mode          MissingMode
+	ZeroIsMissing bool
+}
+
+// IsMissing64 reports whether v is a missing value under the policy
+func (p MissingPolicy) IsMissing64(v float64) bool {
+	return math.IsNaN(v) || math.IsInf(v, 0) || (p.ZeroIsMissing && v == 0.0)
+}
+
+// IsMissing32 is 32 bit version of IsMissing64
+func (p MissingPolicy) IsMissing32(v float32) bool {
+	return p.IsMissing64(float64(v))
+}
+
+// compact64 applies the policy to xs
+// Returns the values to compute on and the index of each in xs
+func (p MissingPolicy) compact64(xs []float64) ([]float64, []int, error) {
+	vals := make([]float64, 0, len(xs))
+	idx := make([]int, 0, len(xs))
+
+	var (
+		last    float64
+		hasLast bool
+	)
+
+	for i, v := range xs {
+		if !p.IsMissing64(v) {
+			vals = append(vals, v)
+			idx = append(idx, i)
+			last, hasLast = v, true
+			continue
+		}
+
+		switch p.Mode {
+		case MissingPropagate:
+			if !math.IsInf(v, 0) {
+				v = math.NaN() // zero gaps propagate as NaN
+			}
+
+			vals = append(vals, v)
+			idx = append(idx, i)
+		case MissingForwardFill:
+			if hasLast {
+				vals = append(vals, last)
+				idx = append(idx, i)
+			}
+		case MissingError:
+			return nil, nil, ErrMissingData
+		}
+	}
+
+	return vals, idx, nil
+}
+
+// compact32 is 32 bit version of compact64
+func (p MissingPolicy) compact32(xs []float32) ([]float32, []int, error) {
+	xs64 := make([]float64, len(xs))
+	for i, v := range xs {
+		xs64[i] = float64(v)
+	}
+
+	vals64, idx, err := p.compact64(xs64)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vals := make([]float32, len(vals64))
+	for i, v := range vals64 {
+		vals[i] = float32(v)
+	}
+
+	return vals, idx, nil
+}
+
+// expand64 maps the outputs computed on compacted values back to a series of size n
+// Positions that were skipped repeat the prior output
+func expand64(out []float64, idx []int, n int) []float64 {
+	res := make([]float64, n)
+
+	k := 0
+	for i := range res {
+		if k < len(idx) && idx[k] == i {
+			res[i] = out[k]
+			k++
+			continue
+		}
+
+		if i > 0 {
+			res[i] = res[i-1]
+		}
+	}
+
+	return res
+}
+
+// expand32 is 32 bit version of expand64
+func expand32(out []float32, idx []int, n int) []float32 {
+	res := make([]float32, n)
+
+	k := 0
+	for i := range res {
+		if k < len(idx) && idx[k] == i {
+			res[i] = out[k]
+			k++
+			continue
+		}
+
+		if i > 0 {
+			res[i] = res[i-1]
+		}
+	}
+
+	return res
+}
+
+// expandBounds64 is expand64 for a Bollinger Band
+func expandBounds64(out []Bound64, idx []int, n int) []Bound64 {
+	res := make([]Bound64, n)
+
+	k := 0
+	for i := range res {
+		if k < len(idx) && idx[k] == i {
+			res[i] = out[k]
+			k++
+			continue
+		}
+
+		if i > 0 {
+			res[i] = res[i-1]
+		}
+	}
+
+	return res
+}
+
+// expandBounds32 is 32 bit version of expandBounds64
+func expandBounds32(out []Bound32, idx []int, n int) []Bound32 {
+	res := make([]Bound32, n)
+
+	k := 0
+	for i := range res {
+		if k < len(idx) && idx[k] == i {
+			res[i] = out[k]
+			k++
+			continue
+		}
+
+		if i > 0 {
+			res[i] = res[i-1]
+		}
+	}
+
+	return res
+}
+
+// Clean64 applies the policy to a list of values
+// Skipped values are removed so the result may be shorter than xs.
+// Missing values that propagate are NaN or ±Inf.
+// Returns ErrMissingData if the mode is MissingError and xs has a missing value.
+func (p MissingPolicy) Clean64(xs []float64) ([]float64, error) {
+	vals, _, err := p.compact64(xs)
+
+	return vals, err
+}
+
+// Clean32 is 32 bit version of Clean64
+func (p MissingPolicy) Clean32(xs []float32) ([]float32, error) {
+	vals, _, err := p.compact32(xs)
+
+	return vals, err
+}
+
+// SimpleAvg64 is SimpleAvg64 applying the policy to xs
+func (p MissingPolicy) SimpleAvg64(xs []float64) (float64, error) {
+	vals, _, err := p.compact64(xs)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return SimpleAvg64(vals), nil
+}
+
+// SimpleAvg32 is 32 bit version of MissingPolicy.SimpleAvg64
+func (p MissingPolicy) SimpleAvg32(xs []float32) (float32, error) {
+	vals, _, err := p.compact32(xs)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return SimpleAvg32(vals), nil
+}
+
+// Variance64 is Variance64 applying the policy to xs
+func (p MissingPolicy) Variance64(xs []float64) (float64, error) {
+	vals, _, err := p.compact64(xs)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return Variance64(vals), nil
+}
+
+// Variance32 is 32 bit version of MissingPolicy.Variance64
+func (p MissingPolicy) Variance32(xs []float32) (float32, error) {
+	vals, _, err := p.compact32(xs)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return Variance32(vals), nil
+}
+
+// StdDev64 is StdDev64 applying the policy to xs
+func (p MissingPolicy) StdDev64(xs []float64) (float64, error) {
+	vals, _, err := p.compact64(xs)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return StdDev64(vals), nil
+}
+
+// StdDev32 is 32 bit version of MissingPolicy.StdDev64
+func (p MissingPolicy) StdDev32(xs []float32) (float32, error) {
+	vals, _, err := p.compact32(xs)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return StdDev32(vals), nil
+}
+
+// EwmaSeries64 is EwmaSeries64 applying the policy to the series
+// A skipped value does not update the EWMA, the prior EWMA is repeated at its index.
+func (p MissingPolicy) EwmaSeries64(series []float64, y float64, lb int) ([]float64, error) {
+	vals, idx, err := p.compact64(series)
+	if err != nil || len(series) == 0 {
+		return nil, err
+	}
+
+	return expand64(EwmaSeries64(vals, y, lb), idx, len(series)), nil
+}
+
+// EwmaSeries32 is 32 bit version of MissingPolicy.EwmaSeries64
+func (p MissingPolicy) EwmaSeries32(series []float32, y float32, lb int) ([]float32, error) {
+	vals, idx, err := p.compact32(series)
+	if err != nil || len(series) == 0 {
+		return nil, err
+	}
+
+	return expand32(EwmaSeries32(vals, y, lb), idx, len(series)), nil
+}
+
+// StaticBollingerConst64 is StaticBollingerConst64 applying the policy to the series
+// A skipped value is excluded from every period, the prior bound is repeated at its index.
+func (p MissingPolicy) StaticBollingerConst64(series []float64, lb int, k float64, a float64) ([]Bound64, error) {
+	vals, idx, err := p.compact64(series)
+	if err != nil || len(series) == 0 {
+		return nil, err
+	}
+
+	return expandBounds64(StaticBollingerConst64(vals, lb, k, a), idx, len(series)), nil
+}
+
+// StaticBollingerConst32 is 32 bit version of MissingPolicy.StaticBollingerConst64
+func (p MissingPolicy) StaticBollingerConst32(series []float32, lb int, k float32, a float32) ([]Bound32, error) {
+	vals, idx, err := p.compact32(series)
+	if err != nil || len(series) == 0 {
+		return nil, err
+	}
+
+	return expandBounds32(StaticBollingerConst32(vals, lb, k, a), idx, len(series)), nil
+}
+
+// StaticBollingerSMA64 is StaticBollingerSMA64 applying the policy to the series
+// A skipped value is excluded from every period, the prior bound is repeated at its index.
+func (p MissingPolicy) StaticBollingerSMA64(series []float64, lb int, a float64) ([]Bound64, error) {
+	vals, idx, err := p.compact64(series)
+	if err != nil || len(series) == 0 {
+		return nil, err
+	}
+
+	return expandBounds64(StaticBollingerSMA64(vals, lb, a), idx, len(series)), nil
+}
+
+// StaticBollingerSMA32 is 32 bit version of MissingPolicy.StaticBollingerSMA64
+func (p MissingPolicy) StaticBollingerSMA32(series []float32, lb int, a float32) ([]Bound32, error) {
+	vals, idx, err := p.compact32(series)
+	if err != nil || len(series) == 0 {
+		return nil, err
+	}
+
+	return expandBounds32(StaticBollingerSMA32(vals, lb, a), idx, len(series)), nil
+}
+
+// StaticBollingerEMA64 is StaticBollingerEMA64 applying the policy to the series
+// A skipped value is excluded from every period, the prior bound is repeated at its index.
+func (p MissingPolicy) StaticBollingerEMA64(series []float64, lb int, y float64, a float64) ([]Bound64, error) {
+	vals, idx, err := p.compact64(series)
+	if err != nil || len(series) == 0 {
+		return nil, err
+	}
+
+	return expandBounds64(StaticBollingerEMA64(vals, lb, y, a), idx, len(series)), nil
+}
+
+// StaticBollingerEMA32 is 32 bit version of MissingPolicy.StaticBollingerEMA64
+func (p MissingPolicy) StaticBollingerEMA32(series []float32, lb int, y float32, a float32) ([]Bound32, error) {
+	vals, idx, err := p.compact32(series)
+	if err != nil || len(series) == 0 {
+		return nil, err
+	}
+
+	return expandBounds32(StaticBollingerEMA32(vals, lb, y, a), idx, len(series)), nil
+}
+
+// TrueRange64 is TrueRange64 applying the policy to the period and last value
+// If last is NaN or Inf it is treated as no prior close (0.0) unless the mode is MissingPropagate or MissingError.
+// A last of 0.0 is the no prior close of TrueRange64 and is never missing, even with ZeroIsMissing.
+// Zeros in the period are missing only with ZeroIsMissing, and negative values remain ignored by the true range itself,
+// so TrueRange64 is this method with mode MissingSkip and ZeroIsMissing for periods without NaN or Inf.
+func (p MissingPolicy) TrueRange64(period []float64, last float64) (float64, error) {
+	vals, _, err := p.compact64(period)
+	if err != nil {
+		return 0.0, err
+	}
+
+	if math.IsNaN(last) || math.IsInf(last, 0) {
+		switch p.Mode {
+		case MissingPropagate:
+			return math.NaN(), nil
+		case MissingError:
+			return 0.0, ErrMissingData
+		default:
+			last = 0.0
+		}
+	}
+
+	return TrueRange64(vals, last), nil
+}
+
+// TrueRange32 is 32 bit version of MissingPolicy.TrueRange64
+func (p MissingPolicy) TrueRange32(period []float32, last float32) (float32, error) {
+	vals, _, err := p.compact32(period)
+	if err != nil {
+		return 0.0, err
+	}
+
+	if f := float64(last); math.IsNaN(f) || math.IsInf(f, 0) {
+		switch p.Mode {
+		case MissingPropagate:
+			return float32(math.NaN()), nil
+		case MissingError:
+			return 0.0, ErrMissingData
+		default:
+			last = 0.0
+		}
+	}
+
+	return TrueRange32(vals, last), nil
+}
+
+// StaticATR64 is StaticATR64 applying the policy to the series
+// Skipped values are removed before the series is divided into periods of size s.
+func (p MissingPolicy) StaticATR64(series []float64, n int, s int) (float64, error) {
+	vals, _, err := p.compact64(series)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return StaticATR64(vals, n, s), nil
+}
+
+// StaticATR32 is 32 bit version of MissingPolicy.StaticATR64
+func (p MissingPolicy) StaticATR32(series []float32, n int, s int) (float32, error) {
+	vals, _, err := p.compact32(series)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return StaticATR32(vals, n, s), nil
+}
@@ -0,0 +1,319 @@
+package technical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	nan  = math.NaN()
+	pinf = math.Inf(1)
+)
+
+func TestMissingPolicyIsMissing(t *testing.T) {
+	p := MissingPolicy{}
+	assert.True(t, p.IsMissing64(nan))
+	assert.True(t, p.IsMissing64(pinf))
+	assert.True(t, p.IsMissing64(math.Inf(-1)))
+	assert.False(t, p.IsMissing64(0.0))
+	assert.False(t, p.IsMissing64(1.0))
+
+	p.ZeroIsMissing = true
+	assert.True(t, p.IsMissing64(0.0))
+	assert.True(t, p.IsMissing32(0.0))
+	assert.True(t, p.IsMissing32(float32(nan)))
+	assert.False(t, p.IsMissing32(1.0))
+}
+
+func TestMissingPolicyClean64(t *testing.T) {
+	xs := []float64{nan, 1, 0, pinf, 4}
+
+	// propagate, zero gaps propagate as NaN
+	vals, err := MissingPolicy{Mode: MissingPropagate, ZeroIsMissing: true}.Clean64(xs)
+	assert.NoError(t, err)
+	assert.Len(t, vals, 5)
+	assert.True(t, math.IsNaN(vals[0]))
+	assert.True(t, math.IsNaN(vals[2]))
+	assert.True(t, math.IsInf(vals[3], 1))
+
+	// skip
+	vals, err = MissingPolicy{Mode: MissingSkip}.Clean64(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 0, 4}, vals)
+
+	vals, err = MissingPolicy{Mode: MissingSkip, ZeroIsMissing: true}.Clean64(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 4}, vals)
+
+	// forward fill, leading missing is skipped
+	vals, err = MissingPolicy{Mode: MissingForwardFill, ZeroIsMissing: true}.Clean64(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1, 1, 4}, vals)
+
+	// error
+	_, err = MissingPolicy{Mode: MissingError}.Clean64(xs)
+	assert.Equal(t, ErrMissingData, err)
+
+	vals, err = MissingPolicy{Mode: MissingError}.Clean64([]float64{1, 0, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 0, 4}, vals)
+}
+
+func TestMissingPolicyClean32(t *testing.T) {
+	xs := []float32{float32(nan), 1, 0, float32(pinf), 4}
+
+	vals, err := MissingPolicy{Mode: MissingSkip, ZeroIsMissing: true}.Clean32(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{1, 4}, vals)
+
+	vals, err = MissingPolicy{Mode: MissingForwardFill}.Clean32(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{1, 0, 0, 4}, vals)
+
+	_, err = MissingPolicy{Mode: MissingError}.Clean32(xs)
+	assert.Equal(t, ErrMissingData, err)
+}
+
+func TestMissingPolicyStats64(t *testing.T) {
+	xs := []float64{1, nan, 3, 0, 5, pinf, 7}
+
+	// package default propagates NaN consistently
+	assert.True(t, math.IsNaN(SimpleAvg64(xs)))
+	assert.True(t, math.IsNaN(Variance64(xs)))
+	assert.True(t, math.IsNaN(StdDev64(xs)))
+
+	p := MissingPolicy{Mode: MissingPropagate}
+	v, err := p.StdDev64(xs)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(v))
+
+	p = MissingPolicy{Mode: MissingSkip, ZeroIsMissing: true}
+	v, err = p.SimpleAvg64(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, v)
+
+	v, err = p.Variance64(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, v)
+
+	v, err = p.StdDev64(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, math.Sqrt(5.0), v)
+
+	// forward fill: 1 1 3 3 5 5 7
+	p = MissingPolicy{Mode: MissingForwardFill, ZeroIsMissing: true}
+	v, err = p.SimpleAvg64(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, 25.0/7.0, v)
+
+	p = MissingPolicy{Mode: MissingError}
+	_, err = p.SimpleAvg64(xs)
+	assert.Equal(t, ErrMissingData, err)
+
+	_, err = p.Variance64(xs)
+	assert.Equal(t, ErrMissingData, err)
+
+	_, err = p.StdDev64(xs)
+	assert.Equal(t, ErrMissingData, err)
+}
+
+func TestMissingPolicyStats32(t *testing.T) {
+	xs := []float32{1, float32(nan), 3, 0, 5, float32(pinf), 7}
+
+	assert.True(t, math.IsNaN(float64(StdDev32(xs))))
+
+	p := MissingPolicy{Mode: MissingSkip, ZeroIsMissing: true}
+	v, err := p.SimpleAvg32(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(4.0), v)
+
+	v, err = p.Variance32(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(5.0), v)
+
+	v, err = p.StdDev32(xs)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(math.Sqrt(5.0)), v)
+
+	_, err = MissingPolicy{Mode: MissingError}.StdDev32(xs)
+	assert.Equal(t, ErrMissingData, err)
+}
+
+func TestMissingPolicyEwmaSeries64(t *testing.T) {
+	xs := []float64{2, nan, 4, 0, 6}
+
+	// skip: ewma of 2, 4, 6 with lb 2 is 0 3 5, repeated at skipped indices
+	p := MissingPolicy{Mode: MissingSkip, ZeroIsMissing: true}
+	ewmas, err := p.EwmaSeries64(xs, 0.5, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 0, 3, 3, 4.5}, ewmas)
+
+	// forward fill: ewma of 2 2 4 4 6
+	p = MissingPolicy{Mode: MissingForwardFill, ZeroIsMissing: true}
+	ewmas, err = p.EwmaSeries64(xs, 0.5, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 2, 3, 3.5, 4.75}, ewmas)
+
+	// propagate
+	p = MissingPolicy{Mode: MissingPropagate}
+	ewmas, err = p.EwmaSeries64(xs, 0.5, 2)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(ewmas[4]))
+
+	_, err = MissingPolicy{Mode: MissingError}.EwmaSeries64(xs, 0.5, 2)
+	assert.Equal(t, ErrMissingData, err)
+
+	ewmas, err = p.EwmaSeries64(nil, 0.5, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, ewmas)
+}
+
+func TestMissingPolicyEwmaSeries32(t *testing.T) {
+	xs := []float32{2, float32(nan), 4, 0, 6}
+
+	p := MissingPolicy{Mode: MissingSkip, ZeroIsMissing: true}
+	ewmas, err := p.EwmaSeries32(xs, 0.5, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0, 0, 3, 3, 4.5}, ewmas)
+}
+
+func TestMissingPolicyStaticBollinger64(t *testing.T) {
+	xs := []float64{1, 3, nan, 5, 7, 0, 9}
+	clean := []float64{1, 3, 5, 7, 9}
+
+	p := MissingPolicy{Mode: MissingSkip, ZeroIsMissing: true}
+
+	band, err := p.StaticBollingerSMA64(xs, 3, 2)
+	assert.NoError(t, err)
+	want := StaticBollingerSMA64(clean, 3, 2)
+	assert.Equal(t, []Bound64{want[0], want[1], want[1], want[2], want[3], want[3], want[4]}, band)
+
+	band, err = p.StaticBollingerEMA64(xs, 3, 0.0, 2)
+	assert.NoError(t, err)
+	want = StaticBollingerEMA64(clean, 3, 0.0, 2)
+	assert.Equal(t, want[4], band[6])
+
+	band, err = p.StaticBollingerConst64(xs, 3, 0.0, 2)
+	assert.NoError(t, err)
+	want = StaticBollingerConst64(clean, 3, 0.0, 2)
+	assert.Equal(t, want[4], band[6])
+
+	// default propagates NaN through the period instead of a zero width band
+	band = StaticBollingerSMA64(xs, 3, 2)
+	assert.True(t, math.IsNaN(band[3].Upper))
+
+	_, err = MissingPolicy{Mode: MissingError}.StaticBollingerSMA64(xs, 3, 2)
+	assert.Equal(t, ErrMissingData, err)
+}
+
+func TestMissingPolicyStaticBollinger32(t *testing.T) {
+	xs := []float32{1, 3, float32(nan), 5, 7, 0, 9}
+	clean := []float32{1, 3, 5, 7, 9}
+
+	p := MissingPolicy{Mode: MissingSkip, ZeroIsMissing: true}
+
+	band, err := p.StaticBollingerSMA32(xs, 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticBollingerSMA32(clean, 3, 2)[4], band[6])
+
+	band, err = p.StaticBollingerEMA32(xs, 3, 0.0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticBollingerEMA32(clean, 3, 0.0, 2)[4], band[6])
+
+	band, err = p.StaticBollingerConst32(xs, 3, 0.0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticBollingerConst32(clean, 3, 0.0, 2)[4], band[6])
+}
+
+func TestMissingPolicyTrueRange64(t *testing.T) {
+	period := []float64{1, nan, 4, 0, 9}
+
+	// default propagates NaN, still ignores zero gaps
+	assert.True(t, math.IsNaN(TrueRange64(period, 0.0)))
+	assert.Equal(t, 8.0, TrueRange64([]float64{1, 4, 0, 9}, 0.0))
+
+	tr, err := MissingPolicy{Mode: MissingSkip}.TrueRange64(period, 11.0)
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, tr)
+
+	// missing last is treated as no prior close
+	tr, err = MissingPolicy{Mode: MissingSkip}.TrueRange64(period, nan)
+	assert.NoError(t, err)
+	assert.Equal(t, 8.0, tr)
+
+	tr, err = MissingPolicy{Mode: MissingPropagate}.TrueRange64([]float64{1, 9}, nan)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(tr))
+
+	_, err = MissingPolicy{Mode: MissingError}.TrueRange64([]float64{1, 9}, pinf)
+	assert.Equal(t, ErrMissingData, err)
+
+	_, err = MissingPolicy{Mode: MissingError, ZeroIsMissing: true}.TrueRange64([]float64{1, 0, 9}, 1)
+	assert.Equal(t, ErrMissingData, err)
+
+	// the default gaps are the skip mode with zeros missing
+	skip := MissingPolicy{Mode: MissingSkip, ZeroIsMissing: true}
+	for _, p := range [][]float64{{1, 4, 0, 9}, {0, 0}, {3, -1, 2}} {
+		for _, last := range []float64{0, 2, 11} {
+			tr, err = skip.TrueRange64(p, last)
+			assert.NoError(t, err)
+			assert.Equal(t, TrueRange64(p, last), tr)
+		}
+	}
+
+	// an ATR starts with no prior close, which is not a missing zero
+	series := readMockSeries64("mock/test_series.txt")[:60]
+	for _, mode := range []MissingMode{MissingPropagate, MissingError} {
+		policy := MissingPolicy{Mode: mode, ZeroIsMissing: true}
+		last := 0.0
+		for i := 0; i < len(series); i += 10 {
+			period := series[i : i+10]
+			tr, err = policy.TrueRange64(period, last)
+			assert.NoError(t, err)
+			assert.Equal(t, TrueRange64(period, last), tr)
+			last = period[len(period)-1]
+		}
+	}
+}
+
+func TestMissingPolicyTrueRange32(t *testing.T) {
+	period := []float32{1, float32(nan), 4, 0, 9}
+
+	assert.True(t, math.IsNaN(float64(TrueRange32(period, 0.0))))
+
+	tr, err := MissingPolicy{Mode: MissingSkip}.TrueRange32(period, 11.0)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(10.0), tr)
+
+	_, err = MissingPolicy{Mode: MissingError}.TrueRange32(period, 11.0)
+	assert.Equal(t, ErrMissingData, err)
+
+	tr, err = MissingPolicy{Mode: MissingError, ZeroIsMissing: true}.TrueRange32([]float32{1, 9}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(8.0), tr)
+}
+
+func TestMissingPolicyStaticATR64(t *testing.T) {
+	s := []float64{1, 4, 2, nan, 7, 9, 4, 5, 6, 7, 9, pinf, 4, 7, 6, 9, 2, 3, 10, 12, 11, 15}
+	clean := []float64{1, 4, 2, 7, 9, 4, 5, 6, 7, 9, 4, 7, 6, 9, 2, 3, 10, 12, 11, 15}
+
+	atr, err := MissingPolicy{Mode: MissingSkip}.StaticATR64(s, 3, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticATR64(clean, 3, 5), atr)
+
+	assert.True(t, math.IsNaN(StaticATR64(s, 3, 5)))
+
+	_, err = MissingPolicy{Mode: MissingError}.StaticATR64(s, 3, 5)
+	assert.Equal(t, ErrMissingData, err)
+}
+
+func TestMissingPolicyStaticATR32(t *testing.T) {
+	s := []float32{1, 4, 2, float32(nan), 7, 9, 4, 5, 6, 7, 9, 4, 7, 6, 9, 2, 3, 10, 12, 11, 15}
+	clean := []float32{1, 4, 2, 7, 9, 4, 5, 6, 7, 9, 4, 7, 6, 9, 2, 3, 10, 12, 11, 15}
+
+	atr, err := MissingPolicy{Mode: MissingSkip}.StaticATR32(s, 3, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticATR32(clean, 3, 5), atr)
+}
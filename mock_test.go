@@ -0,0 +1,59 @@
+package technical
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// mockSessionOpen is the open of the session the mock series are sampled from
+var mockSessionOpen = time.Date(2018, time.June, 15, 9, 30, 0, 0, time.UTC)
+
+// readMockSeries64 reads a mock series file of one value per line
+func readMockSeries64(path string) []float64 {
+	var series []float64
+
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		num, _ := strconv.ParseFloat(scanner.Text(), 64)
+		series = append(series, num)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	return series
+}
+
+// readMockSeries32 is 32 bit version of readMockSeries64
+func readMockSeries32(path string) []float32 {
+	series64 := readMockSeries64(path)
+
+	series := make([]float32, len(series64))
+	for i, v := range series64 {
+		series[i] = float32(v)
+	}
+
+	return series
+}
+
+// mockSessionTimes returns n timestamps one second apart starting at mockSessionOpen
+// i.e. the timestamps of the per second mock series
+func mockSessionTimes(n int) []time.Time {
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = mockSessionOpen.Add(time.Duration(i) * time.Second)
+	}
+
+	return times
+}
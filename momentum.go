@@ -0,0 +1,535 @@
+package technical
+
+import (
+	"math"
+)
+
+/*
+* Momentum oscillators measure the speed of price changes:
+*
+*	Momentum: the change over n periods, x[i] - x[i-n]
+*	Rate of Change (ROC): the percentage change over n periods, 100 * (x[i] - x[i-n]) / x[i-n]
+*	TRIX: the percentage change of a triple EWMA of lookback n, filtering out cycles shorter than the lookback
+*	Percentage Price Oscillator (PPO): the difference of a fast and slow EWMA as a percentage of the slow EWMA
+*	Chande Momentum Oscillator (CMO): the sum of gains less the sum of losses of n changes over their total, between -100 and 100
+*	Ultimate Oscillator: a weighted average of the buying pressure of bars over three lookbacks, between 0 and 100
+*
+* As with the other series functions, values before an oscillator is ready are 0.0 and invalid parameters return nil.
+ */
+
+// MomentumSeries64 computes the momentum of each value of a series over n periods, see MomentumStream64
+// Values with fewer than n prior values are 0.0. Returns nil for an empty series or n <= 0.
+func MomentumSeries64(series []float64, n int) []float64 {
+	s, err := NewMomentumStream64(n)
+	if err != nil || len(series) == 0 {
+		return nil
+	}
+
+	return UpdateSeries(s, series)
+}
+
+// MomentumSeries32 is 32 bit version of MomentumSeries64
+func MomentumSeries32(series []float32, n int) []float32 {
+	return to32(MomentumSeries64(to64(series), n))
+}
+
+// ROCSeries64 computes the Rate of Change of each value of a series over n periods, see ROCStream64
+// Values with fewer than n prior values are 0.0. Returns nil for an empty series or n <= 0.
+func ROCSeries64(series []float64, n int) []float64 {
+	s, err := NewROCStream64(n)
+	if err != nil || len(series) == 0 {
+		return nil
+	}
+
+	return UpdateSeries(s, series)
+}
+
+// ROCSeries32 is 32 bit version of ROCSeries64
+func ROCSeries32(series []float32, n int) []float32 {
+	return to32(ROCSeries64(to64(series), n))
+}
+
+// MomentumStream64 computes the momentum over a stream of values, the change over the last n periods
+type MomentumStream64 struct {
+	w window64 // last n + 1 values
+}
+
+// NewMomentumStream64 creates a MomentumStream64 over n periods
+// Returns ErrInvalidPeriods if n <= 0
+func NewMomentumStream64(n int) (*MomentumStream64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	return &MomentumStream64{w: newWindow64(n + 1)}, nil
+}
+
+// Update adds the next value of the stream
+func (s *MomentumStream64) Update(v float64) {
+	s.w.push(v)
+}
+
+// Value returns the current momentum, 0.0 until the stream is ready
+func (s *MomentumStream64) Value() float64 {
+	if !s.Ready() {
+		return 0.0
+	}
+
+	return s.w.last() - s.w.at(0)
+}
+
+// Ready reports whether n + 1 values have been seen
+func (s *MomentumStream64) Ready() bool {
+	return s.w.full()
+}
+
+// WarmupPeriod returns the number of values before the stream is ready
+func (s *MomentumStream64) WarmupPeriod() int {
+	return len(s.w.buf)
+}
+
+// Reset clears the stream
+func (s *MomentumStream64) Reset() {
+	s.w.reset()
+}
+
+// Clone returns an independent copy of the stream
+func (s *MomentumStream64) Clone() *MomentumStream64 {
+	return &MomentumStream64{w: s.w.clone()}
+}
+
+// ROCStream64 computes the Rate of Change over a stream of values, the percentage change over the last n periods
+// The ROC from a value of 0.0 is 0.0.
+type ROCStream64 struct {
+	m MomentumStream64
+}
+
+// NewROCStream64 creates a ROCStream64 over n periods
+// Returns ErrInvalidPeriods if n <= 0
+func NewROCStream64(n int) (*ROCStream64, error) {
+	m, err := NewMomentumStream64(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ROCStream64{m: *m}, nil
+}
+
+// Update adds the next value of the stream
+func (s *ROCStream64) Update(v float64) {
+	s.m.Update(v)
+}
+
+// Value returns the current ROC, 0.0 until the stream is ready
+func (s *ROCStream64) Value() float64 {
+	base := s.m.w.at(0)
+	if !s.Ready() || base == 0.0 {
+		return 0.0
+	}
+
+	return 100.0 * s.m.Value() / base
+}
+
+// Ready reports whether n + 1 values have been seen
+func (s *ROCStream64) Ready() bool {
+	return s.m.Ready()
+}
+
+// WarmupPeriod returns the number of values before the stream is ready
+func (s *ROCStream64) WarmupPeriod() int {
+	return s.m.WarmupPeriod()
+}
+
+// Reset clears the stream
+func (s *ROCStream64) Reset() {
+	s.m.Reset()
+}
+
+// Clone returns an independent copy of the stream
+func (s *ROCStream64) Clone() *ROCStream64 {
+	return &ROCStream64{m: *s.m.Clone()}
+}
+
+// TRIXSeries64 computes the TRIX of each value of a series with EWMAs of lookback lb, see TRIXStream64
+// Values before 3 * lb - 1 values are 0.0. Returns nil for an empty series or lb <= 0.
+func TRIXSeries64(series []float64, lb int) []float64 {
+	s, err := NewTRIXStream64(lb)
+	if err != nil || len(series) == 0 {
+		return nil
+	}
+
+	return UpdateSeries(s, series)
+}
+
+// TRIXSeries32 is 32 bit version of TRIXSeries64
+func TRIXSeries32(series []float32, lb int) []float32 {
+	return to32(TRIXSeries64(to64(series), lb))
+}
+
+// TRIXStream64 computes the TRIX over a stream of values
+// The values are smoothed three times by EWMAs of lookback lb and the default smoothing 2 / (lb + 1),
+// each EWMA starting from the simple average of the first lb values of the one before as EMAStream64.
+// The TRIX is the percentage change of the triple EWMA from the previous value.
+type TRIXStream64 struct {
+	ema1 EMAStream64
+	ema2 EMAStream64
+	ema3 EMAStream64
+	prev float64
+	trix float64
+	n    int // number of triple EWMAs
+}
+
+// NewTRIXStream64 creates a TRIXStream64 with EWMAs of lookback lb, typically 15
+// Returns ErrInvalidLookback if lb <= 0
+func NewTRIXStream64(lb int) (*TRIXStream64, error) {
+	ema, err := NewEMAStream64(lb, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TRIXStream64{ema1: *ema, ema2: *ema, ema3: *ema}, nil
+}
+
+// Update adds the next value of the stream
+func (s *TRIXStream64) Update(v float64) {
+	s.ema1.Update(v)
+	if !s.ema1.Ready() {
+		return
+	}
+
+	s.ema2.Update(s.ema1.Value())
+	if !s.ema2.Ready() {
+		return
+	}
+
+	s.ema3.Update(s.ema2.Value())
+	if !s.ema3.Ready() {
+		return
+	}
+
+	ema := s.ema3.Value()
+	if s.n > 0 && s.prev != 0.0 {
+		s.trix = 100.0 * (ema - s.prev) / s.prev
+	}
+
+	s.prev = ema
+	s.n++
+}
+
+// Value returns the current TRIX, 0.0 until the stream is ready
+func (s *TRIXStream64) Value() float64 {
+	return s.trix
+}
+
+// Ready reports whether two triple EWMAs have been computed
+func (s *TRIXStream64) Ready() bool {
+	return s.n >= 2
+}
+
+// WarmupPeriod returns the number of values before the stream is ready
+func (s *TRIXStream64) WarmupPeriod() int {
+	return 3*s.ema1.lb - 1
+}
+
+// Reset clears the stream
+func (s *TRIXStream64) Reset() {
+	s.ema1.Reset()
+	s.ema2.Reset()
+	s.ema3.Reset()
+	s.prev, s.trix, s.n = 0.0, 0.0, 0
+}
+
+// Clone returns an independent copy of the stream
+func (s *TRIXStream64) Clone() *TRIXStream64 {
+	c := *s
+	return &c
+}
+
+// PPOSeries64 computes the Percentage Price Oscillator of each value of a series, see PPOStream64
+// Values before slow values are 0.0. Returns nil for an empty series or invalid lookbacks.
+func PPOSeries64(series []float64, fast int, slow int) []float64 {
+	s, err := NewPPOStream64(fast, slow)
+	if err != nil || len(series) == 0 {
+		return nil
+	}
+
+	return UpdateSeries(s, series)
+}
+
+// PPOSeries32 is 32 bit version of PPOSeries64
+func PPOSeries32(series []float32, fast int, slow int) []float32 {
+	return to32(PPOSeries64(to64(series), fast, slow))
+}
+
+// PPOStream64 computes the Percentage Price Oscillator over a stream of values
+// The PPO is 100 * (fast EWMA - slow EWMA) / slow EWMA, with the default smoothing of each lookback,
+// i.e. the MACD as a percentage of the slow EWMA. The PPO of a slow EWMA of 0.0 is 0.0.
+type PPOStream64 struct {
+	fast EMAStream64
+	slow EMAStream64
+}
+
+// NewPPOStream64 creates a PPOStream64
+// Returns ErrInvalidLookback if fast <= 0, slow <= 0 or fast >= slow
+//
+// Parameters:
+//
+//	fast: lookback of the fast EWMA, typically 12
+//	slow: lookback of the slow EWMA, typically 26
+func NewPPOStream64(fast int, slow int) (*PPOStream64, error) {
+	if fast >= slow {
+		return nil, ErrInvalidLookback
+	}
+
+	f, err := NewEMAStream64(fast, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sl, err := NewEMAStream64(slow, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PPOStream64{fast: *f, slow: *sl}, nil
+}
+
+// Update adds the next value of the stream
+func (s *PPOStream64) Update(v float64) {
+	s.fast.Update(v)
+	s.slow.Update(v)
+}
+
+// Value returns the current PPO, 0.0 until the stream is ready
+func (s *PPOStream64) Value() float64 {
+	slow := s.slow.Value()
+	if !s.Ready() || slow == 0.0 {
+		return 0.0
+	}
+
+	return 100.0 * (s.fast.Value() - slow) / slow
+}
+
+// Ready reports whether the slow EWMA is ready
+func (s *PPOStream64) Ready() bool {
+	return s.slow.Ready()
+}
+
+// WarmupPeriod returns the number of values before the stream is ready
+func (s *PPOStream64) WarmupPeriod() int {
+	return s.slow.WarmupPeriod()
+}
+
+// Reset clears the stream
+func (s *PPOStream64) Reset() {
+	s.fast.Reset()
+	s.slow.Reset()
+}
+
+// Clone returns an independent copy of the stream
+func (s *PPOStream64) Clone() *PPOStream64 {
+	c := *s
+	return &c
+}
+
+// CMOSeries64 computes the Chande Momentum Oscillator of each value of a series over n periods (changes), see CMOStream64
+// Values with fewer than n prior changes are 0.0. Returns nil for an empty series or n <= 0.
+func CMOSeries64(series []float64, n int) []float64 {
+	s, err := NewCMOStream64(n)
+	if err != nil || len(series) == 0 {
+		return nil
+	}
+
+	return UpdateSeries(s, series)
+}
+
+// CMOSeries32 is 32 bit version of CMOSeries64
+func CMOSeries32(series []float32, n int) []float32 {
+	return to32(CMOSeries64(to64(series), n))
+}
+
+// CMOStream64 computes the Chande Momentum Oscillator over a stream of values
+// The CMO is 100 * (gains - losses) / (gains + losses) of the last n changes, 0.0 without gains or losses.
+// Unlike the RSI the gains and losses are summed rather than smoothed.
+type CMOStream64 struct {
+	gains  rollingSum64
+	losses rollingSum64
+	last   float64
+	count  int // number of values seen
+}
+
+// NewCMOStream64 creates a CMOStream64 over n periods (changes), typically 9 or 14
+// Returns ErrInvalidPeriods if n <= 0
+func NewCMOStream64(n int) (*CMOStream64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	return &CMOStream64{gains: newRollingSum64(n), losses: newRollingSum64(n)}, nil
+}
+
+// Update adds the next value of the stream
+func (s *CMOStream64) Update(v float64) {
+	if s.count > 0 {
+		var g, l float64
+		if d := v - s.last; d > 0.0 {
+			g = d
+		} else {
+			l = -d
+		}
+
+		s.gains.push(g)
+		s.losses.push(l)
+	}
+
+	s.last = v
+	s.count++
+}
+
+// Value returns the current CMO, 0.0 until the stream is ready
+func (s *CMOStream64) Value() float64 {
+	total := s.gains.sum + s.losses.sum
+	if !s.Ready() || total <= 0.0 {
+		return 0.0
+	}
+
+	return 100.0 * (s.gains.sum - s.losses.sum) / total
+}
+
+// Ready reports whether n changes have been seen
+func (s *CMOStream64) Ready() bool {
+	return s.gains.full()
+}
+
+// WarmupPeriod returns the number of values before the stream is ready
+func (s *CMOStream64) WarmupPeriod() int {
+	return len(s.gains.w.buf) + 1
+}
+
+// Reset clears the stream
+func (s *CMOStream64) Reset() {
+	s.gains.reset()
+	s.losses.reset()
+	s.last, s.count = 0.0, 0
+}
+
+// Clone returns an independent copy of the stream
+func (s *CMOStream64) Clone() *CMOStream64 {
+	c := *s
+	c.gains = s.gains.clone()
+	c.losses = s.losses.clone()
+
+	return &c
+}
+
+// UltimateOscillator64 computes the Ultimate Oscillator of each bar, see UltimateOscillatorStream64
+// Values before long + 1 bars are 0.0. Returns nil for invalid lookbacks.
+func UltimateOscillator64(bars []Bar, short int, medium int, long int) []float64 {
+	s, err := NewUltimateOscillatorStream64(short, medium, long)
+	if err != nil {
+		return nil
+	}
+
+	return updateBars(bars, s.UpdateBar, s.Ready, s.Value)
+}
+
+// UltimateOscillatorStream64 computes the Ultimate Oscillator of Larry Williams over a stream of bars
+// The buying pressure of a bar is its close less the lower of its low and the previous close, and its true range
+// the higher of its high and the previous close less the lower. Over each lookback the buying pressure is averaged
+// as the sum of the buying pressures over the sum of the true ranges, and the oscillator is
+// 100 * (4 * short average + 2 * medium average + long average) / 7.
+type UltimateOscillatorStream64 struct {
+	pressures [3]rollingSum64
+	ranges    [3]rollingSum64
+	last      float64
+	count     int // number of bars seen
+}
+
+// NewUltimateOscillatorStream64 creates an UltimateOscillatorStream64
+// Returns ErrInvalidLookback unless 0 < short < medium < long
+//
+// Parameters:
+//
+//	short: short lookback, typically 7
+//	medium: medium lookback, typically 14
+//	long: long lookback, typically 28
+func NewUltimateOscillatorStream64(short int, medium int, long int) (*UltimateOscillatorStream64, error) {
+	if short <= 0 || short >= medium || medium >= long {
+		return nil, ErrInvalidLookback
+	}
+
+	var s UltimateOscillatorStream64
+	for i, lb := range []int{short, medium, long} {
+		s.pressures[i], s.ranges[i] = newRollingSum64(lb), newRollingSum64(lb)
+	}
+
+	return &s, nil
+}
+
+// Update adds the next value of the stream as a bar of one value
+func (s *UltimateOscillatorStream64) Update(v float64) {
+	s.UpdateBar(valueBar(v))
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *UltimateOscillatorStream64) UpdateBar(b Bar) {
+	if s.count > 0 {
+		low, high := math.Min(b.Low, s.last), math.Max(b.High, s.last)
+		for i := range s.pressures {
+			s.pressures[i].push(b.Close - low)
+			s.ranges[i].push(high - low)
+		}
+	}
+
+	s.last = b.Close
+	s.count++
+}
+
+// Value returns the current oscillator, 0.0 until the stream is ready
+// A lookback without range has a buying pressure of 0.5.
+func (s *UltimateOscillatorStream64) Value() float64 {
+	if !s.Ready() {
+		return 0.0
+	}
+
+	var avg [3]float64
+	for i := range avg {
+		avg[i] = 0.5
+		if r := s.ranges[i].sum; r > 0.0 {
+			avg[i] = s.pressures[i].sum / r
+		}
+	}
+
+	return 100.0 * (4.0*avg[0] + 2.0*avg[1] + avg[2]) / 7.0
+}
+
+// Ready reports whether the long lookback is full, after long + 1 bars
+func (s *UltimateOscillatorStream64) Ready() bool {
+	return s.ranges[2].full()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *UltimateOscillatorStream64) WarmupPeriod() int {
+	return len(s.ranges[2].w.buf) + 1
+}
+
+// Reset clears the stream
+func (s *UltimateOscillatorStream64) Reset() {
+	for i := range s.pressures {
+		s.pressures[i].reset()
+		s.ranges[i].reset()
+	}
+
+	s.last, s.count = 0.0, 0
+}
+
+// Clone returns an independent copy of the stream
+func (s *UltimateOscillatorStream64) Clone() *UltimateOscillatorStream64 {
+	c := *s
+	for i := range s.pressures {
+		c.pressures[i] = s.pressures[i].clone()
+		c.ranges[i] = s.ranges[i].clone()
+	}
+
+	return &c
+}
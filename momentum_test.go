@@ -0,0 +1,191 @@
+package technical
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// momentumSeries has changes +1, +1, -1, +2, +1, -2
+var momentumSeries = []float64{10, 11, 12, 11, 13, 14, 12}
+
+func TestMomentum(t *testing.T) {
+	assert.Equal(t, []float64{0, 0, 2, 0, 1, 3, -1}, MomentumSeries64(momentumSeries, 2))
+	assert.Equal(t, []float32{0, 0, 2, 0, 1, 3, -1}, MomentumSeries32(to32(momentumSeries), 2))
+	assert.Nil(t, MomentumSeries64(momentumSeries, 0))
+	assert.Nil(t, MomentumSeries64(nil, 2))
+
+	s, _ := NewMomentumStream64(2)
+	assert.Equal(t, 3, s.WarmupPeriod())
+	for _, v := range momentumSeries {
+		s.Update(v)
+	}
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, -1.0, c.Value())
+}
+
+func TestROC(t *testing.T) {
+	roc := ROCSeries64(momentumSeries, 2)
+	assert.Equal(t, []float64{0, 0}, roc[:2])
+	assert.InDeltaSlice(t, []float64{20, 0, 100.0 / 12.0, 300.0 / 11.0, -100.0 / 13.0}, roc[2:], 1e-12)
+	assert.InDeltaSlice(t, to32(roc), ROCSeries32(to32(momentumSeries), 2), 1e-5)
+	assert.Nil(t, ROCSeries64(momentumSeries, -1))
+
+	// from 0
+	assert.Equal(t, []float64{0, 0}, ROCSeries64([]float64{0, 1}, 1))
+
+	s, _ := NewROCStream64(2)
+	for _, v := range momentumSeries {
+		s.Update(v)
+	}
+
+	assert.Equal(t, 3, s.WarmupPeriod())
+	c := s.Clone()
+	s.Reset()
+	assert.Equal(t, 0.0, s.Value())
+	assert.Equal(t, roc[len(roc)-1], c.Value())
+}
+
+func TestTRIX(t *testing.T) {
+	assert.Nil(t, TRIXSeries64(momentumSeries, 0))
+
+	trix := TRIXSeries64(momentumSeries, 2)
+	assert.Equal(t, []float64{0, 0, 0, 0}, trix[:4])
+
+	// the triple EWMA by hand is 11.0556, 11.6605, 12.5288, 12.6070
+	assert.InDeltaSlice(t, []float64{5.471803, 7.446621, 0.624076}, trix[4:], 1e-6)
+
+	// the triple EWMA of the series functions
+	ema1 := EwmaSeries64(momentumSeries, 0, 2)
+	ema2 := EwmaSeries64(ema1[1:], 0, 2)
+	ema3 := EwmaSeries64(ema2[1:], 0, 2)[1:]
+	for i := 1; i < len(ema3); i++ {
+		assert.InDelta(t, 100*(ema3[i]-ema3[i-1])/ema3[i-1], trix[i+3], 1e-12)
+	}
+
+	assert.InDeltaSlice(t, to32(trix), TRIXSeries32(to32(momentumSeries), 2), 1e-5)
+
+	s, _ := NewTRIXStream64(15)
+	assert.Equal(t, 44, s.WarmupPeriod())
+	for i, v := range readMockSeries64("mock/test_series.txt")[:100] {
+		s.Update(v)
+		assert.Equal(t, i >= 43, s.Ready())
+	}
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.True(t, c.Ready())
+}
+
+func TestPPO(t *testing.T) {
+	_, err := NewPPOStream64(3, 3)
+	assert.Equal(t, ErrInvalidLookback, err)
+	assert.Nil(t, PPOSeries64(momentumSeries, 0, 3))
+
+	// fast EWMAs 11.5 and 11 + 1/6, slow EWMAs 11 and 11
+	ppo := PPOSeries64(momentumSeries, 2, 3)
+	assert.Equal(t, []float64{0, 0}, ppo[:2])
+	assert.InDelta(t, 100*0.5/11, ppo[2], 1e-12)
+	assert.InDelta(t, 100*(1.0/6.0)/11, ppo[3], 1e-12)
+
+	// the MACD as a percentage of the slow EWMA
+	series := readMockSeries64("mock/test_series.txt")
+	fast, slow := EwmaSeries64(series, 0, 12), EwmaSeries64(series, 0, 26)
+	ppo = PPOSeries64(series, 12, 26)
+	for i := 25; i < len(series); i += 100 {
+		assert.InDelta(t, 100*(fast[i]-slow[i])/slow[i], ppo[i], 1e-9)
+	}
+
+	assert.Len(t, PPOSeries32(to32(series), 12, 26), len(series))
+
+	s, _ := NewPPOStream64(12, 26)
+	assert.Equal(t, 26, s.WarmupPeriod())
+	for _, v := range series {
+		s.Update(v)
+	}
+
+	c := s.Clone()
+	s.Reset()
+	assert.Equal(t, 0.0, s.Value())
+	assert.Equal(t, ppo[len(ppo)-1], c.Value())
+}
+
+func TestCMO(t *testing.T) {
+	assert.Nil(t, CMOSeries64(momentumSeries, 0))
+
+	// gains and losses of the last 3 changes: 2 and 1, 3 and 1, 3 and 1, 3 and 2
+	cmo := CMOSeries64(momentumSeries, 3)
+	assert.Equal(t, []float64{0, 0, 0}, cmo[:3])
+	assert.InDeltaSlice(t, []float64{100.0 / 3.0, 50, 50, 20}, cmo[3:], 1e-12)
+	assert.InDeltaSlice(t, to32(cmo), CMOSeries32(to32(momentumSeries), 3), 1e-5)
+
+	// without changes
+	assert.Equal(t, []float64{0, 0}, CMOSeries64([]float64{1, 1}, 1))
+
+	s, _ := NewCMOStream64(14)
+	assert.Equal(t, 15, s.WarmupPeriod())
+	for _, v := range readMockSeries64("mock/test_series.txt") {
+		s.Update(v)
+		assert.True(t, s.Value() >= -100.0 && s.Value() <= 100.0)
+	}
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.True(t, c.Ready())
+}
+
+func TestUltimateOscillator(t *testing.T) {
+	_, err := NewUltimateOscillatorStream64(7, 7, 28)
+	assert.Equal(t, ErrInvalidLookback, err)
+	assert.Nil(t, UltimateOscillator64(stopBars(), 0, 14, 28))
+
+	// buying pressures 1.5 of true ranges 2 in the rally, then 0.5 of 3, 3 and 2
+	uo := UltimateOscillator64(stopBars(), 1, 2, 3)
+	assert.Equal(t, []float64{0, 0, 0}, uo[:3])
+	assert.InDelta(t, 75.0, uo[3], 1e-12)
+	assert.InDelta(t, 75.0, uo[4], 1e-12)
+	assert.InDelta(t, 100*(4*0.5/3+2*2.0/5+3.5/7)/7, uo[5], 1e-12)
+	assert.InDelta(t, 100*(4*0.5/3+2*1.0/6+2.5/8)/7, uo[6], 1e-12)
+
+	s, _ := NewUltimateOscillatorStream64(7, 14, 28)
+	assert.Equal(t, 29, s.WarmupPeriod())
+	for _, b := range mockBars() {
+		s.UpdateBar(b)
+		assert.True(t, s.Value() >= 0.0 && s.Value() <= 100.0)
+	}
+
+	// a single value has no range
+	v, _ := NewUltimateOscillatorStream64(1, 2, 3)
+	for i := 0; i < 4; i++ {
+		v.Update(10)
+	}
+
+	assert.Equal(t, 50.0, v.Value())
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.True(t, c.Ready())
+}
+
+func TestMomentumExpr(t *testing.T) {
+	series := readMockSeries64("mock/test_series.txt")
+
+	for src, expected := range map[string][]float64{
+		"roc(close, 10)":        ROCSeries64(series, 10),
+		"momentum(close, 10)":   MomentumSeries64(series, 10),
+		"trix(close, 15)":       TRIXSeries64(series, 15),
+		"ppo(close, 12, 26)":    PPOSeries64(series, 12, 26),
+		"cmo(close, 14)":        CMOSeries64(series, 14),
+		"cmo(roc(close, 5), 9)": CMOSeries64(ROCSeries64(series, 5)[5:], 9),
+	} {
+		values, err := EvalExprSeries(src, series)
+		assert.NoError(t, err, src)
+		assert.InDelta(t, expected[len(expected)-1], values[len(values)-1], 1e-9, src)
+	}
+}
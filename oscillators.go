@@ -0,0 +1,333 @@
+package technical
+
+/*
+* Range oscillators place the price within its recent range:
+*
+*	Commodity Channel Index (CCI): the distance of the typical price from its n period average,
+*	in units of 0.015 times the mean absolute deviation, so most values fall between -100 and 100
+*	Williams %R: the close within the n period high low range, from -100 at the lowest low to 0 at the highest high
+*	Aroon: the periods since the n period highest high (Up) and lowest low (Down), from 100 for the current bar
+*	to 0 for n periods ago, and the Aroon Oscillator Up - Down
+*
+* The oscillators take OHLC bars, or a flat series split into periods of s values as StaticATR64 with PeriodBars.
+* Williams %R and Aroon track the range with monotonic deques in amortized O(1) per bar. The mean deviation of the CCI
+* is not a rolling sum, so the CCI keeps its n typical prices in a search tree ordered by price in O(log n) per bar.
+ */
+
+// cciConstant scales the CCI so that most values fall between -100 and 100
+const cciConstant = 0.015
+
+// CCI64 computes the Commodity Channel Index of each bar, see CCIStream64
+// Values before n bars are 0.0. Returns nil if n <= 0.
+func CCI64(bars []Bar, n int) []float64 {
+	s, err := NewCCIStream64(n)
+	if err != nil {
+		return nil
+	}
+
+	return updateBars(bars, s.UpdateBar, s.Ready, s.Value)
+}
+
+// CCIPeriods64 computes the Commodity Channel Index of each period of s values of a flat series
+// The periods are the bars of PeriodBars. Returns nil if n <= 0 or s <= 0.
+func CCIPeriods64(series []float64, n int, s int) []float64 {
+	if s <= 0 {
+		return nil
+	}
+
+	return CCI64(PeriodBars(series, s), n)
+}
+
+// CCIPeriods32 is 32 bit version of CCIPeriods64
+func CCIPeriods32(series []float32, n int, s int) []float32 {
+	return to32(CCIPeriods64(to64(series), n, s))
+}
+
+// CCIStream64 computes the Commodity Channel Index of Donald Lambert over a stream of bars
+// CCI = (tp - mean) / (0.015 * md) where tp is the typical price, mean its n bar average and md the mean
+// absolute deviation of the n typical prices from the average. An update is O(log n) expected for the mean deviation.
+type CCIStream64 struct {
+	prices sortedWindow64
+	value  float64
+}
+
+// NewCCIStream64 creates a CCIStream64 of n bars
+// Returns ErrInvalidPeriods if n <= 0
+func NewCCIStream64(n int) (*CCIStream64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	return &CCIStream64{prices: newSortedWindow64(n)}, nil
+}
+
+// Update adds the next value of the stream as a bar of one value
+func (s *CCIStream64) Update(v float64) {
+	s.UpdateBar(valueBar(v))
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *CCIStream64) UpdateBar(b Bar) {
+	tp := b.TypicalPrice()
+	s.prices.push(tp)
+	if !s.prices.full() {
+		return
+	}
+
+	mean := s.prices.mean()
+	md := s.prices.absDev(mean) / float64(s.prices.size())
+
+	s.value = 0.0
+	if md > 0.0 {
+		s.value = (tp - mean) / (cciConstant * md)
+	}
+}
+
+// Value returns the current CCI, 0.0 until the stream is ready or if the typical prices are constant
+func (s *CCIStream64) Value() float64 {
+	return s.value
+}
+
+// Ready reports whether n bars have been seen
+func (s *CCIStream64) Ready() bool {
+	return s.prices.full()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *CCIStream64) WarmupPeriod() int {
+	return s.prices.size()
+}
+
+// Reset clears the stream
+func (s *CCIStream64) Reset() {
+	s.prices.reset()
+	s.value = 0.0
+}
+
+// Clone returns an independent copy of the stream
+func (s *CCIStream64) Clone() *CCIStream64 {
+	c := *s
+	c.prices = s.prices.clone()
+
+	return &c
+}
+
+// WilliamsR64 computes the Williams %R of each bar, see WilliamsRStream64
+// Values before n bars are 0.0. Returns nil if n <= 0.
+func WilliamsR64(bars []Bar, n int) []float64 {
+	s, err := NewWilliamsRStream64(n)
+	if err != nil {
+		return nil
+	}
+
+	return updateBars(bars, s.UpdateBar, s.Ready, s.Value)
+}
+
+// WilliamsRPeriods64 computes the Williams %R of each period of s values of a flat series
+// The periods are the bars of PeriodBars. Returns nil if n <= 0 or s <= 0.
+func WilliamsRPeriods64(series []float64, n int, s int) []float64 {
+	if s <= 0 {
+		return nil
+	}
+
+	return WilliamsR64(PeriodBars(series, s), n)
+}
+
+// WilliamsRPeriods32 is 32 bit version of WilliamsRPeriods64
+func WilliamsRPeriods32(series []float32, n int, s int) []float32 {
+	return to32(WilliamsRPeriods64(to64(series), n, s))
+}
+
+// WilliamsRStream64 computes the Williams %R of Larry Williams over a stream of bars
+// %R = -100 * (hh - close) / (hh - ll) where hh and ll are the highest high and lowest low of the last n bars.
+type WilliamsRStream64 struct {
+	highs extremeWindow64
+	lows  extremeWindow64
+	close float64
+}
+
+// NewWilliamsRStream64 creates a WilliamsRStream64 of n bars
+// Returns ErrInvalidPeriods if n <= 0
+func NewWilliamsRStream64(n int) (*WilliamsRStream64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	return &WilliamsRStream64{highs: newMaxWindow64(n), lows: newMinWindow64(n)}, nil
+}
+
+// Update adds the next value of the stream as a bar of one value
+func (s *WilliamsRStream64) Update(v float64) {
+	s.UpdateBar(valueBar(v))
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *WilliamsRStream64) UpdateBar(b Bar) {
+	s.highs.push(b.High)
+	s.lows.push(b.Low)
+	s.close = b.Close
+}
+
+// Value returns the current %R, 0.0 until the stream is ready
+// A window without range is in the middle of its range, -50.
+func (s *WilliamsRStream64) Value() float64 {
+	if !s.Ready() {
+		return 0.0
+	}
+
+	hh, ll := s.highs.value(), s.lows.value()
+	if hh <= ll {
+		return -50.0
+	}
+
+	return -100.0 * (hh - s.close) / (hh - ll)
+}
+
+// Ready reports whether n bars have been seen
+func (s *WilliamsRStream64) Ready() bool {
+	return s.highs.full()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *WilliamsRStream64) WarmupPeriod() int {
+	return s.highs.size()
+}
+
+// Reset clears the stream
+func (s *WilliamsRStream64) Reset() {
+	s.highs.reset()
+	s.lows.reset()
+	s.close = 0.0
+}
+
+// Clone returns an independent copy of the stream
+func (s *WilliamsRStream64) Clone() *WilliamsRStream64 {
+	c := *s
+	c.highs = s.highs.clone()
+	c.lows = s.lows.clone()
+
+	return &c
+}
+
+// AroonLevel is the Aroon Up and Down of a bar, both between 0 and 100
+type AroonLevel struct {
+	Up   float64 `json:"up"`
+	Down float64 `json:"down"`
+}
+
+// Oscillator returns the Aroon Oscillator, Up - Down, between -100 and 100
+func (l AroonLevel) Oscillator() float64 {
+	return l.Up - l.Down
+}
+
+// Aroon64 computes the Aroon Up and Down of each bar, see AroonStream64
+// Levels before n + 1 bars are zero. Returns nil if n <= 0.
+func Aroon64(bars []Bar, n int) []AroonLevel {
+	s, err := NewAroonStream64(n)
+	if err != nil {
+		return nil
+	}
+
+	levels := make([]AroonLevel, len(bars))
+	for i, b := range bars {
+		s.UpdateBar(b)
+		if s.Ready() {
+			levels[i] = s.Level()
+		}
+	}
+
+	return levels
+}
+
+// AroonPeriods64 computes the Aroon Up and Down of each period of s values of a flat series
+// The periods are the bars of PeriodBars. Returns nil if n <= 0 or s <= 0.
+func AroonPeriods64(series []float64, n int, s int) []AroonLevel {
+	if s <= 0 {
+		return nil
+	}
+
+	return Aroon64(PeriodBars(series, s), n)
+}
+
+// AroonStream64 computes the Aroon indicator of Tushar Chande over a stream of bars
+// Up = 100 * (n - bars since the highest high) / n and Down = 100 * (n - bars since the lowest low) / n
+// over the last n + 1 bars, the current bar and n before it. Of equal highs or lows the most recent counts.
+type AroonStream64 struct {
+	highs extremeWindow64
+	lows  extremeWindow64
+}
+
+// NewAroonStream64 creates an AroonStream64 of n bars
+// Returns ErrInvalidPeriods if n <= 0
+func NewAroonStream64(n int) (*AroonStream64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	return &AroonStream64{highs: newMaxWindow64(n + 1), lows: newMinWindow64(n + 1)}, nil
+}
+
+// Update adds the next value of the stream as a bar of one value
+func (s *AroonStream64) Update(v float64) {
+	s.UpdateBar(valueBar(v))
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *AroonStream64) UpdateBar(b Bar) {
+	s.highs.push(b.High)
+	s.lows.push(b.Low)
+}
+
+// Level returns the current Aroon Up and Down, zero until the stream is ready
+func (s *AroonStream64) Level() AroonLevel {
+	if !s.Ready() {
+		return AroonLevel{}
+	}
+
+	n := float64(s.highs.size() - 1)
+
+	return AroonLevel{
+		Up:   100.0 * (n - float64(s.highs.age())) / n,
+		Down: 100.0 * (n - float64(s.lows.age())) / n,
+	}
+}
+
+// Up returns the current Aroon Up
+func (s *AroonStream64) Up() float64 {
+	return s.Level().Up
+}
+
+// Down returns the current Aroon Down
+func (s *AroonStream64) Down() float64 {
+	return s.Level().Down
+}
+
+// Value returns the current Aroon Oscillator, 0.0 until the stream is ready
+func (s *AroonStream64) Value() float64 {
+	return s.Level().Oscillator()
+}
+
+// Ready reports whether n + 1 bars have been seen
+func (s *AroonStream64) Ready() bool {
+	return s.highs.full()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *AroonStream64) WarmupPeriod() int {
+	return s.highs.size()
+}
+
+// Reset clears the stream
+func (s *AroonStream64) Reset() {
+	s.highs.reset()
+	s.lows.reset()
+}
+
+// Clone returns an independent copy of the stream
+func (s *AroonStream64) Clone() *AroonStream64 {
+	c := *s
+	c.highs = s.highs.clone()
+	c.lows = s.lows.clone()
+
+	return &c
+}
@@ -0,0 +1,160 @@
+package technical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCCI(t *testing.T) {
+	_, err := NewCCIStream64(0)
+	assert.Equal(t, ErrInvalidPeriods, err)
+	assert.Nil(t, CCI64(stopBars(), 0))
+
+	// typical prices 30/3, 32/3, 35/3, 38/3, 41/3, 38/3, 32/3 and 28/3
+	cci := CCI64(stopBars(), 3)
+	assert.Equal(t, []float64{0, 0}, cci[:2])
+	assert.InDeltaSlice(t, []float64{100, 100, 100, -50}, cci[2:6], 1e-9)
+
+	// the mean deviation of the window by a scan
+	bars := mockBars()
+	cci = CCI64(bars, 20)
+	for i := 19; i < len(bars); i += 37 {
+		var mean, md float64
+		for _, b := range bars[i-19 : i+1] {
+			mean += b.TypicalPrice() / 20
+		}
+
+		for _, b := range bars[i-19 : i+1] {
+			md += math.Abs(b.TypicalPrice()-mean) / 20
+		}
+
+		assert.InDelta(t, (bars[i].TypicalPrice()-mean)/(0.015*md), cci[i], 1e-6)
+	}
+
+	// constant typical prices
+	s, _ := NewCCIStream64(2)
+	s.Update(10)
+	s.Update(10)
+	assert.True(t, s.Ready())
+	assert.Equal(t, 0.0, s.Value())
+	assert.Equal(t, 2, s.WarmupPeriod())
+
+	c := s.Clone()
+	s.Reset()
+	c.Update(11)
+	assert.False(t, s.Ready())
+	assert.InDelta(t, 1/0.015, c.Value(), 1e-9)
+}
+
+func TestWilliamsR(t *testing.T) {
+	_, err := NewWilliamsRStream64(-1)
+	assert.Equal(t, ErrInvalidPeriods, err)
+	assert.Nil(t, WilliamsR64(stopBars(), 0))
+
+	wr := WilliamsR64(stopBars(), 3)
+	assert.Equal(t, []float64{0, 0}, wr[:2])
+	assert.InDeltaSlice(t, []float64{-50.0 / 3.0, -12.5, -12.5, -250.0 / 3.0, -90, -275.0 / 3.0}, wr[2:], 1e-12)
+
+	// the range of the window by a scan
+	bars := mockBars()
+	wr = WilliamsR64(bars, 14)
+	for i := 13; i < len(bars); i++ {
+		hh, ll := math.Inf(-1), math.Inf(1)
+		for _, b := range bars[i-13 : i+1] {
+			hh, ll = math.Max(hh, b.High), math.Min(ll, b.Low)
+		}
+
+		expected := -50.0
+		if hh > ll {
+			expected = -100 * (hh - bars[i].Close) / (hh - ll)
+		}
+
+		assert.InDelta(t, expected, wr[i], 1e-12)
+	}
+
+	s, _ := NewWilliamsRStream64(2)
+	s.Update(10)
+	s.Update(10)
+	assert.Equal(t, -50.0, s.Value())
+	assert.Equal(t, 2, s.WarmupPeriod())
+
+	c := s.Clone()
+	s.Reset()
+	c.Update(12)
+	assert.False(t, s.Ready())
+	assert.Equal(t, 0.0, c.Value())
+}
+
+func TestAroon(t *testing.T) {
+	_, err := NewAroonStream64(0)
+	assert.Equal(t, ErrInvalidPeriods, err)
+	assert.Nil(t, Aroon64(stopBars(), 0))
+
+	// the equal highs of bars 4 and 5 count from bar 5
+	levels := Aroon64(stopBars(), 3)
+	assert.Equal(t, make([]AroonLevel, 3), levels[:3])
+	for i, expected := range []AroonLevel{
+		{Up: 100, Down: 100.0 / 3.0},
+		{Up: 100, Down: 0},
+		{Up: 100, Down: 0},
+		{Up: 200.0 / 3.0, Down: 100},
+		{Up: 100.0 / 3.0, Down: 100},
+	} {
+		assert.InDelta(t, expected.Up, levels[i+3].Up, 1e-12)
+		assert.InDelta(t, expected.Down, levels[i+3].Down, 1e-12)
+	}
+
+	assert.InDelta(t, -200.0/3.0, levels[7].Oscillator(), 1e-12)
+
+	s, _ := NewAroonStream64(25)
+	assert.Equal(t, 26, s.WarmupPeriod())
+	for _, b := range mockBars() {
+		s.UpdateBar(b)
+		l := s.Level()
+		assert.True(t, l.Up >= 0.0 && l.Up <= 100.0)
+		assert.True(t, l.Down >= 0.0 && l.Down <= 100.0)
+		assert.Equal(t, l.Oscillator(), s.Value())
+	}
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, 0.0, s.Up())
+	assert.Equal(t, c.Up()-c.Down(), c.Value())
+}
+
+func TestOscillatorPeriods(t *testing.T) {
+	series := readMockSeries64("mock/test_series.txt")
+	bars := PeriodBars(series, 60)
+
+	assert.Equal(t, CCI64(bars, 20), CCIPeriods64(series, 20, 60))
+	assert.Equal(t, WilliamsR64(bars, 14), WilliamsRPeriods64(series, 14, 60))
+	assert.Equal(t, Aroon64(bars, 25), AroonPeriods64(series, 25, 60))
+	assert.InDeltaSlice(t, to32(CCI64(bars, 20)), CCIPeriods32(to32(series), 20, 60), 1e-2)
+	assert.InDeltaSlice(t, to32(WilliamsR64(bars, 14)), WilliamsRPeriods32(to32(series), 14, 60), 1e-2)
+
+	assert.Nil(t, CCIPeriods64(series, 20, 0))
+	assert.Nil(t, WilliamsRPeriods64(series, 0, 60))
+	assert.Nil(t, AroonPeriods64(series, 25, -1))
+}
+
+func TestOscillatorExpr(t *testing.T) {
+	series := readMockSeries64("mock/test_series.txt")
+	bars := make([]Bar, len(series))
+	for i, v := range series {
+		bars[i] = valueBar(v)
+	}
+
+	aroon := Aroon64(bars, 25)
+	for src, expected := range map[string][]float64{
+		"cci(close, 20)":        CCI64(bars, 20),
+		"williams_r(close, 14)": WilliamsR64(bars, 14),
+		"aroon(close, 25)":      {aroon[len(aroon)-1].Oscillator()},
+	} {
+		values, err := EvalExprSeries(src, series)
+		assert.NoError(t, err, src)
+		assert.InDelta(t, expected[len(expected)-1], values[len(values)-1], 1e-9, src)
+	}
+}
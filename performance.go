@@ -0,0 +1,407 @@
+package technical
+
+import (
+	"math"
+)
+
+/*
+* Performance metrics score a returns or equity series, such as the equity curve of a backtest.
+*
+* Returns based metrics (Sharpe, Sortino, profit factor and hit rate) take a series of periodic returns,
+* e.g. Returns64 of an equity curve, and drawdown based metrics (max drawdown, Ulcer Index and Calmar) take the equity curve itself.
+* Rolling versions compute the metric over the last lb values of the series, with values before the first full window 0.0.
+ */
+
+// TradingDaysPerYear is the number of daily periods in a year typically used to annualize daily metrics
+const TradingDaysPerYear = 252
+
+// MetricsConfig configures the annualization and risk free rate of performance metrics
+type MetricsConfig struct {
+	// PeriodsPerYear is the number of return periods in a year, e.g. TradingDaysPerYear for daily returns.
+	// Metrics are not annualized if PeriodsPerYear <= 0.
+	PeriodsPerYear float64 `json:"periodsPerYear"`
+	// RiskFree is the annual risk free rate, or the per period rate if PeriodsPerYear <= 0.
+	RiskFree float64 `json:"riskFree"`
+}
+
+// periods returns the number of periods per year, 1 if metrics are not annualized
+func (c MetricsConfig) periods() float64 {
+	if !(c.PeriodsPerYear > 0.0) {
+		return 1.0
+	}
+
+	return c.PeriodsPerYear
+}
+
+// excess returns the returns less the per period risk free rate
+func (c MetricsConfig) excess(returns []float64) []float64 {
+	rf := c.RiskFree / c.periods()
+
+	xs := make([]float64, len(returns))
+	for i, r := range returns {
+		xs[i] = r - rf
+	}
+
+	return xs
+}
+
+// Returns64 computes the simple returns of an equity series, equity[i] / equity[i-1] - 1
+// The returns have one less value than the equity series. Returns nil for fewer than 2 values.
+func Returns64(equity []float64) []float64 {
+	if len(equity) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, len(equity)-1)
+	for i := 1; i < len(equity); i++ {
+		returns[i-1] = equity[i]/equity[i-1] - 1.0
+	}
+
+	return returns
+}
+
+// Returns32 is 32 bit version of Returns64
+func Returns32(equity []float32) []float32 {
+	return to32(Returns64(to64(equity)))
+}
+
+// Equity64 compounds a series of simple returns into an equity series starting at start
+// The equity series has one more value than the returns. Equivalent to the inverse of Returns64.
+func Equity64(returns []float64, start float64) []float64 {
+	equity := make([]float64, len(returns)+1)
+	equity[0] = start
+
+	for i, r := range returns {
+		equity[i+1] = equity[i] * (1.0 + r)
+	}
+
+	return equity
+}
+
+// Equity32 is 32 bit version of Equity64
+func Equity32(returns []float32, start float32) []float32 {
+	return to32(Equity64(to64(returns), float64(start)))
+}
+
+// Sharpe64 computes the Sharpe ratio of a returns series, the mean excess return over its population standard deviation
+// The ratio is annualized by the square root of the periods per year. Returns 0.0 for an empty series or no deviation.
+func Sharpe64(returns []float64, c MetricsConfig) float64 {
+	xs := c.excess(returns)
+
+	sd := StdDev64(xs)
+	if sd == 0.0 {
+		return 0.0
+	}
+
+	return SimpleAvg64(xs) / sd * math.Sqrt(c.periods())
+}
+
+// Sharpe32 is 32 bit version of Sharpe64
+func Sharpe32(returns []float32, c MetricsConfig) float32 {
+	return float32(Sharpe64(to64(returns), c))
+}
+
+// Sortino64 computes the Sortino ratio of a returns series, the mean excess return over its downside deviation
+// The downside deviation is the root mean square of the negative excess returns over all periods.
+// The ratio is annualized by the square root of the periods per year. Returns 0.0 for an empty series or no downside.
+func Sortino64(returns []float64, c MetricsConfig) float64 {
+	xs := c.excess(returns)
+	if len(xs) == 0 {
+		return 0.0
+	}
+
+	var downside float64
+	for _, x := range xs {
+		if x < 0.0 {
+			downside += x * x
+		}
+	}
+
+	if downside == 0.0 {
+		return 0.0
+	}
+
+	return SimpleAvg64(xs) / math.Sqrt(downside/float64(len(xs))) * math.Sqrt(c.periods())
+}
+
+// Sortino32 is 32 bit version of Sortino64
+func Sortino32(returns []float32, c MetricsConfig) float32 {
+	return float32(Sortino64(to64(returns), c))
+}
+
+// Drawdown is a decline of an equity series from a peak
+type Drawdown struct {
+	Depth    float64 `json:"depth"`    // decline from the peak as a fraction of the peak
+	Peak     int     `json:"peak"`     // index of the peak
+	Trough   int     `json:"trough"`   // index of the lowest value after the peak
+	Recovery int     `json:"recovery"` // index the series regained the peak, -1 if it has not
+	Duration int     `json:"duration"` // number of periods from the peak to the recovery, or to the end of the series
+}
+
+// MaxDrawdown64 finds the largest drawdown of an equity series
+// The zero Drawdown is returned for an empty or never declining series.
+func MaxDrawdown64(equity []float64) Drawdown {
+	var (
+		max  Drawdown
+		peak int
+	)
+
+	for i, v := range equity {
+		if v >= equity[peak] {
+			peak = i
+			continue
+		}
+
+		if depth := 1.0 - v/equity[peak]; depth > max.Depth {
+			max = Drawdown{Depth: depth, Peak: peak, Trough: i}
+		}
+	}
+
+	if max.Depth == 0.0 {
+		return Drawdown{}
+	}
+
+	max.Recovery = -1
+	max.Duration = len(equity) - 1 - max.Peak
+
+	for i := max.Trough + 1; i < len(equity); i++ {
+		if equity[i] >= equity[max.Peak] {
+			max.Recovery = i
+			max.Duration = i - max.Peak
+			break
+		}
+	}
+
+	return max
+}
+
+// MaxDrawdown32 is 32 bit version of MaxDrawdown64
+func MaxDrawdown32(equity []float32) Drawdown {
+	return MaxDrawdown64(to64(equity))
+}
+
+// UlcerIndex64 computes the Ulcer Index of an equity series
+// The Ulcer Index is the root mean square of the percentage drawdown of each value from the running peak.
+// Returns 0.0 for an empty series.
+func UlcerIndex64(equity []float64) float64 {
+	if len(equity) == 0 {
+		return 0.0
+	}
+
+	var (
+		sum  float64
+		peak = equity[0]
+	)
+
+	for _, v := range equity {
+		peak = math.Max(peak, v)
+		dd := 100.0 * (v - peak) / peak
+		sum += dd * dd
+	}
+
+	return math.Sqrt(sum / float64(len(equity)))
+}
+
+// UlcerIndex32 is 32 bit version of UlcerIndex64
+func UlcerIndex32(equity []float32) float32 {
+	return float32(UlcerIndex64(to64(equity)))
+}
+
+// Calmar64 computes the Calmar ratio of an equity series, the annualized return over the max drawdown
+// The annualized return compounds the total return over the periods of the series, it is the total return if not annualized.
+// The risk free rate is not used. Returns 0.0 for fewer than 2 values or no drawdown.
+func Calmar64(equity []float64, c MetricsConfig) float64 {
+	if len(equity) < 2 {
+		return 0.0
+	}
+
+	dd := MaxDrawdown64(equity)
+	if dd.Depth == 0.0 {
+		return 0.0
+	}
+
+	total := equity[len(equity)-1] / equity[0]
+	if c.PeriodsPerYear > 0.0 {
+		total = math.Pow(total, c.PeriodsPerYear/float64(len(equity)-1))
+	}
+
+	return (total - 1.0) / dd.Depth
+}
+
+// Calmar32 is 32 bit version of Calmar64
+func Calmar32(equity []float32, c MetricsConfig) float32 {
+	return float32(Calmar64(to64(equity), c))
+}
+
+// ProfitFactor64 computes the profit factor of a returns series, the sum of gains over the sum of losses
+// Also applies to the PnLs of trades. Returns +Inf for gains without losses and 0.0 without gains.
+func ProfitFactor64(returns []float64) float64 {
+	var gains, losses float64
+
+	for _, r := range returns {
+		if r > 0.0 {
+			gains += r
+		} else {
+			losses -= r
+		}
+	}
+
+	if gains == 0.0 {
+		return 0.0
+	}
+
+	return gains / losses
+}
+
+// ProfitFactor32 is 32 bit version of ProfitFactor64
+func ProfitFactor32(returns []float32) float32 {
+	return float32(ProfitFactor64(to64(returns)))
+}
+
+// HitRate64 computes the fraction of the non zero returns of a series which are positive
+// Also applies to the PnLs of trades. Returns 0.0 if there are no non zero returns.
+func HitRate64(returns []float64) float64 {
+	var wins, total int
+
+	for _, r := range returns {
+		if r != 0.0 {
+			total++
+		}
+
+		if r > 0.0 {
+			wins++
+		}
+	}
+
+	if total == 0 {
+		return 0.0
+	}
+
+	return float64(wins) / float64(total)
+}
+
+// HitRate32 is 32 bit version of HitRate64
+func HitRate32(returns []float32) float32 {
+	return float32(HitRate64(to64(returns)))
+}
+
+// rolling64 computes f over each window of lb values of series, 0.0 before the first full window
+// Returns nil for an empty series or lb <= 0
+func rolling64(series []float64, lb int, f func(xs []float64) float64) []float64 {
+	if len(series) == 0 || lb <= 0 {
+		return nil
+	}
+
+	res := make([]float64, len(series))
+	for i := lb - 1; i < len(series); i++ {
+		res[i] = f(series[i-lb+1 : i+1])
+	}
+
+	return res
+}
+
+// RollingSharpe64 computes the Sharpe ratio of each window of lb returns, see Sharpe64
+// Values with fewer than lb returns are 0.0. Returns nil for an empty series or lb <= 0.
+func RollingSharpe64(returns []float64, lb int, c MetricsConfig) []float64 {
+	return rolling64(returns, lb, func(xs []float64) float64 { return Sharpe64(xs, c) })
+}
+
+// RollingSharpe32 is 32 bit version of RollingSharpe64
+func RollingSharpe32(returns []float32, lb int, c MetricsConfig) []float32 {
+	return to32(RollingSharpe64(to64(returns), lb, c))
+}
+
+// RollingSortino64 computes the Sortino ratio of each window of lb returns, see Sortino64
+// Values with fewer than lb returns are 0.0. Returns nil for an empty series or lb <= 0.
+func RollingSortino64(returns []float64, lb int, c MetricsConfig) []float64 {
+	return rolling64(returns, lb, func(xs []float64) float64 { return Sortino64(xs, c) })
+}
+
+// RollingSortino32 is 32 bit version of RollingSortino64
+func RollingSortino32(returns []float32, lb int, c MetricsConfig) []float32 {
+	return to32(RollingSortino64(to64(returns), lb, c))
+}
+
+// RollingMaxDrawdown64 computes the depth of the max drawdown of each window of lb equity values, see MaxDrawdown64
+// Values with fewer than lb values are 0.0. Returns nil for an empty series or lb <= 0.
+func RollingMaxDrawdown64(equity []float64, lb int) []float64 {
+	return rolling64(equity, lb, func(xs []float64) float64 { return MaxDrawdown64(xs).Depth })
+}
+
+// RollingMaxDrawdown32 is 32 bit version of RollingMaxDrawdown64
+func RollingMaxDrawdown32(equity []float32, lb int) []float32 {
+	return to32(RollingMaxDrawdown64(to64(equity), lb))
+}
+
+// RollingUlcerIndex64 computes the Ulcer Index of each window of lb equity values, see UlcerIndex64
+// The drawdowns of each window are from the running peak within the window.
+// Values with fewer than lb values are 0.0. Returns nil for an empty series or lb <= 0.
+func RollingUlcerIndex64(equity []float64, lb int) []float64 {
+	return rolling64(equity, lb, UlcerIndex64)
+}
+
+// RollingUlcerIndex32 is 32 bit version of RollingUlcerIndex64
+func RollingUlcerIndex32(equity []float32, lb int) []float32 {
+	return to32(RollingUlcerIndex64(to64(equity), lb))
+}
+
+// RollingCalmar64 computes the Calmar ratio of each window of lb equity values, see Calmar64
+// Values with fewer than lb values are 0.0. Returns nil for an empty series or lb <= 0.
+func RollingCalmar64(equity []float64, lb int, c MetricsConfig) []float64 {
+	return rolling64(equity, lb, func(xs []float64) float64 { return Calmar64(xs, c) })
+}
+
+// RollingCalmar32 is 32 bit version of RollingCalmar64
+func RollingCalmar32(equity []float32, lb int, c MetricsConfig) []float32 {
+	return to32(RollingCalmar64(to64(equity), lb, c))
+}
+
+// RollingProfitFactor64 computes the profit factor of each window of lb returns, see ProfitFactor64
+// Values with fewer than lb returns are 0.0. Returns nil for an empty series or lb <= 0.
+func RollingProfitFactor64(returns []float64, lb int) []float64 {
+	return rolling64(returns, lb, ProfitFactor64)
+}
+
+// RollingProfitFactor32 is 32 bit version of RollingProfitFactor64
+func RollingProfitFactor32(returns []float32, lb int) []float32 {
+	return to32(RollingProfitFactor64(to64(returns), lb))
+}
+
+// RollingHitRate64 computes the hit rate of each window of lb returns, see HitRate64
+// Values with fewer than lb returns are 0.0. Returns nil for an empty series or lb <= 0.
+func RollingHitRate64(returns []float64, lb int) []float64 {
+	return rolling64(returns, lb, HitRate64)
+}
+
+// RollingHitRate32 is 32 bit version of RollingHitRate64
+func RollingHitRate32(returns []float32, lb int) []float32 {
+	return to32(RollingHitRate64(to64(returns), lb))
+}
+
+// to64 converts a 32 bit series to 64 bit, nil for a nil series
+func to64(xs []float32) []float64 {
+	if xs == nil {
+		return nil
+	}
+
+	res := make([]float64, len(xs))
+	for i, v := range xs {
+		res[i] = float64(v)
+	}
+
+	return res
+}
+
+// to32 converts a 64 bit series to 32 bit, nil for a nil series
+func to32(xs []float64) []float32 {
+	if xs == nil {
+		return nil
+	}
+
+	res := make([]float32, len(xs))
+	for i, v := range xs {
+		res[i] = float32(v)
+	}
+
+	return res
+}
@@ -0,0 +1,150 @@
+package technical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReturnsEquity(t *testing.T) {
+	equity := []float64{100, 110, 99, 99}
+
+	returns := Returns64(equity)
+	assert.InDeltaSlice(t, []float64{0.1, -0.1, 0}, returns, 1e-12)
+	assert.InDeltaSlice(t, equity, Equity64(returns, 100), 1e-9)
+
+	assert.Nil(t, Returns64([]float64{1}))
+	assert.Equal(t, []float64{5}, Equity64(nil, 5))
+
+	assert.InDeltaSlice(t, []float32{0.1, -0.1, 0}, Returns32([]float32{100, 110, 99, 99}), 1e-6)
+	assert.InDeltaSlice(t, []float32{100, 110, 99}, Equity32([]float32{0.1, -0.1}, 100), 1e-4)
+}
+
+func TestSharpe(t *testing.T) {
+	returns := []float64{0.01, 0.02, -0.01, 0.02}
+
+	// mean 0.01, population sd 0.0122474
+	assert.InDelta(t, 0.01/math.Sqrt(0.00015), Sharpe64(returns, MetricsConfig{}), 1e-9)
+	assert.InDelta(t, 0.01/math.Sqrt(0.00015)*math.Sqrt(252), Sharpe64(returns, MetricsConfig{PeriodsPerYear: TradingDaysPerYear}), 1e-9)
+
+	// annual risk free rate of 2.52% is 0.01% per day
+	c := MetricsConfig{PeriodsPerYear: TradingDaysPerYear, RiskFree: 0.0252}
+	assert.InDelta(t, 0.0099/math.Sqrt(0.00015)*math.Sqrt(252), Sharpe64(returns, c), 1e-9)
+
+	assert.Equal(t, 0.0, Sharpe64(nil, MetricsConfig{}))
+	assert.Equal(t, 0.0, Sharpe64([]float64{0.01, 0.01}, MetricsConfig{}))
+	assert.InDelta(t, 0.01/math.Sqrt(0.00015), Sharpe32([]float32{0.01, 0.02, -0.01, 0.02}, MetricsConfig{}), 1e-4)
+}
+
+func TestSortino(t *testing.T) {
+	returns := []float64{0.01, 0.02, -0.01, 0.02}
+
+	// downside deviation sqrt(0.0001 / 4)
+	assert.InDelta(t, 0.01/0.005, Sortino64(returns, MetricsConfig{}), 1e-9)
+	assert.Equal(t, 0.0, Sortino64([]float64{0.01, 0.02}, MetricsConfig{}))
+	assert.Equal(t, 0.0, Sortino64(nil, MetricsConfig{}))
+	assert.InDelta(t, 2.0, Sortino32([]float32{0.01, 0.02, -0.01, 0.02}, MetricsConfig{}), 1e-4)
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	equity := []float64{100, 120, 90, 110, 60, 80, 130, 125}
+
+	dd := MaxDrawdown64(equity)
+	assert.Equal(t, Drawdown{Depth: 0.5, Peak: 1, Trough: 4, Recovery: 6, Duration: 5}, dd)
+
+	// never recovered
+	dd = MaxDrawdown64([]float64{100, 120, 90, 110})
+	assert.InDelta(t, 0.25, dd.Depth, 1e-12)
+	assert.Equal(t, -1, dd.Recovery)
+	assert.Equal(t, 2, dd.Duration)
+
+	assert.Equal(t, Drawdown{}, MaxDrawdown64([]float64{1, 2, 3}))
+	assert.Equal(t, Drawdown{}, MaxDrawdown64(nil))
+	assert.Equal(t, 5, MaxDrawdown32([]float32{100, 120, 90, 110, 60, 80, 130, 125}).Duration)
+}
+
+func TestUlcerIndex(t *testing.T) {
+	// drawdowns 0, 0, -10%, -20%
+	assert.InDelta(t, math.Sqrt(500.0/4.0), UlcerIndex64([]float64{90, 100, 90, 80}), 1e-9)
+	assert.Equal(t, 0.0, UlcerIndex64([]float64{1, 2, 3}))
+	assert.Equal(t, 0.0, UlcerIndex64(nil))
+	assert.InDelta(t, math.Sqrt(500.0/4.0), UlcerIndex32([]float32{90, 100, 90, 80}), 1e-4)
+}
+
+func TestCalmar(t *testing.T) {
+	equity := []float64{100, 120, 90, 110}
+
+	// total return 10% over a max drawdown of 25%
+	assert.InDelta(t, 0.4, Calmar64(equity, MetricsConfig{}), 1e-12)
+	assert.InDelta(t, (math.Pow(1.1, 252.0/3.0)-1.0)/0.25, Calmar64(equity, MetricsConfig{PeriodsPerYear: TradingDaysPerYear}), 1e-6)
+
+	assert.Equal(t, 0.0, Calmar64([]float64{1, 2}, MetricsConfig{}))
+	assert.Equal(t, 0.0, Calmar64([]float64{1}, MetricsConfig{}))
+	assert.InDelta(t, 0.4, Calmar32([]float32{100, 120, 90, 110}, MetricsConfig{}), 1e-5)
+}
+
+func TestProfitFactorHitRate(t *testing.T) {
+	returns := []float64{0.02, -0.01, 0, 0.01, -0.01}
+
+	assert.InDelta(t, 1.5, ProfitFactor64(returns), 1e-12)
+	assert.Equal(t, math.Inf(1), ProfitFactor64([]float64{0.01}))
+	assert.Equal(t, 0.0, ProfitFactor64([]float64{-0.01, 0}))
+	assert.InDelta(t, float32(1.5), ProfitFactor32([]float32{0.02, -0.01, 0, 0.01, -0.01}), 1e-5)
+
+	// zero returns are not counted
+	assert.Equal(t, 0.5, HitRate64(returns))
+	assert.Equal(t, 0.0, HitRate64([]float64{0, 0}))
+	assert.Equal(t, float32(0.5), HitRate32([]float32{0.02, -0.01, 0, 0.01, -0.01}))
+}
+
+func TestRollingMetrics(t *testing.T) {
+	returns := []float64{0.01, 0.02, -0.01, 0.02, 0.03}
+	c := MetricsConfig{PeriodsPerYear: TradingDaysPerYear}
+
+	sharpe := RollingSharpe64(returns, 4, c)
+	assert.Equal(t, []float64{0, 0, 0}, sharpe[:3])
+	assert.InDelta(t, Sharpe64(returns[:4], c), sharpe[3], 1e-12)
+	assert.InDelta(t, Sharpe64(returns[1:], c), sharpe[4], 1e-12)
+
+	sortino := RollingSortino64(returns, 4, c)
+	assert.InDelta(t, Sortino64(returns[1:], c), sortino[4], 1e-12)
+
+	assert.InDeltaSlice(t, []float64{0, 0, 2.0 / 3.0, 2.0 / 3.0, 2.0 / 3.0}, RollingHitRate64(returns, 3), 1e-12)
+	assert.InDeltaSlice(t, []float64{0, 0, 3, 4, 5}, RollingProfitFactor64(returns, 3), 1e-9)
+
+	equity := []float64{100, 120, 90, 110, 60, 80}
+	assert.InDeltaSlice(t, []float64{0, 0, 0.25, 0.25, 0.4545454545, 0.4545454545}, RollingMaxDrawdown64(equity, 3), 1e-9)
+	assert.InDelta(t, UlcerIndex64(equity[3:]), RollingUlcerIndex64(equity, 3)[5], 1e-12)
+	assert.InDelta(t, Calmar64(equity[2:5], MetricsConfig{}), RollingCalmar64(equity, 3, MetricsConfig{})[4], 1e-12)
+
+	assert.Nil(t, RollingSharpe64(nil, 3, c))
+	assert.Nil(t, RollingHitRate64(returns, 0))
+	assert.Len(t, RollingSharpe32([]float32{0.01, 0.02, -0.01}, 2, c), 3)
+	assert.Len(t, RollingSortino32([]float32{0.01, 0.02, -0.01}, 2, c), 3)
+	assert.Len(t, RollingMaxDrawdown32([]float32{1, 2, 1}, 2), 3)
+	assert.Len(t, RollingUlcerIndex32([]float32{1, 2, 1}, 2), 3)
+	assert.Len(t, RollingCalmar32([]float32{1, 2, 1}, 2, c), 3)
+	assert.Len(t, RollingProfitFactor32([]float32{1, 2, 1}, 2), 3)
+	assert.Len(t, RollingHitRate32([]float32{1, 2, 1}, 2), 3)
+}
+
+func TestBacktestMetrics(t *testing.T) {
+	bb, _ := NewBollingerEMAStream64(20, 0, 2)
+	res, _ := Backtest(mockBars(), &bollingerReversion{bb: bb, qty: 100}, BacktestConfig{InitialCash: 100000})
+
+	pnls := make([]float64, len(res.Trades))
+	for i, tr := range res.Trades {
+		pnls[i] = tr.PnL
+	}
+
+	equity := res.Equity.Values
+	dd := MaxDrawdown64(equity)
+	assert.True(t, dd.Depth > 0.0)
+	assert.True(t, dd.Trough > dd.Peak)
+	assert.InDelta(t, 1.0-equity[dd.Trough]/equity[dd.Peak], dd.Depth, 1e-12)
+
+	hits := HitRate64(pnls)
+	assert.True(t, hits >= 0.0 && hits <= 1.0)
+	assert.InDelta(t, Sharpe64(Returns64(equity), MetricsConfig{}), RollingSharpe64(Returns64(equity), len(equity)-1, MetricsConfig{})[len(equity)-2], 1e-9)
+}
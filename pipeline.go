@@ -0,0 +1,378 @@
+package technical
+
+import (
+	"strconv"
+)
+
+/*
+* A pipeline composes streaming indicators into a directed acyclic graph, so the output of one indicator
+* can be the input of another, e.g. a Bollinger Band of an RSI or an EMA of an ATR.
+*
+* Nodes can only take earlier nodes as inputs, so the order nodes are added is a topological order
+* and each value fed to the pipeline evaluates every node once in that order.
+* Indicators added by name with Build are shared: building the same indicator with the same parameters
+* on the same input returns the existing node, e.g. one EMA used by both a MACD and a Keltner Channel.
+ */
+
+// Node is a handle to a value computed by a Pipeline
+type Node int
+
+// PipelineInput is the node of the values fed to a Pipeline
+const PipelineInput Node = 0
+
+type pipelineNode struct {
+	inputs []Node
+	ind    Indicator                  // set for indicator nodes
+	fn     func(xs []float64) float64 // set for function nodes
+	xs     []float64                  // input values of a function node
+	warmup int                        // number of pipeline values before the node is ready
+	value  float64
+	ready  bool
+}
+
+// Pipeline evaluates a DAG of streaming indicators once per value
+type Pipeline struct {
+	registry *Registry
+	nodes    []pipelineNode
+	shared   map[string]Node
+	bands    map[Node][2]Node
+	exprs    map[string]Node
+	outputs  map[string]Node
+}
+
+// NewPipeline creates a Pipeline building indicators by name from r, or DefaultRegistry if r is nil
+func NewPipeline(r *Registry) *Pipeline {
+	if r == nil {
+		r = DefaultRegistry
+	}
+
+	return &Pipeline{
+		registry: r,
+		nodes:    []pipelineNode{{warmup: 1}},
+		shared:   make(map[string]Node),
+		bands:    make(map[Node][2]Node),
+		exprs:    make(map[string]Node),
+		outputs:  make(map[string]Node),
+	}
+}
+
+// Len returns the number of nodes of the pipeline, including the input
+func (p *Pipeline) Len() int {
+	return len(p.nodes)
+}
+
+// checkNodes validates that the nodes exist
+func (p *Pipeline) checkNodes(nodes ...Node) error {
+	for _, n := range nodes {
+		if n < 0 || int(n) >= len(p.nodes) {
+			return ErrInvalidNode
+		}
+	}
+
+	return nil
+}
+
+// Apply adds a node computing ind on the values of input
+// ind is only updated once input is ready, so it does not see the warm up values of input.
+// Returns ErrInvalidNode if ind is nil or input does not exist
+func (p *Pipeline) Apply(ind Indicator, input Node) (Node, error) {
+	if ind == nil {
+		return 0, ErrInvalidNode
+	}
+
+	if err := p.checkNodes(input); err != nil {
+		return 0, err
+	}
+
+	p.nodes = append(p.nodes, pipelineNode{
+		inputs: []Node{input},
+		ind:    ind,
+		warmup: p.nodes[input].warmup + ind.WarmupPeriod() - 1,
+	})
+
+	return Node(len(p.nodes) - 1), nil
+}
+
+// Build adds a node computing the named indicator of the registry on the values of input, see Registry.Build
+// If the same indicator with the same parameters was already built on input its node is returned.
+func (p *Pipeline) Build(name string, params Params, input Node) (Node, error) {
+	if err := p.checkNodes(input); err != nil {
+		return 0, err
+	}
+
+	spec, ok := p.registry.Lookup(name)
+	if !ok {
+		return 0, errorf(ErrUnknownIndicator, "%s", name)
+	}
+
+	// key on the resolved parameters so defaults given explicitly or not share a node
+	resolved, err := spec.resolve(params)
+	if err != nil {
+		return 0, err
+	}
+
+	key := sharedKey(spec, resolved, input)
+	if n, ok := p.shared[key]; ok {
+		return n, nil
+	}
+
+	ind, err := spec.New(resolved)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := p.Apply(ind, input)
+	if err != nil {
+		return 0, err
+	}
+
+	p.shared[key] = n
+
+	return n, nil
+}
+
+// sharedKey returns the key of a built indicator, its name and parameters in the order of the spec and its input
+// The parameters are not formatted as a map, whose key order is only sorted by fmt from Go 1.12.
+func sharedKey(spec IndicatorSpec, params Params, input Node) string {
+	key := spec.Name + "("
+	for i, p := range spec.Params {
+		if i > 0 {
+			key += ","
+		}
+
+		key += p.Name + "=" + strconv.FormatFloat(params[p.Name], 'g', -1, 64)
+	}
+
+	return key + ")@" + strconv.Itoa(int(input))
+}
+
+// Combine adds a node computing f of the values of inputs, ready once every input is ready
+// Returns ErrInvalidNode if f is nil, there are no inputs, or an input does not exist
+func (p *Pipeline) Combine(f func(xs []float64) float64, inputs ...Node) (Node, error) {
+	if f == nil || len(inputs) == 0 {
+		return 0, ErrInvalidNode
+	}
+
+	if err := p.checkNodes(inputs...); err != nil {
+		return 0, err
+	}
+
+	warmup := 0
+	for _, in := range inputs {
+		if w := p.nodes[in].warmup; w > warmup {
+			warmup = w
+		}
+	}
+
+	p.nodes = append(p.nodes, pipelineNode{
+		inputs: append([]Node(nil), inputs...),
+		fn:     f,
+		xs:     make([]float64, len(inputs)),
+		warmup: warmup,
+	})
+
+	return Node(len(p.nodes) - 1), nil
+}
+
+// Diff adds a node computing a - b
+func (p *Pipeline) Diff(a Node, b Node) (Node, error) {
+	return p.Combine(func(xs []float64) float64 { return xs[0] - xs[1] }, a, b)
+}
+
+// Band adds nodes for the upper and lower bound of a band indicator node, the midpoint is the node itself
+// The bound nodes are shared, so calling Band again with n returns the same nodes.
+// Returns ErrInvalidNode if n is not a node of a BandIndicator
+func (p *Pipeline) Band(n Node) (upper Node, lower Node, err error) {
+	if err = p.checkNodes(n); err != nil {
+		return 0, 0, err
+	}
+
+	if b, ok := p.bands[n]; ok {
+		return b[0], b[1], nil
+	}
+
+	band, ok := p.nodes[n].ind.(BandIndicator)
+	if !ok {
+		return 0, 0, ErrInvalidNode
+	}
+
+	upper, err = p.Combine(func([]float64) float64 { return band.Bound().Upper }, n)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if lower, err = p.Combine(func([]float64) float64 { return band.Bound().Lower }, n); err != nil {
+		return 0, 0, err
+	}
+
+	p.bands[n] = [2]Node{upper, lower}
+
+	return upper, lower, nil
+}
+
+// MACD adds the nodes of a Moving Average Convergence Divergence of input
+// The MACD line is the fast EMA less the slow EMA, the signal line is an EMA of the MACD line
+// and the histogram is the MACD line less the signal line. The EMAs are shared with other nodes.
+//
+// Parameters:
+//
+//	fast: lookback of the fast EMA, typically 12
+//	slow: lookback of the slow EMA, typically 26
+//	signal: lookback of the signal EMA, typically 9
+func (p *Pipeline) MACD(fast int, slow int, signal int, input Node) (macd Node, sig Node, hist Node, err error) {
+	f, err := p.Build("ema", Params{"lb": float64(fast)}, input)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	s, err := p.Build("ema", Params{"lb": float64(slow)}, input)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if macd, err = p.Diff(f, s); err != nil {
+		return 0, 0, 0, err
+	}
+
+	if sig, err = p.Build("ema", Params{"lb": float64(signal)}, macd); err != nil {
+		return 0, 0, 0, err
+	}
+
+	hist, err = p.Diff(macd, sig)
+
+	return macd, sig, hist, err
+}
+
+// Keltner adds the nodes of a Keltner Channel of input
+// The midpoint is an EMA and the bounds are k ATRs above and below it. The EMA and ATR are shared with other nodes.
+// The ATR is computed with ATRStream64.Update, i.e. each value is a period.
+//
+// Parameters:
+//
+//	lb: lookback of the EMA midpoint
+//	n: number of periods of the ATR
+//	k: multiplier on the ATR
+func (p *Pipeline) Keltner(lb int, n int, k float64, input Node) (mid Node, upper Node, lower Node, err error) {
+	if err = checkMultiplier(k); err != nil {
+		return 0, 0, 0, err
+	}
+
+	if mid, err = p.Build("ema", Params{"lb": float64(lb)}, input); err != nil {
+		return 0, 0, 0, err
+	}
+
+	atr, err := p.Build("atr", Params{"n": float64(n)}, input)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if upper, err = p.Combine(func(xs []float64) float64 { return xs[0] + k*xs[1] }, mid, atr); err != nil {
+		return 0, 0, 0, err
+	}
+
+	lower, err = p.Combine(func(xs []float64) float64 { return xs[0] - k*xs[1] }, mid, atr)
+
+	return mid, upper, lower, err
+}
+
+// SetOutput names a node as an output of the pipeline
+// Returns ErrInvalidNode if n does not exist
+func (p *Pipeline) SetOutput(name string, n Node) error {
+	if err := p.checkNodes(n); err != nil {
+		return err
+	}
+
+	p.outputs[name] = n
+
+	return nil
+}
+
+// Update feeds the next value to the pipeline and evaluates every node in order
+func (p *Pipeline) Update(v float64) {
+	p.nodes[0].value = v
+	p.nodes[0].ready = true
+
+	for i := 1; i < len(p.nodes); i++ {
+		node := &p.nodes[i]
+
+		ready := true
+		for _, in := range node.inputs {
+			ready = ready && p.nodes[in].ready
+		}
+
+		if !ready {
+			continue
+		}
+
+		if node.ind != nil {
+			node.ind.Update(p.nodes[node.inputs[0]].value)
+			node.value = node.ind.Value()
+			node.ready = node.ind.Ready()
+			continue
+		}
+
+		for j, in := range node.inputs {
+			node.xs[j] = p.nodes[in].value
+		}
+
+		node.value = node.fn(node.xs)
+		node.ready = true
+	}
+}
+
+// Value returns the current value of node n, 0.0 for a node that does not exist
+func (p *Pipeline) Value(n Node) float64 {
+	if p.checkNodes(n) != nil {
+		return 0.0
+	}
+
+	return p.nodes[n].value
+}
+
+// WarmupPeriod returns the number of values fed to the pipeline before node n is ready, 0 for a node that does not exist
+func (p *Pipeline) WarmupPeriod(n Node) int {
+	if p.checkNodes(n) != nil {
+		return 0
+	}
+
+	return p.nodes[n].warmup
+}
+
+// Ready reports whether node n and all of its inputs are ready
+func (p *Pipeline) Ready(n Node) bool {
+	return p.checkNodes(n) == nil && p.nodes[n].ready
+}
+
+// Output returns the current value of the named output and whether it is ready
+func (p *Pipeline) Output(name string) (float64, bool) {
+	n, ok := p.outputs[name]
+	if !ok {
+		return 0.0, false
+	}
+
+	return p.nodes[n].value, p.nodes[n].ready
+}
+
+// Outputs returns the current values of the named outputs that are ready
+func (p *Pipeline) Outputs() map[string]float64 {
+	values := make(map[string]float64, len(p.outputs))
+	for name, n := range p.outputs {
+		if p.nodes[n].ready {
+			values[name] = p.nodes[n].value
+		}
+	}
+
+	return values
+}
+
+// Reset clears every node and indicator of the pipeline
+func (p *Pipeline) Reset() {
+	for i := range p.nodes {
+		node := &p.nodes[i]
+		if node.ind != nil {
+			node.ind.Reset()
+		}
+
+		node.value, node.ready = 0.0, false
+	}
+}
@@ -0,0 +1,128 @@
+package technical
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineShared(t *testing.T) {
+	testseries := readMockSeries64("./mock/test_series.txt")[:2000]
+
+	p := NewPipeline(nil)
+	macd, sig, hist, err := p.MACD(12, 26, 9, PipelineInput)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, p.Len())
+
+	// the 26 EMA is shared with the MACD
+	mid, upper, lower, err := p.Keltner(26, 14, 2, PipelineInput)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, p.Len())
+
+	ema26, err := p.Build("ema", Params{"lb": 26, "smoothing": 0}, PipelineInput)
+	assert.NoError(t, err)
+	assert.Equal(t, mid, ema26)
+
+	// the key lists the parameters in the order of the spec, not of a map
+	spec, _ := DefaultRegistry.Lookup("bollinger_ema")
+	for i := 0; i < 10; i++ {
+		key := sharedKey(spec, Params{"a": 2, "smoothing": 0.1, "lb": 20}, ema26)
+		assert.Equal(t, "bollinger_ema(lb=20,smoothing=0.1,a=2)@"+strconv.Itoa(int(ema26)), key)
+	}
+
+	assert.NoError(t, p.SetOutput("hist", hist))
+	assert.False(t, p.Ready(macd))
+
+	for _, v := range testseries {
+		p.Update(v)
+	}
+
+	fast := EwmaSeries64(testseries, 0, 12)
+	slow := EwmaSeries64(testseries, 0, 26)
+	macds := make([]float64, len(testseries)-25)
+	for i := range macds {
+		macds[i] = fast[i+25] - slow[i+25]
+	}
+
+	signals := EwmaSeries64(macds, 0, 9)
+	last := len(macds) - 1
+
+	assert.True(t, p.Ready(hist))
+	assert.InDelta(t, macds[last], p.Value(macd), 1e-9)
+	assert.InDelta(t, signals[last], p.Value(sig), 1e-9)
+
+	v, ok := p.Output("hist")
+	assert.True(t, ok)
+	assert.InDelta(t, macds[last]-signals[last], v, 1e-9)
+	assert.Equal(t, map[string]float64{"hist": v}, p.Outputs())
+
+	atr := StaticATR64(testseries, 14, 1)
+	assert.InDelta(t, slow[len(slow)-1]+2*atr, p.Value(upper), 1e-9)
+	assert.InDelta(t, slow[len(slow)-1]-2*atr, p.Value(lower), 1e-9)
+
+	p.Reset()
+	assert.False(t, p.Ready(macd))
+	_, ok = p.Output("hist")
+	assert.False(t, ok)
+}
+
+func TestPipelineStacked(t *testing.T) {
+	testseries := readMockSeries64("./mock/test_series.txt")[:2000]
+
+	// Bollinger Band of an RSI
+	p := NewPipeline(nil)
+	rsi, err := p.Build("rsi", Params{"n": 14}, PipelineInput)
+	assert.NoError(t, err)
+	bb, err := p.Build("bollinger_ema", Params{"lb": 20}, rsi)
+	assert.NoError(t, err)
+	upper, lower, err := p.Band(bb)
+	assert.NoError(t, err)
+
+	_, _, err = p.Band(rsi)
+	assert.Equal(t, ErrInvalidNode, err)
+
+	for _, v := range testseries {
+		p.Update(v)
+	}
+
+	// the band does not see the RSI warm up values
+	bounds := StaticBollingerEMA64(RSISeries64(testseries, 14)[14:], 20, 0, 2)
+	b := bounds[len(bounds)-1]
+	assert.InDelta(t, b.Midpoint, p.Value(bb), 1e-9)
+	assert.InDelta(t, b.Upper, p.Value(upper), 1e-9)
+	assert.InDelta(t, b.Lower, p.Value(lower), 1e-9)
+}
+
+func TestPipelineErrors(t *testing.T) {
+	p := NewPipeline(nil)
+
+	_, err := p.Apply(nil, PipelineInput)
+	assert.Equal(t, ErrInvalidNode, err)
+
+	ema, _ := NewEMAStream64(3, 0)
+	_, err = p.Apply(ema, Node(5))
+	assert.Equal(t, ErrInvalidNode, err)
+
+	_, err = p.Combine(nil, PipelineInput)
+	assert.Equal(t, ErrInvalidNode, err)
+
+	_, err = p.Combine(func(xs []float64) float64 { return xs[0] })
+	assert.Equal(t, ErrInvalidNode, err)
+
+	_, err = p.Build("vwap", nil, PipelineInput)
+	assert.Equal(t, ErrUnknownIndicator, Cause(err))
+
+	_, err = p.Build("ema", Params{"lb": 0}, PipelineInput)
+	assert.Equal(t, ErrInvalidParam, Cause(err))
+
+	_, _, _, err = p.Keltner(20, 14, -1, PipelineInput)
+	assert.Equal(t, ErrInvalidMultiplier, err)
+
+	assert.Equal(t, ErrInvalidNode, p.SetOutput("x", Node(-1)))
+	assert.Equal(t, 0.0, p.Value(Node(99)))
+	assert.False(t, p.Ready(Node(99)))
+
+	_, ok := p.Output("x")
+	assert.False(t, ok)
+}
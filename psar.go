@@ -0,0 +1,191 @@
+package technical
+
+import (
+	"math"
+)
+
+/*
+* Wilder's Parabolic SAR (stop and reverse) is a trailing stop that accelerates towards the price as the trend extends.
+*
+*	SAR = prior SAR + AF * (EP - prior SAR)
+*
+* EP, the extreme point, is the highest high of an up trend or the lowest low of a down trend. AF, the acceleration factor,
+* starts at step and increases by step each time a new extreme point is made, up to max. In an up trend the SAR is
+* never above the lows of the two previous bars, in a down trend never below their highs.
+* When a bar trades through the SAR the trend reverses: the SAR is set to the extreme point of the previous trend,
+* the extreme point to the high or low of the bar and AF back to step.
+ */
+
+// Default Parabolic SAR acceleration parameters of Wilder
+const (
+	DefaultSARStep = 0.02
+	DefaultSARMax  = 0.2
+)
+
+// ParabolicSAR64 computes the Parabolic SAR of each bar and the reversals of its trend, see ParabolicSARStream64
+// Levels before the stream is ready are empty. Returns nil for invalid parameters.
+func ParabolicSAR64(bars []Bar, step float64, max float64) ([]StopLevel, []Event) {
+	s, err := NewParabolicSARStream64(step, max)
+	if err != nil {
+		return nil, nil
+	}
+
+	var events []Event
+	levels := make([]StopLevel, len(bars))
+	for i, b := range bars {
+		s.UpdateBar(b)
+		if s.Ready() {
+			levels[i] = s.Level()
+		}
+
+		if e, ok := s.Reversal(); ok {
+			events = append(events, e)
+		}
+	}
+
+	return levels, events
+}
+
+// ParabolicSARStream64 computes Wilder's Parabolic SAR over a stream of bars
+// The trend starts on the second bar, up if its close is not below the first close with the SAR at the lowest low
+// of the two bars, else down with the SAR at their highest high.
+type ParabolicSARStream64 struct {
+	step     float64
+	max      float64
+	af       float64
+	ep       float64
+	level    StopLevel
+	prev     [2]Bar // previous bar and the one before it
+	n        int
+	reversal Event
+	reversed bool
+}
+
+// NewParabolicSARStream64 creates a ParabolicSARStream64
+// Returns ErrInvalidAcceleration if step <= 0 or max < step
+//
+// Parameters:
+//
+//	step: increment of the acceleration factor, typically DefaultSARStep (0.02)
+//	max: maximum of the acceleration factor, typically DefaultSARMax (0.2)
+func NewParabolicSARStream64(step float64, max float64) (*ParabolicSARStream64, error) {
+	if !(step > 0.0) || !(max >= step) || math.IsInf(max, 1) {
+		return nil, ErrInvalidAcceleration
+	}
+
+	return &ParabolicSARStream64{step: step, max: max}, nil
+}
+
+// Update adds the next value of the stream as a bar of one value
+func (s *ParabolicSARStream64) Update(v float64) {
+	s.UpdateBar(valueBar(v))
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *ParabolicSARStream64) UpdateBar(b Bar) {
+	s.reversed = false
+
+	switch s.n {
+	case 0:
+	case 1:
+		p := s.prev[0]
+		if b.Close >= p.Close {
+			s.level = StopLevel{Stop: math.Min(p.Low, b.Low), Long: true}
+			s.ep = math.Max(p.High, b.High)
+		} else {
+			s.level = StopLevel{Stop: math.Max(p.High, b.High)}
+			s.ep = math.Min(p.Low, b.Low)
+		}
+
+		s.af = s.step
+	default:
+		s.next(b)
+	}
+
+	s.prev[1], s.prev[0] = s.prev[0], b
+	s.n++
+}
+
+// next moves the SAR to bar b and reverses the trend if b trades through it
+func (s *ParabolicSARStream64) next(b Bar) {
+	sar := s.level.Stop + s.af*(s.ep-s.level.Stop)
+
+	if s.level.Long {
+		sar = math.Min(sar, math.Min(s.prev[0].Low, s.prev[1].Low))
+		if b.Low < sar {
+			s.reverse(b, EventReversalDown, math.Max(s.ep, b.High), b.Low)
+			return
+		}
+
+		if b.High > s.ep {
+			s.ep, s.af = b.High, math.Min(s.af+s.step, s.max)
+		}
+	} else {
+		sar = math.Max(sar, math.Max(s.prev[0].High, s.prev[1].High))
+		if b.High > sar {
+			s.reverse(b, EventReversalUp, math.Min(s.ep, b.Low), b.High)
+			return
+		}
+
+		if b.Low < s.ep {
+			s.ep, s.af = b.Low, math.Min(s.af+s.step, s.max)
+		}
+	}
+
+	s.level.Stop = sar
+}
+
+// reverse flips the trend on bar b to a SAR of sar and an extreme point of ep
+func (s *ParabolicSARStream64) reverse(b Bar, typ EventType, sar float64, ep float64) {
+	s.level = StopLevel{Stop: sar, Long: typ == EventReversalUp}
+	s.ep, s.af = ep, s.step
+	s.reversal = Event{Type: typ, Index: s.n, Time: b.End, Value: b.Close, Level: sar}
+	s.reversed = true
+}
+
+// Level returns the current SAR and the direction of the trend, Long for an up trend
+func (s *ParabolicSARStream64) Level() StopLevel {
+	return s.level
+}
+
+// Value returns the current SAR
+func (s *ParabolicSARStream64) Value() float64 {
+	return s.level.Stop
+}
+
+// Long reports whether the trend is up
+func (s *ParabolicSARStream64) Long() bool {
+	return s.level.Long
+}
+
+// Reversal returns the reversal of the trend on the last bar, with false if the trend did not reverse
+// The Event has the index of the bar in the stream, its end time and close, and the Level of the new SAR.
+func (s *ParabolicSARStream64) Reversal() (Event, bool) {
+	return s.reversal, s.reversed
+}
+
+// AccelerationFactor returns the current acceleration factor
+func (s *ParabolicSARStream64) AccelerationFactor() float64 {
+	return s.af
+}
+
+// Ready reports whether the stream has a trend, i.e. after two bars
+func (s *ParabolicSARStream64) Ready() bool {
+	return s.n >= 2
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *ParabolicSARStream64) WarmupPeriod() int {
+	return 2
+}
+
+// Reset clears the stream
+func (s *ParabolicSARStream64) Reset() {
+	*s = ParabolicSARStream64{step: s.step, max: s.max}
+}
+
+// Clone returns an independent copy of the stream
+func (s *ParabolicSARStream64) Clone() *ParabolicSARStream64 {
+	c := *s
+	return &c
+}
@@ -0,0 +1,108 @@
+package technical
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParabolicSAR(t *testing.T) {
+	bars := stopBars()
+
+	_, err := NewParabolicSARStream64(0, 0.2)
+	assert.Equal(t, ErrInvalidAcceleration, err)
+	_, err = NewParabolicSARStream64(0.02, 0.01)
+	assert.Equal(t, ErrInvalidAcceleration, err)
+	levels, events := ParabolicSAR64(bars, -1, 0.2)
+	assert.Nil(t, levels)
+	assert.Nil(t, events)
+
+	levels, events = ParabolicSAR64(bars, DefaultSARStep, DefaultSARMax)
+	assert.Equal(t, StopLevel{}, levels[0])
+
+	// the second bar closes up and starts the up trend at the lowest low, held below the two previous lows
+	for i, sar := range []float64{9.5, 9.5, 9.5, 9.74, 9.74 + 0.08*4.76} {
+		assert.True(t, levels[i+1].Long)
+		assert.InDelta(t, sar, levels[i+1].Stop, 1e-12)
+	}
+
+	// the low of 9.5 trades through the SAR and reverses to the highest high of the up trend
+	assert.Equal(t, StopLevel{Stop: 14.5}, levels[6])
+	assert.Equal(t, []Event{{Type: EventReversalDown, Index: 6, Time: bars[6].End, Value: 10, Level: 14.5}}, events)
+
+	// the first SAR of the down trend is held above the two previous highs
+	assert.Equal(t, StopLevel{Stop: 14.5}, levels[7])
+
+	s, _ := NewParabolicSARStream64(DefaultSARStep, DefaultSARMax)
+	assert.Equal(t, 2, s.WarmupPeriod())
+	for i, b := range bars {
+		s.UpdateBar(b)
+
+		_, ok := s.Reversal()
+		assert.Equal(t, i == 6, ok)
+	}
+
+	assert.False(t, s.Long())
+	assert.Equal(t, 14.5, s.Value())
+	assert.InDelta(t, 0.04, s.AccelerationFactor(), 1e-12)
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, StopLevel{}, s.Level())
+	assert.Equal(t, levels[len(levels)-1], c.Level())
+}
+
+func TestParabolicSARDownTrend(t *testing.T) {
+	bars := testBars(
+		[4]float64{10, 10.5, 9.5, 10},
+		[4]float64{10, 10.5, 8.5, 9},
+		[4]float64{9, 9.5, 7.5, 8},
+		[4]float64{8, 10.5, 7.5, 10},
+	)
+
+	levels, events := ParabolicSAR64(bars, DefaultSARStep, DefaultSARMax)
+
+	// the second bar closes down and starts the down trend at the highest high
+	assert.Equal(t, StopLevel{Stop: 10.5}, levels[1])
+	assert.Equal(t, StopLevel{Stop: 10.5}, levels[2])
+
+	// the high of 10.5 is not above the SAR, held at the previous highs, so the trend does not reverse
+	assert.Equal(t, StopLevel{Stop: 10.5}, levels[3])
+	assert.Empty(t, events)
+}
+
+func TestMockParabolicSAR(t *testing.T) {
+	bars := mockBars()
+	levels, events := ParabolicSAR64(bars, DefaultSARStep, DefaultSARMax)
+	assert.NotEmpty(t, events)
+
+	// the SAR is below the bar in an up trend and above it in a down trend
+	for i := 1; i < len(bars); i++ {
+		if levels[i].Long {
+			assert.True(t, levels[i].Stop <= bars[i].Low)
+		} else {
+			assert.True(t, levels[i].Stop >= bars[i].High)
+		}
+	}
+
+	// reversals alternate and flip the trend of their bar
+	for i, e := range events {
+		assert.Equal(t, e.Type == EventReversalUp, levels[e.Index].Long)
+		assert.Equal(t, levels[e.Index].Stop, e.Level)
+		assert.Equal(t, bars[e.Index].Close, e.Value)
+		assert.NotEqual(t, levels[e.Index-1].Long, levels[e.Index].Long)
+		if i > 0 {
+			assert.NotEqual(t, events[i-1].Type, e.Type)
+		}
+	}
+
+	// the acceleration factor never exceeds the maximum
+	s, _ := NewParabolicSARStream64(DefaultSARStep, DefaultSARMax)
+	for _, b := range bars {
+		s.UpdateBar(b)
+		assert.True(t, s.AccelerationFactor() <= DefaultSARMax)
+	}
+
+	assert.Equal(t, levels[len(levels)-1], s.Level())
+}
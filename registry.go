@@ -0,0 +1,466 @@
+package technical
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+/*
+* The registry looks up streaming indicators by name and builds them from parameters.
+*
+* Each indicator is registered with a schema of its parameters, so strategy configs can be validated
+* and indicators built from JSON (or YAML decoded into an IndicatorConfig) without code changes.
+ */
+
+// ParamType is the type of an indicator parameter
+type ParamType string
+
+const (
+	// ParamInt is an integer parameter such as a lookback
+	ParamInt ParamType = "int"
+	// ParamFloat is a real parameter such as a multiplier
+	ParamFloat ParamType = "float"
+)
+
+// MaxLookback is the largest lookback or number of periods of the default registry and expression indicators
+// It bounds the memory that an indicator config can allocate.
+const MaxLookback = 1 << 20
+
+// maxParamInt is the largest magnitude of an int parameter, an int on every platform
+const maxParamInt = math.MaxInt32
+
+// Param describes an indicator parameter
+// The valid range is Min <= v < Max, a nil Min or Max is unbounded.
+type Param struct {
+	Name        string    `json:"name" yaml:"name"`
+	Type        ParamType `json:"type" yaml:"type"`
+	Default     float64   `json:"default" yaml:"default"`
+	Min         *float64  `json:"min,omitempty" yaml:"min,omitempty"`
+	Max         *float64  `json:"max,omitempty" yaml:"max,omitempty"`
+	Description string    `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Validate reports whether v is a valid value of the parameter
+// Returns ErrInvalidParam if v is NaN or ±Inf, not an integer of at most 2^31 - 1 in magnitude
+// for an int parameter, or outside of the valid range
+func (p Param) Validate(v float64) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) || (p.Type == ParamInt && (v != math.Trunc(v) || math.Abs(v) > maxParamInt)) {
+		return errorf(ErrInvalidParam, "%s must be a %s, got %v", p.Name, p.Type, v)
+	}
+
+	if (p.Min != nil && v < *p.Min) || (p.Max != nil && v >= *p.Max) {
+		return errorf(ErrInvalidParam, "%s out of range, got %v", p.Name, v)
+	}
+
+	return nil
+}
+
+// Params are the parameter values of an indicator by name
+type Params map[string]float64
+
+// Int returns the named parameter as an int
+// Parameters validated as ParamInt always fit in an int.
+func (p Params) Int(name string) int {
+	return int(p[name])
+}
+
+// Float returns the named parameter
+func (p Params) Float(name string) float64 {
+	return p[name]
+}
+
+// IndicatorSpec describes an indicator that can be built by a Registry
+type IndicatorSpec struct {
+	Name        string  `json:"name" yaml:"name"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Params      []Param `json:"params" yaml:"params"`
+	// New builds the indicator from validated parameters with defaults applied.
+	New func(p Params) (Indicator, error) `json:"-" yaml:"-"`
+}
+
+// resolve validates params against the spec and applies the defaults
+func (s IndicatorSpec) resolve(params Params) (Params, error) {
+	known := make(map[string]bool, len(s.Params))
+	res := make(Params, len(s.Params))
+
+	for _, p := range s.Params {
+		known[p.Name] = true
+
+		v, ok := params[p.Name]
+		if !ok {
+			v = p.Default
+		}
+
+		if err := p.Validate(v); err != nil {
+			return nil, err
+		}
+
+		res[p.Name] = v
+	}
+
+	for name := range params {
+		if !known[name] {
+			return nil, errorf(ErrInvalidParam, "unknown parameter %s of %s", name, s.Name)
+		}
+	}
+
+	return res, nil
+}
+
+// IndicatorConfig is the name and parameters of an indicator to build, e.g. from a strategy config
+// Parameters not given take their default.
+type IndicatorConfig struct {
+	Name   string `json:"name" yaml:"name"`
+	Params Params `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// Registry looks up indicators by name and builds them from parameters
+// It holds Indicators, which are updated one value at a time. A TimeIndicator such as TimeEMAStream64 needs the time
+// of each value and the volume streams such as VWAPStream64 need whole bars, so neither can be registered
+// and they are not available to a Pipeline or an expression. They adapt to a BarIndicator instead.
+type Registry struct {
+	specs map[string]IndicatorSpec
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{specs: make(map[string]IndicatorSpec)}
+}
+
+// Register adds an indicator to the registry
+// Returns ErrInvalidSpec if the spec has no name or constructor, or the name is already registered
+func (r *Registry) Register(spec IndicatorSpec) error {
+	if spec.Name == "" || spec.New == nil {
+		return ErrInvalidSpec
+	}
+
+	if _, ok := r.specs[spec.Name]; ok {
+		return errorf(ErrInvalidSpec, "%s already registered", spec.Name)
+	}
+
+	r.specs[spec.Name] = spec
+
+	return nil
+}
+
+// Lookup returns the spec of the named indicator
+func (r *Registry) Lookup(name string) (IndicatorSpec, bool) {
+	spec, ok := r.specs[name]
+	return spec, ok
+}
+
+// Names returns the names of the registered indicators in ascending order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.specs))
+	for name := range r.specs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Specs returns the specs of the registered indicators in ascending name order
+func (r *Registry) Specs() []IndicatorSpec {
+	specs := make([]IndicatorSpec, 0, len(r.specs))
+	for _, name := range r.Names() {
+		specs = append(specs, r.specs[name])
+	}
+
+	return specs
+}
+
+// Build builds the named indicator from params
+// Returns ErrUnknownIndicator if the name is not registered and ErrInvalidParam if a parameter is unknown or invalid
+func (r *Registry) Build(name string, params Params) (Indicator, error) {
+	spec, ok := r.specs[name]
+	if !ok {
+		return nil, errorf(ErrUnknownIndicator, "%s", name)
+	}
+
+	resolved, err := spec.resolve(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return spec.New(resolved)
+}
+
+// BuildConfig builds the indicator of a config, see Build
+func (r *Registry) BuildConfig(c IndicatorConfig) (Indicator, error) {
+	return r.Build(c.Name, c.Params)
+}
+
+// BuildJSON builds the indicator of a JSON encoded IndicatorConfig, e.g. {"name": "ema", "params": {"lb": 20}}
+func (r *Registry) BuildJSON(data []byte) (Indicator, error) {
+	var c IndicatorConfig
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	return r.BuildConfig(c)
+}
+
+// DefaultRegistry holds the streaming indicators of the package
+var DefaultRegistry = newDefaultRegistry()
+
+// paramBound returns a pointer to v for a Param range
+func paramBound(v float64) *float64 {
+	return &v
+}
+
+func newDefaultRegistry() *Registry {
+	maxLookback := paramBound(MaxLookback + 1)
+	lb := Param{Name: "lb", Type: ParamInt, Default: 20, Min: paramBound(1), Max: maxLookback, Description: "lookback"}
+	smoothing := Param{Name: "smoothing", Type: ParamFloat, Default: 0, Min: paramBound(0), Max: paramBound(1),
+		Description: "EWMA smoothing factor, 0 for 2/(lb+1)"}
+	a := Param{Name: "a", Type: ParamFloat, Default: 2, Min: paramBound(0), Description: "standard deviation multiplier"}
+
+	r := NewRegistry()
+	specs := []IndicatorSpec{
+		{
+			Name:        "ema",
+			Description: "Exponentially Weighted Moving Average, see EMAStream64",
+			Params:      []Param{lb, smoothing},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewEMAStream64(p.Int("lb"), Smoothing(p.Float("smoothing")))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "bollinger_ema",
+			Description: "EWMA midpoint Bollinger Band, see BollingerEMAStream64",
+			Params:      []Param{lb, smoothing, a},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewBollingerEMAStream64(p.Int("lb"), Smoothing(p.Float("smoothing")), p.Float("a"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "linreg",
+			Description: "Linear Regression endpoint, see LinRegStream64",
+			Params:      []Param{lb},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewLinRegStream64(p.Int("lb"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "atr",
+			Description: "Average True Range, see ATRStream64",
+			Params:      []Param{{Name: "n", Type: ParamInt, Default: 14, Min: paramBound(1), Max: maxLookback, Description: "number of periods"}},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewATRStream64(p.Int("n"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "rsi",
+			Description: "Wilder Relative Strength Index, see RSIStream64",
+			Params:      []Param{{Name: "n", Type: ParamInt, Default: 14, Min: paramBound(1), Max: maxLookback, Description: "number of periods"}},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewRSIStream64(p.Int("n"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "momentum",
+			Description: "Momentum, the change over n periods, see MomentumStream64",
+			Params:      []Param{{Name: "n", Type: ParamInt, Default: 10, Min: paramBound(1), Max: maxLookback, Description: "number of periods"}},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewMomentumStream64(p.Int("n"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "roc",
+			Description: "Rate of Change, the percentage change over n periods, see ROCStream64",
+			Params:      []Param{{Name: "n", Type: ParamInt, Default: 10, Min: paramBound(1), Max: maxLookback, Description: "number of periods"}},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewROCStream64(p.Int("n"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "trix",
+			Description: "TRIX, the percentage change of a triple EWMA, see TRIXStream64",
+			Params:      []Param{{Name: "lb", Type: ParamInt, Default: 15, Min: paramBound(1), Max: maxLookback, Description: "lookback"}},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewTRIXStream64(p.Int("lb"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "ppo",
+			Description: "Percentage Price Oscillator, see PPOStream64",
+			Params: []Param{
+				{Name: "fast", Type: ParamInt, Default: 12, Min: paramBound(1), Max: maxLookback, Description: "lookback of the fast EWMA"},
+				{Name: "slow", Type: ParamInt, Default: 26, Min: paramBound(2), Max: maxLookback, Description: "lookback of the slow EWMA"},
+			},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewPPOStream64(p.Int("fast"), p.Int("slow"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "cmo",
+			Description: "Chande Momentum Oscillator, see CMOStream64",
+			Params:      []Param{{Name: "n", Type: ParamInt, Default: 14, Min: paramBound(1), Max: maxLookback, Description: "number of periods"}},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewCMOStream64(p.Int("n"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "cci",
+			Description: "Commodity Channel Index, see CCIStream64",
+			Params:      []Param{{Name: "n", Type: ParamInt, Default: 20, Min: paramBound(1), Max: maxLookback, Description: "number of bars"}},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewCCIStream64(p.Int("n"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "williams_r",
+			Description: "Williams %R, see WilliamsRStream64",
+			Params:      []Param{{Name: "n", Type: ParamInt, Default: 14, Min: paramBound(1), Max: maxLookback, Description: "number of bars"}},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewWilliamsRStream64(p.Int("n"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "aroon",
+			Description: "Aroon Oscillator, see AroonStream64",
+			Params:      []Param{{Name: "n", Type: ParamInt, Default: 25, Min: paramBound(1), Max: maxLookback, Description: "number of bars"}},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewAroonStream64(p.Int("n"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "ultimate",
+			Description: "Ultimate Oscillator, see UltimateOscillatorStream64",
+			Params: []Param{
+				{Name: "short", Type: ParamInt, Default: 7, Min: paramBound(1), Max: maxLookback, Description: "short lookback"},
+				{Name: "medium", Type: ParamInt, Default: 14, Min: paramBound(2), Max: maxLookback, Description: "medium lookback"},
+				{Name: "long", Type: ParamInt, Default: 28, Min: paramBound(3), Max: maxLookback, Description: "long lookback"},
+			},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewUltimateOscillatorStream64(p.Int("short"), p.Int("medium"), p.Int("long"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "psar",
+			Description: "Parabolic SAR, see ParabolicSARStream64",
+			Params: []Param{
+				{Name: "step", Type: ParamFloat, Default: DefaultSARStep, Min: paramBound(0), Description: "increment of the acceleration factor"},
+				{Name: "max", Type: ParamFloat, Default: DefaultSARMax, Min: paramBound(0), Description: "maximum of the acceleration factor"},
+			},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewParabolicSARStream64(p.Float("step"), p.Float("max"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "supertrend",
+			Description: "SuperTrend, see SuperTrendStream64",
+			Params: []Param{
+				{Name: "n", Type: ParamInt, Default: 10, Min: paramBound(1), Max: maxLookback, Description: "number of periods of the ATR"},
+				{Name: "k", Type: ParamFloat, Default: 3, Min: paramBound(0), Description: "multiplier on the ATR"},
+			},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewSuperTrendStream64(p.Int("n"), p.Float("k"), TickSize{})
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+		{
+			Name:        "zscore",
+			Description: "Rolling z-score, see ZScoreStream64",
+			Params:      []Param{lb},
+			New: func(p Params) (Indicator, error) {
+				ind, err := NewZScoreStream64(p.Int("lb"))
+				if err != nil {
+					return nil, err
+				}
+
+				return ind, nil
+			},
+		},
+	}
+
+	for _, spec := range specs {
+		if err := r.Register(spec); err != nil {
+			panic(err)
+		}
+	}
+
+	return r
+}
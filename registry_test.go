@@ -0,0 +1,102 @@
+package technical
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	assert.Equal(t, []string{"aroon", "atr", "bollinger_ema", "cci", "cmo", "ema", "linreg", "momentum", "ppo", "psar", "roc", "rsi",
+		"supertrend", "trix", "ultimate", "williams_r", "zscore"}, DefaultRegistry.Names())
+	assert.Len(t, DefaultRegistry.Specs(), 17)
+
+	spec, ok := DefaultRegistry.Lookup("ema")
+	assert.True(t, ok)
+	assert.Equal(t, "lb", spec.Params[0].Name)
+
+	// defaults
+	ind, err := DefaultRegistry.Build("ema", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, ind.WarmupPeriod())
+
+	ind, err = DefaultRegistry.Build("bollinger_ema", Params{"lb": 5, "a": 1.5})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, ind.WarmupPeriod())
+	_, ok = ind.(BandIndicator)
+	assert.True(t, ok)
+
+	ind, err = DefaultRegistry.Build("supertrend", Params{"n": 5})
+	assert.NoError(t, err)
+	assert.IsType(t, &SuperTrendStream64{}, ind)
+	_, ok = ind.(BandIndicator)
+	assert.True(t, ok)
+
+	_, err = DefaultRegistry.Build("psar", Params{"step": 0.3})
+	assert.Equal(t, ErrInvalidAcceleration, Cause(err))
+
+	_, err = DefaultRegistry.Build("vwap", nil)
+	assert.Equal(t, ErrUnknownIndicator, Cause(err))
+	assert.EqualError(t, err, "technical: unknown indicator: vwap")
+	assert.Equal(t, ErrInvalidLookback, Cause(ErrInvalidLookback))
+
+	for _, p := range []Params{{"lb": 0}, {"lb": 2.5}, {"lb": MaxLookback + 1}, {"lb": math.Inf(1)}, {"a": math.Inf(1)},
+		{"smoothing": 1}, {"a": -1}, {"period": 3}} {
+		_, err = DefaultRegistry.Build("bollinger_ema", p)
+		assert.Equal(t, ErrInvalidParam, Cause(err), "%v", p)
+	}
+
+	ind, err = DefaultRegistry.BuildJSON([]byte(`{"name": "atr", "params": {"n": 30}}`))
+	assert.NoError(t, err)
+	assert.IsType(t, &ATRStream64{}, ind)
+	assert.Equal(t, 30, ind.WarmupPeriod())
+
+	_, err = DefaultRegistry.BuildJSON([]byte(`{"name": `))
+	assert.Error(t, err)
+
+	// a huge lookback is rejected rather than allocated
+	_, err = DefaultRegistry.BuildJSON([]byte(`{"name": "bollinger_ema", "params": {"lb": 1e15}}`))
+	assert.Equal(t, ErrInvalidParam, Cause(err))
+
+	ind, err = DefaultRegistry.Build("ema", Params{"lb": MaxLookback})
+	assert.NoError(t, err)
+	assert.Equal(t, MaxLookback, ind.WarmupPeriod())
+
+	// schemas encode for configs and documentation
+	data, err := json.Marshal(spec)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "ema",
+		"description": "Exponentially Weighted Moving Average, see EMAStream64",
+		"params": [
+			{"name": "lb", "type": "int", "default": 20, "min": 1, "max": 1048577, "description": "lookback"},
+			{"name": "smoothing", "type": "float", "default": 0, "min": 0, "max": 1, "description": "EWMA smoothing factor, 0 for 2/(lb+1)"}
+		]
+	}`, string(data))
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+	assert.Equal(t, ErrInvalidSpec, r.Register(IndicatorSpec{Name: "lsma"}))
+
+	spec := IndicatorSpec{
+		Name:   "lsma",
+		Params: []Param{{Name: "lb", Type: ParamInt, Default: 10, Min: paramBound(1)}},
+		New: func(p Params) (Indicator, error) {
+			return NewLinRegStream64(p.Int("lb"))
+		},
+	}
+
+	// an int parameter without a maximum still fits in an int
+	assert.Equal(t, ErrInvalidParam, Cause(spec.Params[0].Validate(1e15)))
+	assert.NoError(t, spec.Params[0].Validate(1e9))
+
+	assert.NoError(t, r.Register(spec))
+	assert.Equal(t, ErrInvalidSpec, Cause(r.Register(spec)))
+
+	ind, err := r.BuildConfig(IndicatorConfig{Name: "lsma", Params: Params{"lb": 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, ind.WarmupPeriod())
+}
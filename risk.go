@@ -0,0 +1,374 @@
+package technical
+
+import (
+	"math"
+	"sort"
+)
+
+/*
+* Value-at-Risk (VaR) and Expected Shortfall (ES, also CVaR) estimate the tail risk of a returns series,
+* e.g. Returns64 or LogReturns64 of a price series.
+*
+* At a confidence level c, VaR is the loss that is not exceeded with probability c and ES is the expected loss
+* when it is exceeded. Both are reported as positive fractions, i.e. a VaR of 0.02 is a loss of 2%.
+*
+* The estimators are:
+*
+*	historical: the empirical quantile and tail mean of the returns
+*	gaussian: a normal distribution with the mean and standard deviation of the returns
+*	cornish-fisher: the gaussian quantile adjusted for the skew and excess kurtosis of the returns
+*	ewma: a zero mean normal distribution with the RiskMetrics exponentially weighted volatility of the returns
+ */
+
+// DefaultRiskLambda is the RiskMetrics decay factor of daily returns
+const DefaultRiskLambda = 0.94
+
+// VaRMethod is the estimator of a VaR or Expected Shortfall
+type VaRMethod int
+
+const (
+	// VaRHistorical estimates from the empirical distribution of the returns
+	VaRHistorical VaRMethod = iota
+	// VaRGaussian estimates from a normal distribution fitted to the returns
+	VaRGaussian
+	// VaRCornishFisher estimates from a normal distribution with a quantile adjusted for skew and kurtosis
+	VaRCornishFisher
+	// VaREWMA estimates from a zero mean normal distribution with an exponentially weighted volatility
+	VaREWMA
+)
+
+// RiskConfig configures a VaR or Expected Shortfall estimate
+type RiskConfig struct {
+	Confidence float64   `json:"confidence"` // confidence level, e.g. 0.95 or 0.99
+	Method     VaRMethod `json:"method"`
+	Lambda     float64   `json:"lambda"` // decay factor of VaREWMA, 0 for DefaultRiskLambda
+}
+
+// Validate checks the config
+// Returns ErrInvalidConfidence if the confidence is not in (0, 1), ErrInvalidSmoothing if lambda is not in [0, 1)
+// and ErrInvalidMethod for an unknown method
+func (c RiskConfig) Validate() error {
+	if !(c.Confidence > 0.0 && c.Confidence < 1.0) {
+		return ErrInvalidConfidence
+	}
+
+	if !(c.Lambda >= 0.0 && c.Lambda < 1.0) {
+		return ErrInvalidSmoothing
+	}
+
+	if c.Method < VaRHistorical || c.Method > VaREWMA {
+		return ErrInvalidMethod
+	}
+
+	return nil
+}
+
+// lambda returns the EWMA decay factor
+func (c RiskConfig) lambda() float64 {
+	if c.Lambda == 0.0 {
+		return DefaultRiskLambda
+	}
+
+	return c.Lambda
+}
+
+// LogReturns64 computes the log returns of a price series, ln(prices[i] / prices[i-1])
+// The returns have one less value than the prices. Returns nil for fewer than 2 values.
+func LogReturns64(prices []float64) []float64 {
+	if len(prices) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		returns[i-1] = math.Log(prices[i] / prices[i-1])
+	}
+
+	return returns
+}
+
+// LogReturns32 is 32 bit version of LogReturns64
+func LogReturns32(prices []float32) []float32 {
+	return to32(LogReturns64(to64(prices)))
+}
+
+// Skew64 computes the population skewness of a given list of values
+// Returns 0.0 for an empty list or no deviation.
+func Skew64(xs []float64) float64 {
+	sd := StdDev64(xs)
+	if sd == 0.0 {
+		return 0.0
+	}
+
+	avg := SimpleAvg64(xs)
+
+	var total float64
+	for _, v := range xs {
+		z := (v - avg) / sd
+		total += z * z * z
+	}
+
+	return total / float64(len(xs))
+}
+
+// Skew32 is 32 bit version of Skew64
+func Skew32(xs []float32) float32 {
+	return float32(Skew64(to64(xs)))
+}
+
+// ExcessKurtosis64 computes the population excess kurtosis of a given list of values, 0.0 for a normal distribution
+// Returns 0.0 for an empty list or no deviation.
+func ExcessKurtosis64(xs []float64) float64 {
+	sd := StdDev64(xs)
+	if sd == 0.0 {
+		return 0.0
+	}
+
+	avg := SimpleAvg64(xs)
+
+	var total float64
+	for _, v := range xs {
+		z := (v - avg) / sd
+		total += z * z * z * z
+	}
+
+	return total/float64(len(xs)) - 3.0
+}
+
+// ExcessKurtosis32 is 32 bit version of ExcessKurtosis64
+func ExcessKurtosis32(xs []float32) float32 {
+	return float32(ExcessKurtosis64(to64(xs)))
+}
+
+// normQuantile returns the quantile of the standard normal distribution at probability p
+func normQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2.0*p-1.0)
+}
+
+// normPDF returns the density of the standard normal distribution at z
+func normPDF(z float64) float64 {
+	return math.Exp(-0.5*z*z) / math.Sqrt(2.0*math.Pi)
+}
+
+// cornishFisher adjusts the standard normal quantile z for skew s and excess kurtosis k
+func cornishFisher(z float64, s float64, k float64) float64 {
+	return z + (z*z-1.0)*s/6.0 + (z*z*z-3.0*z)*k/24.0 - (2.0*z*z*z-5.0*z)*s*s/36.0
+}
+
+// ewmaVolatility returns the exponentially weighted root mean square of the returns, the latest return weighted most
+func ewmaVolatility(returns []float64, lambda float64) float64 {
+	var sum, weights float64
+
+	w := 1.0
+	for i := len(returns) - 1; i >= 0; i-- {
+		sum += w * returns[i] * returns[i]
+		weights += w
+		w *= lambda
+	}
+
+	return math.Sqrt(sum / weights)
+}
+
+// historicalTail returns the returns sorted ascending and the number of returns in the tail at confidence c
+func historicalTail(returns []float64, c float64) ([]float64, int) {
+	sorted := append([]float64(nil), returns...)
+	sort.Float64s(sorted)
+
+	// tolerate rounding of 1 - c, e.g. 20 * (1 - 0.9) is slightly over 2
+	m := int(math.Ceil(float64(len(sorted))*(1.0-c) - 1e-9))
+	if m < 1 {
+		m = 1
+	}
+
+	return sorted, m
+}
+
+// cfSteps is the number of quantiles averaged by the Cornish-Fisher Expected Shortfall
+const cfSteps = 100
+
+// VaR64 estimates the Value-at-Risk of a returns series, the loss not exceeded with the configured confidence
+// The historical VaR is the loss of the ceil(n * (1 - c))th worst return.
+// Returns 0.0 for an empty series or an invalid config, see VaRChecked64 to validate the parameters instead.
+func VaR64(returns []float64, c RiskConfig) float64 {
+	if len(returns) == 0 || c.Validate() != nil {
+		return 0.0
+	}
+
+	p := 1.0 - c.Confidence
+
+	switch c.Method {
+	case VaRGaussian:
+		return -(SimpleAvg64(returns) + normQuantile(p)*StdDev64(returns))
+	case VaRCornishFisher:
+		z := cornishFisher(normQuantile(p), Skew64(returns), ExcessKurtosis64(returns))
+		return -(SimpleAvg64(returns) + z*StdDev64(returns))
+	case VaREWMA:
+		return -normQuantile(p) * ewmaVolatility(returns, c.lambda())
+	}
+
+	sorted, m := historicalTail(returns, c.Confidence)
+
+	return -sorted[m-1]
+}
+
+// VaR32 is 32 bit version of VaR64
+func VaR32(returns []float32, c RiskConfig) float32 {
+	return float32(VaR64(to64(returns), c))
+}
+
+// VaRChecked64 is VaR64 returning an error for invalid parameters
+// Returns ErrEmptySeries and the errors of RiskConfig.Validate
+func VaRChecked64(returns []float64, c RiskConfig) (float64, error) {
+	if err := checkRisk(len(returns), 1, c); err != nil {
+		return 0.0, err
+	}
+
+	return VaR64(returns, c), nil
+}
+
+// VaRChecked32 is 32 bit version of VaRChecked64
+func VaRChecked32(returns []float32, c RiskConfig) (float32, error) {
+	if err := checkRisk(len(returns), 1, c); err != nil {
+		return 0.0, err
+	}
+
+	return VaR32(returns, c), nil
+}
+
+// ExpectedShortfall64 estimates the Expected Shortfall of a returns series, the expected loss beyond the VaR
+// The historical ES is the mean loss of the ceil(n * (1 - c)) worst returns.
+// The Cornish-Fisher ES averages the Cornish-Fisher VaR over evenly spaced confidence levels in the tail.
+// Returns 0.0 for an empty series or an invalid config,
+// see ExpectedShortfallChecked64 to validate the parameters instead.
+func ExpectedShortfall64(returns []float64, c RiskConfig) float64 {
+	if len(returns) == 0 || c.Validate() != nil {
+		return 0.0
+	}
+
+	p := 1.0 - c.Confidence
+
+	switch c.Method {
+	case VaRGaussian:
+		return -SimpleAvg64(returns) + StdDev64(returns)*normPDF(normQuantile(p))/p
+	case VaRCornishFisher:
+		avg, sd := SimpleAvg64(returns), StdDev64(returns)
+		s, k := Skew64(returns), ExcessKurtosis64(returns)
+
+		var total float64
+		for i := 0; i < cfSteps; i++ {
+			z := cornishFisher(normQuantile(p*(float64(i)+0.5)/cfSteps), s, k)
+			total += avg + z*sd
+		}
+
+		return -total / cfSteps
+	case VaREWMA:
+		return ewmaVolatility(returns, c.lambda()) * normPDF(normQuantile(p)) / p
+	}
+
+	sorted, m := historicalTail(returns, c.Confidence)
+
+	return -SimpleAvg64(sorted[:m])
+}
+
+// ExpectedShortfall32 is 32 bit version of ExpectedShortfall64
+func ExpectedShortfall32(returns []float32, c RiskConfig) float32 {
+	return float32(ExpectedShortfall64(to64(returns), c))
+}
+
+// ExpectedShortfallChecked64 is ExpectedShortfall64 returning an error for invalid parameters
+// Returns ErrEmptySeries and the errors of RiskConfig.Validate
+func ExpectedShortfallChecked64(returns []float64, c RiskConfig) (float64, error) {
+	if err := checkRisk(len(returns), 1, c); err != nil {
+		return 0.0, err
+	}
+
+	return ExpectedShortfall64(returns, c), nil
+}
+
+// ExpectedShortfallChecked32 is 32 bit version of ExpectedShortfallChecked64
+func ExpectedShortfallChecked32(returns []float32, c RiskConfig) (float32, error) {
+	if err := checkRisk(len(returns), 1, c); err != nil {
+		return 0.0, err
+	}
+
+	return ExpectedShortfall32(returns, c), nil
+}
+
+// RollingVaR64 estimates the VaR of each window of lb returns, see VaR64
+// Values with fewer than lb returns are 0.0. Returns nil for an empty series, lb <= 0 or an invalid config.
+// See RollingVaRChecked64 to validate the parameters instead.
+func RollingVaR64(returns []float64, lb int, c RiskConfig) []float64 {
+	if c.Validate() != nil {
+		return nil
+	}
+
+	return rolling64(returns, lb, func(xs []float64) float64 { return VaR64(xs, c) })
+}
+
+// RollingVaR32 is 32 bit version of RollingVaR64
+func RollingVaR32(returns []float32, lb int, c RiskConfig) []float32 {
+	return to32(RollingVaR64(to64(returns), lb, c))
+}
+
+// RollingVaRChecked64 is RollingVaR64 returning an error for invalid parameters
+// Returns ErrEmptySeries, ErrInvalidLookback if lb <= 0 or lb > len(returns) and the errors of RiskConfig.Validate
+func RollingVaRChecked64(returns []float64, lb int, c RiskConfig) ([]float64, error) {
+	if err := checkRisk(len(returns), lb, c); err != nil {
+		return nil, err
+	}
+
+	return RollingVaR64(returns, lb, c), nil
+}
+
+// RollingVaRChecked32 is 32 bit version of RollingVaRChecked64
+func RollingVaRChecked32(returns []float32, lb int, c RiskConfig) ([]float32, error) {
+	if err := checkRisk(len(returns), lb, c); err != nil {
+		return nil, err
+	}
+
+	return RollingVaR32(returns, lb, c), nil
+}
+
+// RollingExpectedShortfall64 estimates the Expected Shortfall of each window of lb returns, see ExpectedShortfall64
+// Values with fewer than lb returns are 0.0. Returns nil for an empty series, lb <= 0 or an invalid config.
+// See RollingExpectedShortfallChecked64 to validate the parameters instead.
+func RollingExpectedShortfall64(returns []float64, lb int, c RiskConfig) []float64 {
+	if c.Validate() != nil {
+		return nil
+	}
+
+	return rolling64(returns, lb, func(xs []float64) float64 { return ExpectedShortfall64(xs, c) })
+}
+
+// RollingExpectedShortfall32 is 32 bit version of RollingExpectedShortfall64
+func RollingExpectedShortfall32(returns []float32, lb int, c RiskConfig) []float32 {
+	return to32(RollingExpectedShortfall64(to64(returns), lb, c))
+}
+
+// RollingExpectedShortfallChecked64 is RollingExpectedShortfall64 returning an error for invalid parameters
+// Returns ErrEmptySeries, ErrInvalidLookback if lb <= 0 or lb > len(returns) and the errors of RiskConfig.Validate
+func RollingExpectedShortfallChecked64(returns []float64, lb int, c RiskConfig) ([]float64, error) {
+	if err := checkRisk(len(returns), lb, c); err != nil {
+		return nil, err
+	}
+
+	return RollingExpectedShortfall64(returns, lb, c), nil
+}
+
+// RollingExpectedShortfallChecked32 is 32 bit version of RollingExpectedShortfallChecked64
+func RollingExpectedShortfallChecked32(returns []float32, lb int, c RiskConfig) ([]float32, error) {
+	if err := checkRisk(len(returns), lb, c); err != nil {
+		return nil, err
+	}
+
+	return RollingExpectedShortfall32(returns, lb, c), nil
+}
+
+// checkRisk validates a returns series of size values with a lookback lb and a risk config
+func checkRisk(size int, lb int, c RiskConfig) error {
+	if err := checkSeries(size, lb); err != nil {
+		return err
+	}
+
+	return c.Validate()
+}
@@ -0,0 +1,193 @@
+package technical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// riskReturns are 20 returns, the two worst -0.10 and -0.05
+var riskReturns = []float64{
+	0.01, -0.10, 0.02, 0.00, -0.02, 0.03, 0.01, -0.05, 0.02, 0.01,
+	-0.01, 0.00, 0.02, -0.03, 0.01, 0.04, -0.01, 0.02, 0.00, 0.01,
+}
+
+func TestRiskConfig(t *testing.T) {
+	assert.Nil(t, RiskConfig{Confidence: 0.95}.Validate())
+	assert.Equal(t, ErrInvalidConfidence, RiskConfig{Confidence: 1}.Validate())
+	assert.Equal(t, ErrInvalidConfidence, RiskConfig{}.Validate())
+	assert.Equal(t, ErrInvalidSmoothing, RiskConfig{Confidence: 0.95, Lambda: 1}.Validate())
+	assert.Equal(t, ErrInvalidMethod, RiskConfig{Confidence: 0.95, Method: VaREWMA + 1}.Validate())
+
+	assert.Equal(t, 0.0, VaR64(riskReturns, RiskConfig{}))
+	assert.Equal(t, 0.0, ExpectedShortfall64(nil, RiskConfig{Confidence: 0.95}))
+	assert.Nil(t, RollingVaR64(riskReturns, 5, RiskConfig{}))
+}
+
+func TestRiskChecked(t *testing.T) {
+	c := RiskConfig{Confidence: 0.95}
+
+	_, err := VaRChecked64(riskReturns, RiskConfig{Confidence: 1.5})
+	assert.Equal(t, ErrInvalidConfidence, err)
+	_, err = ExpectedShortfallChecked64(riskReturns, RiskConfig{Confidence: 0.95, Method: -1})
+	assert.Equal(t, ErrInvalidMethod, err)
+	_, err = VaRChecked32(nil, c)
+	assert.Equal(t, ErrEmptySeries, err)
+	_, err = ExpectedShortfallChecked32(to32(riskReturns), RiskConfig{})
+	assert.Equal(t, ErrInvalidConfidence, err)
+	_, err = RollingVaRChecked64(riskReturns, 0, c)
+	assert.Equal(t, ErrInvalidLookback, err)
+	_, err = RollingExpectedShortfallChecked64(riskReturns, 5, RiskConfig{Confidence: 0.95, Method: VaREWMA + 1})
+	assert.Equal(t, ErrInvalidMethod, err)
+	_, err = RollingVaRChecked32(to32(riskReturns), 21, c)
+	assert.Equal(t, ErrInvalidLookback, err)
+	_, err = RollingExpectedShortfallChecked32(to32(riskReturns), 5, RiskConfig{Confidence: 0.95, Lambda: -1})
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	v, err := VaRChecked64(riskReturns, c)
+	assert.NoError(t, err)
+	assert.Equal(t, VaR64(riskReturns, c), v)
+
+	es, err := ExpectedShortfallChecked64(riskReturns, c)
+	assert.NoError(t, err)
+	assert.Equal(t, ExpectedShortfall64(riskReturns, c), es)
+
+	v32, err := VaRChecked32(to32(riskReturns), c)
+	assert.NoError(t, err)
+	assert.Equal(t, VaR32(to32(riskReturns), c), v32)
+
+	es32, err := ExpectedShortfallChecked32(to32(riskReturns), c)
+	assert.NoError(t, err)
+	assert.Equal(t, ExpectedShortfall32(to32(riskReturns), c), es32)
+
+	vars, err := RollingVaRChecked64(riskReturns, 10, c)
+	assert.NoError(t, err)
+	assert.Equal(t, RollingVaR64(riskReturns, 10, c), vars)
+
+	ess, err := RollingExpectedShortfallChecked32(to32(riskReturns), 10, c)
+	assert.NoError(t, err)
+	assert.Equal(t, RollingExpectedShortfall32(to32(riskReturns), 10, c), ess)
+}
+
+func TestLogReturns(t *testing.T) {
+	assert.InDeltaSlice(t, []float64{math.Log(1.1), math.Log(0.5)}, LogReturns64([]float64{10, 11, 5.5}), 1e-12)
+	assert.Nil(t, LogReturns64([]float64{10}))
+	assert.InDeltaSlice(t, []float32{float32(math.Log(1.1))}, LogReturns32([]float32{10, 11}), 1e-6)
+}
+
+func TestSkewKurtosis(t *testing.T) {
+	// symmetric
+	assert.InDelta(t, 0.0, Skew64([]float64{1, 2, 3}), 1e-12)
+	assert.InDelta(t, -1.5, ExcessKurtosis64([]float64{1, 2, 3}), 1e-12)
+
+	// population moments of {0, 0, 0, 4}: mean 1, variance 3
+	assert.InDelta(t, (3*-1.0+27.0)/4.0/math.Pow(3, 1.5), Skew64([]float64{0, 0, 0, 4}), 1e-12)
+	assert.InDelta(t, (3*1.0+81.0)/4.0/9.0-3.0, ExcessKurtosis64([]float64{0, 0, 0, 4}), 1e-12)
+
+	assert.Equal(t, 0.0, Skew64([]float64{2, 2}))
+	assert.Equal(t, 0.0, ExcessKurtosis64(nil))
+	assert.InDelta(t, float32(0.0), Skew32([]float32{1, 2, 3}), 1e-6)
+	assert.InDelta(t, float32(-1.5), ExcessKurtosis32([]float32{1, 2, 3}), 1e-5)
+}
+
+func TestNormQuantile(t *testing.T) {
+	for _, p := range []float64{0.001, 0.01, 0.05, 0.5, 0.95} {
+		assert.InDelta(t, distuv.UnitNormal.Quantile(p), normQuantile(p), 1e-9)
+		assert.InDelta(t, distuv.UnitNormal.Prob(normQuantile(p)), normPDF(normQuantile(p)), 1e-12)
+	}
+}
+
+func TestHistoricalVaR(t *testing.T) {
+	c := RiskConfig{Confidence: 0.9}
+
+	// tail of 2 returns
+	assert.InDelta(t, 0.05, VaR64(riskReturns, c), 1e-12)
+	assert.InDelta(t, 0.075, ExpectedShortfall64(riskReturns, c), 1e-12)
+
+	// tail of 1 return
+	c.Confidence = 0.99
+	assert.InDelta(t, 0.10, VaR64(riskReturns, c), 1e-12)
+	assert.InDelta(t, 0.10, ExpectedShortfall64(riskReturns, c), 1e-12)
+
+	assert.InDelta(t, 0.05, VaR32(to32(riskReturns), RiskConfig{Confidence: 0.9}), 1e-6)
+	assert.InDelta(t, 0.075, ExpectedShortfall32(to32(riskReturns), RiskConfig{Confidence: 0.9}), 1e-6)
+}
+
+func TestGaussianVaR(t *testing.T) {
+	c := RiskConfig{Confidence: 0.95, Method: VaRGaussian}
+	avg, sd := SimpleAvg64(riskReturns), StdDev64(riskReturns)
+	n := distuv.Normal{Mu: avg, Sigma: sd}
+
+	assert.InDelta(t, -n.Quantile(0.05), VaR64(riskReturns, c), 1e-9)
+
+	// tail mean of the fitted normal
+	z := distuv.UnitNormal.Quantile(0.05)
+	assert.InDelta(t, -avg+sd*distuv.UnitNormal.Prob(z)/0.05, ExpectedShortfall64(riskReturns, c), 1e-9)
+}
+
+func TestCornishFisherVaR(t *testing.T) {
+	// no skew or kurtosis is the normal quantile
+	assert.Equal(t, -1.5, cornishFisher(-1.5, 0, 0))
+
+	c := RiskConfig{Confidence: 0.99, Method: VaRCornishFisher}
+	gaussian := RiskConfig{Confidence: 0.99, Method: VaRGaussian}
+
+	// negative skew and fat tails increase the VaR over the gaussian
+	assert.True(t, Skew64(riskReturns) < 0.0)
+	assert.True(t, ExcessKurtosis64(riskReturns) > 0.0)
+	assert.True(t, VaR64(riskReturns, c) > VaR64(riskReturns, gaussian))
+	assert.True(t, ExpectedShortfall64(riskReturns, c) > VaR64(riskReturns, c))
+
+	z := cornishFisher(normQuantile(0.01), Skew64(riskReturns), ExcessKurtosis64(riskReturns))
+	assert.InDelta(t, -(SimpleAvg64(riskReturns) + z*StdDev64(riskReturns)), VaR64(riskReturns, c), 1e-12)
+}
+
+func TestEWMAVaR(t *testing.T) {
+	c := RiskConfig{Confidence: 0.95, Method: VaREWMA, Lambda: 0.5}
+	returns := []float64{0.01, -0.02}
+
+	// latest return weighted 1, previous 0.5
+	sd := math.Sqrt((0.0004 + 0.5*0.0001) / 1.5)
+	z := distuv.UnitNormal.Quantile(0.05)
+	assert.InDelta(t, -z*sd, VaR64(returns, c), 1e-9)
+	assert.InDelta(t, sd*distuv.UnitNormal.Prob(z)/0.05, ExpectedShortfall64(returns, c), 1e-9)
+
+	// default lambda
+	c.Lambda = 0
+	assert.InDelta(t, -z*ewmaVolatility(returns, DefaultRiskLambda), VaR64(returns, c), 1e-12)
+}
+
+func TestRollingVaR(t *testing.T) {
+	for _, m := range []VaRMethod{VaRHistorical, VaRGaussian, VaRCornishFisher, VaREWMA} {
+		c := RiskConfig{Confidence: 0.95, Method: m}
+
+		vars := RollingVaR64(riskReturns, 10, c)
+		es := RollingExpectedShortfall64(riskReturns, 10, c)
+		assert.Len(t, vars, len(riskReturns))
+		assert.Equal(t, 0.0, vars[8])
+		assert.Equal(t, 0.0, es[8])
+
+		for i := 9; i < len(riskReturns); i++ {
+			assert.InDelta(t, VaR64(riskReturns[i-9:i+1], c), vars[i], 1e-12)
+			assert.InDelta(t, ExpectedShortfall64(riskReturns[i-9:i+1], c), es[i], 1e-12)
+		}
+	}
+
+	assert.Len(t, RollingVaR32(to32(riskReturns), 10, RiskConfig{Confidence: 0.95}), len(riskReturns))
+	assert.Len(t, RollingExpectedShortfall32(to32(riskReturns), 10, RiskConfig{Confidence: 0.95}), len(riskReturns))
+}
+
+func TestMockVaR(t *testing.T) {
+	returns := LogReturns64(readMockSeries64("mock/test_series.txt"))
+
+	// the expected shortfall is at least the VaR for every method
+	for _, m := range []VaRMethod{VaRHistorical, VaRGaussian, VaRCornishFisher, VaREWMA} {
+		c := RiskConfig{Confidence: 0.99, Method: m}
+
+		v := VaR64(returns, c)
+		assert.True(t, v > 0.0)
+		assert.True(t, ExpectedShortfall64(returns, c) >= v)
+	}
+}
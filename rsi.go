@@ -0,0 +1,219 @@
+package technical
+
+/*
+Relative Strength Index (RSI) developed by J. Welles Wilder Jr
+is a momentum oscillator between 0 and 100 comparing the size of recent gains to recent losses.
+*/
+
+// rsiFromAverages64 computes the RSI from the average gain and average loss
+// An RSI with no losses is 100, and with no gains or losses 50.
+func rsiFromAverages64(gain float64, loss float64) float64 {
+	if loss == 0.0 {
+		if gain == 0.0 {
+			return 50.0
+		}
+
+		return 100.0
+	}
+
+	return 100.0 - 100.0/(1.0+gain/loss)
+}
+
+// rsiFromAverages32 is 32 bit version of rsiFromAverages64
+func rsiFromAverages32(gain float32, loss float32) float32 {
+	if loss == 0.0 {
+		if gain == 0.0 {
+			return 50.0
+		}
+
+		return 100.0
+	}
+
+	return 100.0 - 100.0/(1.0+gain/loss)
+}
+
+// RSISeries64 computes the Wilder RSI for each value of a series over n periods (changes)
+// The first average gain and loss are the simple averages of the first n changes,
+// after that they are smoothed with RollingATR64, i.e. the Wilder EWMA.
+// Values with fewer than n prior changes are 0.0. Returns nil for an empty series or n <= 0.
+func RSISeries64(series []float64, n int) []float64 {
+	if len(series) == 0 || n <= 0 {
+		return nil
+	}
+
+	rsis := make([]float64, len(series))
+	var gain, loss float64
+
+	for i := 1; i < len(series); i++ {
+		var g, l float64
+		if d := series[i] - series[i-1]; d > 0.0 {
+			g = d
+		} else {
+			l = -d
+		}
+
+		switch {
+		case i < n:
+			gain += g
+			loss += l
+			continue
+		case i == n: // first is simple average
+			gain = (gain + g) / float64(n)
+			loss = (loss + l) / float64(n)
+		default:
+			gain = RollingATR64(gain, g, n)
+			loss = RollingATR64(loss, l, n)
+		}
+
+		rsis[i] = rsiFromAverages64(gain, loss)
+	}
+
+	return rsis
+}
+
+// RSISeries32 is 32 bit version of RSISeries64
+func RSISeries32(series []float32, n int) []float32 {
+	if len(series) == 0 || n <= 0 {
+		return nil
+	}
+
+	rsis := make([]float32, len(series))
+	var gain, loss float32
+
+	for i := 1; i < len(series); i++ {
+		var g, l float32
+		if d := series[i] - series[i-1]; d > 0.0 {
+			g = d
+		} else {
+			l = -d
+		}
+
+		switch {
+		case i < n:
+			gain += g
+			loss += l
+			continue
+		case i == n: // first is simple average
+			gain = (gain + g) / float32(n)
+			loss = (loss + l) / float32(n)
+		default:
+			gain = RollingATR32(gain, g, n)
+			loss = RollingATR32(loss, l, n)
+		}
+
+		rsis[i] = rsiFromAverages32(gain, loss)
+	}
+
+	return rsis
+}
+
+// RSISeriesChecked64 is RSISeries64 with input validation
+// Returns ErrEmptySeries for an empty series and ErrInvalidPeriods if n <= 0
+func RSISeriesChecked64(series []float64, n int) ([]float64, error) {
+	if err := checkRSI(len(series), n); err != nil {
+		return nil, err
+	}
+
+	return RSISeries64(series, n), nil
+}
+
+// RSISeriesChecked32 is 32 bit version of RSISeriesChecked64
+func RSISeriesChecked32(series []float32, n int) ([]float32, error) {
+	if err := checkRSI(len(series), n); err != nil {
+		return nil, err
+	}
+
+	return RSISeries32(series, n), nil
+}
+
+// checkRSI validates the parameters of an RSI series
+func checkRSI(size int, n int) error {
+	if size == 0 {
+		return ErrEmptySeries
+	}
+
+	if n <= 0 {
+		return ErrInvalidPeriods
+	}
+
+	return nil
+}
+
+// RSIStream64 computes the Wilder RSI over a stream of values
+// Equivalent to RSISeries64 computed one value at a time.
+type RSIStream64 struct {
+	n     int
+	gain  float64
+	loss  float64
+	last  float64
+	count int // number of values seen
+}
+
+// NewRSIStream64 creates an RSIStream64 over n periods
+// Returns ErrInvalidPeriods if n <= 0
+func NewRSIStream64(n int) (*RSIStream64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	return &RSIStream64{n: n}, nil
+}
+
+// Update adds the next value of the stream
+func (s *RSIStream64) Update(v float64) {
+	s.count++
+
+	if s.count == 1 {
+		s.last = v
+		return
+	}
+
+	var g, l float64
+	if d := v - s.last; d > 0.0 {
+		g = d
+	} else {
+		l = -d
+	}
+
+	s.last = v
+
+	changes := s.count - 1
+	switch {
+	case changes <= s.n: // warm up is the simple average
+		s.gain += (g - s.gain) / float64(changes)
+		s.loss += (l - s.loss) / float64(changes)
+	default:
+		s.gain = RollingATR64(s.gain, g, s.n)
+		s.loss = RollingATR64(s.loss, l, s.n)
+	}
+}
+
+// Value returns the current RSI, 0.0 until the stream is ready
+func (s *RSIStream64) Value() float64 {
+	if !s.Ready() {
+		return 0.0
+	}
+
+	return rsiFromAverages64(s.gain, s.loss)
+}
+
+// Ready reports whether n changes have been seen
+func (s *RSIStream64) Ready() bool {
+	return s.count > s.n
+}
+
+// WarmupPeriod returns the number of values before the stream is ready
+func (s *RSIStream64) WarmupPeriod() int {
+	return s.n + 1
+}
+
+// Reset clears the stream
+func (s *RSIStream64) Reset() {
+	*s = RSIStream64{n: s.n}
+}
+
+// Clone returns an independent copy of the stream
+func (s *RSIStream64) Clone() *RSIStream64 {
+	c := *s
+	return &c
+}
@@ -0,0 +1,61 @@
+package technical
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRSISeries64(t *testing.T) {
+	assert.Equal(t, []float64{0, 0, 100, 50, 75}, RSISeries64([]float64{1, 2, 3, 2, 3}, 2))
+	assert.Equal(t, []float64{0, 50}, RSISeries64([]float64{1, 1}, 1))
+	assert.Nil(t, RSISeries64(nil, 14))
+	assert.Nil(t, RSISeries64([]float64{1}, 0))
+
+	testseries := readMockSeries64("./mock/test_series.txt")
+	rsis := RSISeries64(testseries, 300)
+	for _, v := range rsis {
+		if v < 0.0 || v > 100.0 {
+			t.Fatalf("rsi out of range %v", v)
+		}
+	}
+
+	_, err := RSISeriesChecked64(nil, 14)
+	assert.Equal(t, ErrEmptySeries, err)
+	_, err = RSISeriesChecked64(testseries, 0)
+	assert.Equal(t, ErrInvalidPeriods, err)
+	checked, err := RSISeriesChecked64(testseries, 300)
+	assert.NoError(t, err)
+	assert.Equal(t, rsis, checked)
+}
+
+func TestRSISeries32(t *testing.T) {
+	assert.Equal(t, []float32{0, 0, 100, 50, 75}, RSISeries32([]float32{1, 2, 3, 2, 3}, 2))
+	assert.Nil(t, RSISeries32(nil, 14))
+
+	testseries := readMockSeries32("./mock/test_series.txt")
+	rsis := RSISeries32(testseries, 300)
+	expected := RSISeries64(readMockSeries64("./mock/test_series.txt"), 300)
+	assert.InDelta(t, expected[len(expected)-1], rsis[len(rsis)-1], 1e-1)
+
+	_, err := RSISeriesChecked32(testseries, -1)
+	assert.Equal(t, ErrInvalidPeriods, err)
+}
+
+func TestRSIStream64(t *testing.T) {
+	_, err := NewRSIStream64(0)
+	assert.Equal(t, ErrInvalidPeriods, err)
+
+	testseries := readMockSeries64("./mock/test_series.txt")
+	s, err := NewRSIStream64(300)
+	assert.NoError(t, err)
+	assert.Equal(t, 301, s.WarmupPeriod())
+
+	expected := RSISeries64(testseries, 300)
+	actual := UpdateSeries(s, testseries)
+	assert.InDeltaSlice(t, expected, actual, 1e-9)
+
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, 0.0, s.Value())
+}
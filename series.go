@@ -0,0 +1,205 @@
+package technical
+
+import (
+	"sort"
+	"time"
+)
+
+// Series is a time series of values and their timestamps in ascending time order
+// Unlike a bare []float64 a Series does not assume its values are evenly spaced.
+type Series struct {
+	Times  []time.Time `json:"times"`
+	Values []float64   `json:"values"`
+}
+
+// BandSeries is a time series of Bollinger style Bounds and their timestamps in ascending time order
+type BandSeries struct {
+	Times  []time.Time `json:"times"`
+	Bounds []Bound64   `json:"bounds"`
+}
+
+// NewSeries creates a Series from values and their timestamps
+// Returns ErrLengthMismatch if the lengths differ and ErrUnsortedSeries if times are not ascending
+func NewSeries(times []time.Time, values []float64) (Series, error) {
+	if len(times) != len(values) {
+		return Series{}, ErrLengthMismatch
+	}
+
+	for i := 1; i < len(times); i++ {
+		if times[i].Before(times[i-1]) {
+			return Series{}, ErrUnsortedSeries
+		}
+	}
+
+	return Series{Times: times, Values: values}, nil
+}
+
+// Len returns the number of values in the series
+func (s Series) Len() int {
+	return len(s.Values)
+}
+
+// search returns the index of the last value at or before t, -1 if there is none
+func (s Series) search(t time.Time) int {
+	return sort.Search(len(s.Times), func(i int) bool { return s.Times[i].After(t) }) - 1
+}
+
+// AsOf returns the last value observed at or before t
+// ok is false if the series has no value at or before t
+func (s Series) AsOf(t time.Time) (v float64, ok bool) {
+	i := s.search(t)
+	if i < 0 {
+		return 0.0, false
+	}
+
+	return s.Values[i], true
+}
+
+// Between returns the part of the series with from <= time < to
+func (s Series) Between(from time.Time, to time.Time) Series {
+	i := sort.Search(len(s.Times), func(i int) bool { return !s.Times[i].Before(from) })
+	j := sort.Search(len(s.Times), func(i int) bool { return !s.Times[i].Before(to) })
+	if j < i {
+		j = i
+	}
+
+	return Series{Times: s.Times[i:j], Values: s.Values[i:j]}
+}
+
+// AlignAsOf aligns other to the timestamps of s
+// For each timestamp of s the last value of other at or before it is taken, so no future value of other is used.
+// Timestamps of s before the first value of other are dropped from both results.
+// The returned series have the same timestamps.
+func (s Series) AlignAsOf(other Series) (Series, Series) {
+	var left, right Series
+
+	j := -1
+	for i, t := range s.Times {
+		// advance j to the last value of other at or before t
+		for j+1 < len(other.Times) && !other.Times[j+1].After(t) {
+			j++
+		}
+
+		if j < 0 {
+			continue
+		}
+
+		left.Times = append(left.Times, t)
+		left.Values = append(left.Values, s.Values[i])
+		right.Times = append(right.Times, t)
+		right.Values = append(right.Values, other.Values[j])
+	}
+
+	return left, right
+}
+
+// Lag returns the series with each value moved n observations later
+// The value at each timestamp is the value n observations before it. The first n timestamps are dropped.
+// A negative n leads the series instead, which looks ahead and should not be used to compute signals.
+func (s Series) Lag(n int) Series {
+	size := s.Len()
+
+	switch {
+	case n == 0:
+		return s
+	case n >= size || -n >= size:
+		return Series{}
+	case n > 0:
+		return Series{Times: s.Times[n:], Values: s.Values[:size-n]}
+	default:
+		return Series{Times: s.Times[:size+n], Values: s.Values[-n:]}
+	}
+}
+
+// Shift returns the series with every timestamp moved by d
+func (s Series) Shift(d time.Duration) Series {
+	times := make([]time.Time, len(s.Times))
+	for i, t := range s.Times {
+		times[i] = t.Add(d)
+	}
+
+	return Series{Times: times, Values: s.Values}
+}
+
+// ResampleMethod determines how the values of an interval are combined when resampling
+type ResampleMethod int
+
+const (
+	// ResampleLast takes the last value of the interval
+	ResampleLast ResampleMethod = iota
+	// ResampleMean takes the simple average of the values of the interval
+	ResampleMean
+)
+
+// Resample combines the values of the series into intervals of d
+// Each resampled value is timestamped with the end of its interval, the time at which it is known.
+// Intervals are aligned as with TimeBarAggregator and intervals without values are omitted.
+// Returns ErrInvalidBarSize if d <= 0
+func (s Series) Resample(d time.Duration, method ResampleMethod) (Series, error) {
+	bars, err := s.ResampleOHLC(d)
+	if err != nil {
+		return Series{}, err
+	}
+
+	var res Series
+	for _, b := range bars {
+		v := b.Close
+		if method == ResampleMean {
+			v = SimpleAvg64(s.Between(b.Start, b.End).Values)
+		}
+
+		res.Times = append(res.Times, b.End)
+		res.Values = append(res.Values, v)
+	}
+
+	return res, nil
+}
+
+// ResampleOHLC combines the values of the series into OHLC bars of interval d
+// Each value counts as a tick of size 1, so the bar volume is the number of values in the interval.
+// Returns ErrInvalidBarSize if d <= 0
+func (s Series) ResampleOHLC(d time.Duration) ([]Bar, error) {
+	return TimeBars(SeriesTicks(s.Values, s.Times), d)
+}
+
+// Apply runs a series function, such as an indicator, on the values and keeps the timestamps
+// f must return one value per input value, e.g. EwmaSeries64.
+func (s Series) Apply(f func(xs []float64) []float64) Series {
+	return Series{Times: s.Times, Values: f(s.Values)}
+}
+
+// ApplyBand runs a band function, such as a Bollinger Band, on the values and keeps the timestamps
+// f must return one Bound64 per input value, e.g. StaticBollingerSMA64.
+func (s Series) ApplyBand(f func(xs []float64) []Bound64) BandSeries {
+	return BandSeries{Times: s.Times, Bounds: f(s.Values)}
+}
+
+// Ewma computes EwmaSeries64 of the series values, see EwmaSeries64
+func (s Series) Ewma(y float64, lb int) Series {
+	return Series{Times: s.Times, Values: EwmaSeries64(s.Values, y, lb)}
+}
+
+// TimeEwma computes the time aware EWMA of the series using its timestamps, see TimeEwmaSeries64
+func (s Series) TimeEwma(halfLife time.Duration) Series {
+	return Series{Times: s.Times, Values: TimeEwmaSeries64(s.Values, s.Times, halfLife)}
+}
+
+// BollingerSMA computes StaticBollingerSMA64 of the series values, see StaticBollingerSMA64
+func (s Series) BollingerSMA(lb int, a float64) BandSeries {
+	return BandSeries{Times: s.Times, Bounds: StaticBollingerSMA64(s.Values, lb, a)}
+}
+
+// BollingerEMA computes StaticBollingerEMA64 of the series values, see StaticBollingerEMA64
+func (s Series) BollingerEMA(lb int, y float64, a float64) BandSeries {
+	return BandSeries{Times: s.Times, Bounds: StaticBollingerEMA64(s.Values, lb, y, a)}
+}
+
+// BollingerTimeEMA computes the time aware Bollinger Band of the series using its timestamps, see StaticBollingerTimeEMA64
+func (s Series) BollingerTimeEMA(halfLife time.Duration, a float64) BandSeries {
+	return BandSeries{Times: s.Times, Bounds: StaticBollingerTimeEMA64(s.Values, s.Times, halfLife, a)}
+}
+
+// LinRegChannel computes StaticLinRegChannel64 of the series values, see StaticLinRegChannel64
+func (s Series) LinRegChannel(lb int, a float64) BandSeries {
+	return BandSeries{Times: s.Times, Bounds: StaticLinRegChannel64(s.Values, lb, a)}
+}
@@ -0,0 +1,169 @@
+package technical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// secs returns the timestamps at the given second offsets from mockSessionOpen
+func secs(offsets ...int) []time.Time {
+	times := make([]time.Time, len(offsets))
+	for i, o := range offsets {
+		times[i] = mockSessionOpen.Add(time.Duration(o) * time.Second)
+	}
+
+	return times
+}
+
+func TestNewSeries(t *testing.T) {
+	_, err := NewSeries(secs(0, 1), []float64{1})
+	assert.Equal(t, ErrLengthMismatch, err)
+
+	_, err = NewSeries(secs(1, 0), []float64{1, 2})
+	assert.Equal(t, ErrUnsortedSeries, err)
+
+	s, err := NewSeries(secs(0, 0, 1), []float64{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, s.Len())
+}
+
+func TestSeriesAsOf(t *testing.T) {
+	s, _ := NewSeries(secs(10, 20, 30), []float64{1, 2, 3})
+
+	_, ok := s.AsOf(mockSessionOpen.Add(5 * time.Second))
+	assert.False(t, ok)
+
+	v, ok := s.AsOf(mockSessionOpen.Add(20 * time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, v)
+
+	v, ok = s.AsOf(mockSessionOpen.Add(29 * time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, v)
+
+	v, ok = s.AsOf(mockSessionOpen.Add(time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, 3.0, v)
+}
+
+func TestSeriesBetween(t *testing.T) {
+	s, _ := NewSeries(secs(10, 20, 30), []float64{1, 2, 3})
+
+	b := s.Between(mockSessionOpen.Add(15*time.Second), mockSessionOpen.Add(30*time.Second))
+	assert.Equal(t, []float64{2}, b.Values)
+	assert.Equal(t, secs(20), b.Times)
+
+	b = s.Between(mockSessionOpen.Add(time.Hour), mockSessionOpen)
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestSeriesAlignAsOf(t *testing.T) {
+	s, _ := NewSeries(secs(0, 10, 20, 30), []float64{1, 2, 3, 4})
+	other, _ := NewSeries(secs(5, 20, 25), []float64{10, 20, 30})
+
+	left, right := s.AlignAsOf(other)
+	assert.Equal(t, secs(10, 20, 30), left.Times)
+	assert.Equal(t, left.Times, right.Times)
+	assert.Equal(t, []float64{2, 3, 4}, left.Values)
+
+	// value at the same timestamp is used, future values are not
+	assert.Equal(t, []float64{10, 20, 30}, right.Values)
+}
+
+func TestSeriesLag(t *testing.T) {
+	s, _ := NewSeries(secs(0, 1, 2, 3), []float64{1, 2, 3, 4})
+
+	assert.Equal(t, s, s.Lag(0))
+
+	l := s.Lag(1)
+	assert.Equal(t, secs(1, 2, 3), l.Times)
+	assert.Equal(t, []float64{1, 2, 3}, l.Values)
+
+	l = s.Lag(-2)
+	assert.Equal(t, secs(0, 1), l.Times)
+	assert.Equal(t, []float64{3, 4}, l.Values)
+
+	assert.Equal(t, 0, s.Lag(4).Len())
+	assert.Equal(t, 0, s.Lag(-5).Len())
+}
+
+func TestSeriesShift(t *testing.T) {
+	s, _ := NewSeries(secs(0, 1), []float64{1, 2})
+
+	sh := s.Shift(time.Minute)
+	assert.Equal(t, secs(60, 61), sh.Times)
+	assert.Equal(t, s.Values, sh.Values)
+	assert.Equal(t, secs(0, 1), s.Times)
+}
+
+func TestSeriesResample(t *testing.T) {
+	s, _ := NewSeries(secs(0, 30, 59, 60, 150), []float64{1, 2, 6, 4, 5})
+
+	_, err := s.Resample(0, ResampleLast)
+	assert.Equal(t, ErrInvalidBarSize, err)
+
+	r, err := s.Resample(time.Minute, ResampleLast)
+	assert.NoError(t, err)
+	assert.Equal(t, secs(60, 120, 180), r.Times)
+	assert.Equal(t, []float64{6, 4, 5}, r.Values)
+
+	r, err = s.Resample(time.Minute, ResampleMean)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 4, 5}, r.Values)
+
+	bars, err := s.ResampleOHLC(time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, bars, 3)
+	assert.Equal(t, Bar{
+		Start:  mockSessionOpen,
+		End:    mockSessionOpen.Add(time.Minute),
+		Open:   1,
+		High:   6,
+		Low:    1,
+		Close:  6,
+		Volume: 3,
+		Ticks:  3,
+	}, bars[0])
+
+	// full session of per second values to minutes
+	testseries := readMockSeries64("./mock/test_series.txt")
+	s, _ = NewSeries(mockSessionTimes(len(testseries)), testseries)
+	r, err = s.Resample(time.Minute, ResampleLast)
+	assert.NoError(t, err)
+	assert.Equal(t, 390, r.Len())
+	assert.Equal(t, testseries[len(testseries)-1], r.Values[r.Len()-1])
+}
+
+func TestSeriesIndicators(t *testing.T) {
+	testseries := readMockSeries64("./mock/test_series.txt")
+	times := mockSessionTimes(len(testseries))
+	s, _ := NewSeries(times, testseries)
+
+	ewma := s.Ewma(0.0, 1200)
+	assert.Equal(t, times, ewma.Times)
+	assert.Equal(t, 39.9488124468039, ewma.Values[ewma.Len()-1])
+
+	applied := s.Apply(func(xs []float64) []float64 { return EwmaSeries64(xs, 0.0, 1200) })
+	assert.Equal(t, ewma, applied)
+
+	band := s.BollingerEMA(1200, 0.0, 2)
+	assert.Equal(t, times, band.Times)
+	assert.Equal(t, 39.9488124468039, band.Bounds[len(band.Bounds)-1].Midpoint)
+
+	band = s.BollingerSMA(5, 2)
+	assert.Equal(t, StaticBollingerSMA64(testseries, 5, 2), band.Bounds)
+
+	applyBand := s.ApplyBand(func(xs []float64) []Bound64 { return StaticBollingerSMA64(xs, 5, 2) })
+	assert.Equal(t, band, applyBand)
+
+	band = s.LinRegChannel(60, 2)
+	assert.Equal(t, StaticLinRegChannel64(testseries, 60, 2), band.Bounds)
+
+	tewma := s.TimeEwma(time.Minute)
+	assert.Equal(t, TimeEwmaSeries64(testseries, times, time.Minute), tewma.Values)
+
+	band = s.BollingerTimeEMA(time.Minute, 2)
+	assert.Equal(t, StaticBollingerTimeEMA64(testseries, times, time.Minute, 2), band.Bounds)
+}
@@ -0,0 +1,323 @@
+package technical
+
+import (
+	"math"
+)
+
+// Smoothing is the lambda (y) smoothing factor of an Exponentially Weighted Moving Average
+// i.e. the weight given to the current value, 0 < y < 1.
+//
+// The zero Smoothing uses the default formulaic calculation 2 / (lb + 1) of the lookback,
+// the same as passing y = 0.0 to EwmaSeries64.
+// Use the Smoothing constructors to convert from other common parameterizations.
+type Smoothing float64
+
+// SmoothingAlpha creates a Smoothing directly from the smoothing factor y (alpha)
+func SmoothingAlpha(y float64) (Smoothing, error) {
+	s := Smoothing(y)
+
+	if y == 0.0 {
+		// the zero Smoothing is the default, an explicit alpha must be in range
+		return 0, ErrInvalidSmoothing
+	}
+
+	return s, s.Validate()
+}
+
+// SmoothingSpan creates a Smoothing from a span of n values: y = 2 / (span + 1)
+// This is the default smoothing of a lookback of span values. Constraint: span > 1
+func SmoothingSpan(span float64) (Smoothing, error) {
+	if !(span > 1.0) {
+		return 0, ErrInvalidSmoothing
+	}
+
+	return checkedSmoothing(2.0 / (span + 1.0))
+}
+
+// SmoothingCenterOfMass creates a Smoothing from a center of mass: y = 1 / (1 + com)
+// Constraint: com > 0
+func SmoothingCenterOfMass(com float64) (Smoothing, error) {
+	if !(com > 0.0) {
+		return 0, ErrInvalidSmoothing
+	}
+
+	return checkedSmoothing(1.0 / (1.0 + com))
+}
+
+// SmoothingHalfLife creates a Smoothing from a half life of h values: y = 1 - exp(ln(0.5) / h)
+// i.e. the weight of a value halves after h more values. Constraint: h > 0
+func SmoothingHalfLife(h float64) (Smoothing, error) {
+	if !(h > 0.0) {
+		return 0, ErrInvalidSmoothing
+	}
+
+	return checkedSmoothing(1.0 - math.Exp(-math.Ln2/h))
+}
+
+// SmoothingWilder creates a Smoothing from a Wilder period of n values: y = 1 / n
+// This is the smoothing used by RollingATR64. Constraint: n > 1
+func SmoothingWilder(n int) (Smoothing, error) {
+	if n <= 1 {
+		return 0, ErrInvalidSmoothing
+	}
+
+	return checkedSmoothing(1.0 / float64(n))
+}
+
+// checkedSmoothing converts y to a Smoothing, validating the result of a conversion
+// as very large parameters can round y to 0 or 1
+func checkedSmoothing(y float64) (Smoothing, error) {
+	if y == 0.0 {
+		return 0, ErrInvalidSmoothing
+	}
+
+	s := Smoothing(y)
+
+	return s, s.Validate()
+}
+
+// Validate checks the smoothing factor satisfies 0 < y < 1
+// The zero Smoothing is valid and uses the default smoothing of the lookback
+func (s Smoothing) Validate() error {
+	if s == 0.0 {
+		return nil
+	}
+
+	if !(s > 0.0 && s < 1.0) {
+		return ErrInvalidSmoothing
+	}
+
+	return nil
+}
+
+// Lambda returns the smoothing factor y for a lookback lb
+// If s is the zero Smoothing the default 2 / (lb + 1) is returned
+func (s Smoothing) Lambda(lb int) float64 {
+	if s == 0.0 {
+		return 2.0 / float64(lb+1)
+	}
+
+	return float64(s)
+}
+
+// Lambda32 is 32 bit version of Lambda
+func (s Smoothing) Lambda32(lb int) float32 {
+	if s == 0.0 {
+		return 2.0 / float32(lb+1)
+	}
+
+	return float32(s)
+}
+
+// EwmaSeriesSmoothed64 is EwmaSeriesChecked64 using a Smoothing
+// Returns ErrEmptySeries, ErrInvalidLookback if lb <= 0 or lb > len(series) and ErrInvalidSmoothing if s is not valid
+func EwmaSeriesSmoothed64(series []float64, s Smoothing, lb int) ([]float64, error) {
+	return EwmaSeriesChecked64(series, float64(s), lb)
+}
+
+// EwmaSeriesSmoothed32 is 32 bit version of EwmaSeriesSmoothed64
+func EwmaSeriesSmoothed32(series []float32, s Smoothing, lb int) ([]float32, error) {
+	if err := s.Validate(); err != nil { // validate before narrowing to 32 bit
+		return nil, err
+	}
+
+	return EwmaSeriesChecked32(series, float32(s), lb)
+}
+
+// StaticBollingerEMASmoothed64 is StaticBollingerEMAChecked64 using a Smoothing
+// Returns ErrEmptySeries, ErrInvalidLookback if lb <= 0 or lb > len(series),
+// ErrInvalidMultiplier if a < 0 and ErrInvalidSmoothing if s is not valid
+func StaticBollingerEMASmoothed64(series []float64, lb int, s Smoothing, a float64) ([]Bound64, error) {
+	return StaticBollingerEMAChecked64(series, lb, float64(s), a)
+}
+
+// StaticBollingerEMASmoothed32 is 32 bit version of StaticBollingerEMASmoothed64
+func StaticBollingerEMASmoothed32(series []float32, lb int, s Smoothing, a float32) ([]Bound32, error) {
+	if err := s.Validate(); err != nil { // validate before narrowing to 32 bit
+		return nil, err
+	}
+
+	return StaticBollingerEMAChecked32(series, lb, float32(s), a)
+}
+
+// RollingEMASmoothed64 is RollingEMA64 using a Smoothing
+// The zero Smoothing uses the default smoothing of the lookback lb. s is not validated on this hot path.
+func RollingEMASmoothed64(v float64, last float64, s Smoothing, lb int) float64 {
+	return RollingEMA64(v, last, s.Lambda(lb))
+}
+
+// RollingEMASmoothed32 is 32 bit version of RollingEMASmoothed64
+func RollingEMASmoothed32(v float32, last float32, s Smoothing, lb int) float32 {
+	return RollingEMA32(v, last, s.Lambda32(lb))
+}
+
+// RollingBollingerEMASmoothed64 is RollingBollingerEMA64 using a Smoothing
+// The zero Smoothing uses the default smoothing of a lookback of the length of the period.
+// s is not validated on this hot path.
+func RollingBollingerEMASmoothed64(period []float64, s Smoothing, v float64, last float64, a float64) Bound64 {
+	return RollingBollingerEMA64(period, s.Lambda(len(period)), v, last, a)
+}
+
+// RollingBollingerEMASmoothed32 is 32 bit version of RollingBollingerEMASmoothed64
+func RollingBollingerEMASmoothed32(period []float32, s Smoothing, v float32, last float32, a float32) Bound32 {
+	return RollingBollingerEMA32(period, s.Lambda32(len(period)), v, last, a)
+}
+
+// EMAStream64 computes an Exponentially Weighted Moving Average over a stream of values
+// The first lb values warm up the stream and the first EWMA is their simple average, as EwmaSeries64.
+type EMAStream64 struct {
+	lb  int
+	y   float64
+	sum float64
+	ema float64
+	n   int
+}
+
+// NewEMAStream64 creates an EMAStream64 with lookback lb and smoothing s
+// Returns ErrInvalidLookback if lb <= 0 and ErrInvalidSmoothing if s is not valid
+func NewEMAStream64(lb int, s Smoothing) (*EMAStream64, error) {
+	if lb <= 0 {
+		return nil, ErrInvalidLookback
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &EMAStream64{lb: lb, y: s.Lambda(lb)}, nil
+}
+
+// Update adds the next value of the stream
+func (s *EMAStream64) Update(v float64) {
+	s.n++
+
+	switch {
+	case s.n < s.lb:
+		s.sum += v
+	case s.n == s.lb: // first is simple average
+		s.sum += v
+		s.ema = s.sum / float64(s.lb)
+	default:
+		s.ema = RollingEMA64(v, s.ema, s.y)
+	}
+}
+
+// Value returns the current EWMA, 0.0 until the stream is ready
+func (s *EMAStream64) Value() float64 {
+	return s.ema
+}
+
+// Ready reports whether a full lookback of values has been seen
+func (s *EMAStream64) Ready() bool {
+	return s.n >= s.lb
+}
+
+// WarmupPeriod returns the number of values before the stream is ready
+func (s *EMAStream64) WarmupPeriod() int {
+	return s.lb
+}
+
+// Reset clears the stream
+func (s *EMAStream64) Reset() {
+	s.sum, s.ema, s.n = 0.0, 0.0, 0
+}
+
+// AdjustGap shifts the stream state by gap as if every value seen so far had been gap higher
+// Used at session boundaries so the EWMA does not trend towards the price across an overnight gap.
+func (s *EMAStream64) AdjustGap(gap float64) {
+	if s.n < s.lb {
+		s.sum += gap * float64(s.n)
+	}
+
+	s.ema += gap
+}
+
+// Clone returns an independent copy of the stream
+func (s *EMAStream64) Clone() *EMAStream64 {
+	c := *s
+	return &c
+}
+
+// BollingerEMAStream64 computes an EWMA midpoint Bollinger Bound over a stream of values
+// Equivalent to StaticBollingerEMA64 computed one value at a time.
+type BollingerEMAStream64 struct {
+	ema EMAStream64
+	win window64
+	a   float64
+}
+
+// NewBollingerEMAStream64 creates a BollingerEMAStream64
+// Returns ErrInvalidLookback if lb <= 0, ErrInvalidSmoothing if s is not valid and ErrInvalidMultiplier if a < 0
+//
+// Parameters:
+//
+//	lb: lookback to derive a period
+//	s: smoothing of the EWMA midpoint
+//	a (alpha): multiplier on the standard deviation of the period
+func NewBollingerEMAStream64(lb int, s Smoothing, a float64) (*BollingerEMAStream64, error) {
+	ema, err := NewEMAStream64(lb, s)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkMultiplier(a); err != nil {
+		return nil, err
+	}
+
+	return &BollingerEMAStream64{ema: *ema, win: newWindow64(ema.lb), a: a}, nil
+}
+
+// Update adds the next value of the stream
+func (s *BollingerEMAStream64) Update(v float64) {
+	s.ema.Update(v)
+	s.win.push(v)
+}
+
+// Bound returns the current Bollinger Bound, empty until the stream is ready
+func (s *BollingerEMAStream64) Bound() Bound64 {
+	var b Bound64
+
+	if !s.Ready() {
+		return b
+	}
+
+	leg := s.win.stdDev() * s.a
+
+	b.Midpoint = s.ema.Value()
+	b.Lower = b.Midpoint - leg
+	b.Upper = b.Midpoint + leg
+
+	return b
+}
+
+// Value returns the midpoint of the current Bollinger Bound
+func (s *BollingerEMAStream64) Value() float64 {
+	return s.ema.Value()
+}
+
+// Ready reports whether a full lookback of values has been seen
+func (s *BollingerEMAStream64) Ready() bool {
+	return s.ema.Ready()
+}
+
+// WarmupPeriod returns the number of values before the stream is ready
+func (s *BollingerEMAStream64) WarmupPeriod() int {
+	return s.ema.lb
+}
+
+// Reset clears the stream
+func (s *BollingerEMAStream64) Reset() {
+	s.ema.Reset()
+	s.win.reset()
+}
+
+// AdjustGap shifts the midpoint and the window by gap, leaving the width of the band unchanged
+func (s *BollingerEMAStream64) AdjustGap(gap float64) {
+	s.ema.AdjustGap(gap)
+	s.win.shift(gap)
+}
+
+// Clone returns an independent copy of the stream
+func (s *BollingerEMAStream64) Clone() *BollingerEMAStream64 {
+	return &BollingerEMAStream64{ema: s.ema, win: s.win.clone(), a: s.a}
+}
@@ -0,0 +1,191 @@
+package technical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSmoothingConstructors(t *testing.T) {
+	s, err := SmoothingAlpha(0.25)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.25, s.Lambda(10))
+
+	for _, y := range []float64{0, 1, -0.5, 1.5, math.NaN()} {
+		_, err = SmoothingAlpha(y)
+		assert.Equal(t, ErrInvalidSmoothing, err)
+	}
+
+	s, err = SmoothingSpan(9)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.2, s.Lambda(100))
+
+	_, err = SmoothingSpan(1)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	s, err = SmoothingCenterOfMass(3)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.25, s.Lambda(100))
+
+	_, err = SmoothingCenterOfMass(0)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	s, err = SmoothingHalfLife(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, s.Lambda(100))
+
+	// weight of a value halves after h values
+	s, err = SmoothingHalfLife(10)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5, math.Pow(1-s.Lambda(0), 10), 1e-12)
+
+	_, err = SmoothingHalfLife(-1)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	_, err = SmoothingHalfLife(math.Inf(1))
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	s, err = SmoothingWilder(5)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.2, s.Lambda(100))
+	assert.Equal(t, RollingATR64(8.0, 3.0, 5), RollingEMA64(3.0, 8.0, s.Lambda(5)))
+
+	_, err = SmoothingWilder(1)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+}
+
+func TestSmoothingValidate(t *testing.T) {
+	assert.NoError(t, Smoothing(0).Validate())
+	assert.NoError(t, Smoothing(0.5).Validate())
+	assert.Equal(t, ErrInvalidSmoothing, Smoothing(1).Validate())
+	assert.Equal(t, ErrInvalidSmoothing, Smoothing(-0.1).Validate())
+	assert.Equal(t, ErrInvalidSmoothing, Smoothing(math.NaN()).Validate())
+}
+
+func TestSmoothingLambda(t *testing.T) {
+	// zero is default smoothing
+	assert.Equal(t, 2.0/11.0, Smoothing(0).Lambda(10))
+	assert.Equal(t, float32(2.0/11.0), Smoothing(0).Lambda32(10))
+
+	assert.Equal(t, 0.3, Smoothing(0.3).Lambda(10))
+	assert.Equal(t, float32(0.3), Smoothing(0.3).Lambda32(10))
+}
+
+func TestRollingSmoothed(t *testing.T) {
+	// zero is the default smoothing of the lookback, 2 / (3 + 1)
+	assert.Equal(t, RollingEMA64(10, 6, 0.5), RollingEMASmoothed64(10, 6, 0, 3))
+	assert.Equal(t, RollingEMA32(10, 6, 0.25), RollingEMASmoothed32(10, 6, Smoothing(0.25), 3))
+
+	h, _ := SmoothingHalfLife(1)
+	assert.Equal(t, 8.0, RollingEMASmoothed64(10, 6, h, 3))
+
+	period := []float64{4, 6, 8}
+	assert.Equal(t, RollingBollingerEMA64(period, 0.5, 10, 6, 2), RollingBollingerEMASmoothed64(period, 0, 10, 6, 2))
+	assert.Equal(t, RollingBollingerEMA32(to32(period), 0.25, 10, 6, 2), RollingBollingerEMASmoothed32(to32(period), 0.25, 10, 6, 2))
+}
+
+func TestEwmaSeriesSmoothed64(t *testing.T) {
+	_, err := EwmaSeriesSmoothed64([]float64{1, 2}, Smoothing(2), 2)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	testseries := readMockSeries64("./mock/test_series.txt")
+
+	// default smoothing
+	ewmas, err := EwmaSeriesSmoothed64(testseries, 0, 1200)
+	assert.NoError(t, err)
+	assert.Equal(t, 39.9488124468039, ewmas[len(ewmas)-1])
+
+	// span of the lookback is the default smoothing
+	s, _ := SmoothingSpan(1200)
+	ewmas, err = EwmaSeriesSmoothed64(testseries, s, 1200)
+	assert.NoError(t, err)
+	assert.Equal(t, 39.9488124468039, ewmas[len(ewmas)-1])
+
+	// list < lookback
+	_, err = EwmaSeriesSmoothed64([]float64{1, 9}, 0, 10)
+	assert.Equal(t, ErrInvalidLookback, err)
+}
+
+func TestEwmaSeriesSmoothed32(t *testing.T) {
+	_, err := EwmaSeriesSmoothed32([]float32{1, 2}, Smoothing(2), 2)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	testseries := readMockSeries32("./mock/test_series.txt")
+
+	ewmas, err := EwmaSeriesSmoothed32(testseries, 0, 1200)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(39.948578), ewmas[len(ewmas)-1])
+}
+
+func TestStaticBollingerEMASmoothed64(t *testing.T) {
+	_, err := StaticBollingerEMASmoothed64([]float64{1, 2}, 2, Smoothing(-1), 2)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	testseries := readMockSeries64("./mock/test_series.txt")
+
+	band, err := StaticBollingerEMASmoothed64(testseries, 1200, 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticBollingerEMA64(testseries, 1200, 0, 2), band)
+}
+
+func TestStaticBollingerEMASmoothed32(t *testing.T) {
+	_, err := StaticBollingerEMASmoothed32([]float32{1, 2}, 2, Smoothing(-1), 2)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	testseries := readMockSeries32("./mock/test_series.txt")
+
+	band, err := StaticBollingerEMASmoothed32(testseries, 1200, 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(39.948578), band[len(band)-1].Midpoint)
+}
+
+func TestEMAStream64(t *testing.T) {
+	_, err := NewEMAStream64(10, Smoothing(1))
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	_, err = NewEMAStream64(0, 0)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	testseries := readMockSeries64("./mock/test_series.txt")
+
+	s, err := NewEMAStream64(1200, 0)
+	assert.NoError(t, err)
+
+	ewmas := EwmaSeries64(testseries, 0.0, 1200)
+	for i, v := range testseries {
+		s.Update(v)
+		assert.Equal(t, i >= 1199, s.Ready())
+		assert.Equal(t, ewmas[i], s.Value())
+	}
+
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, 0.0, s.Value())
+}
+
+func TestBollingerEMAStream64(t *testing.T) {
+	_, err := NewBollingerEMAStream64(10, Smoothing(1), 2)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	_, err = NewBollingerEMAStream64(10, 0, -1)
+	assert.Equal(t, ErrInvalidMultiplier, err)
+
+	testseries := readMockSeries64("./mock/test_series.txt")
+
+	s, err := NewBollingerEMAStream64(1200, 0, 2)
+	assert.NoError(t, err)
+
+	band := StaticBollingerEMA64(testseries, 1200, 0.0, 2)
+	for i, v := range testseries {
+		s.Update(v)
+		b := s.Bound()
+		assert.Equal(t, band[i].Midpoint, b.Midpoint)
+		assert.InDelta(t, band[i].Lower, b.Lower, 1e-9)
+		assert.InDelta(t, band[i].Upper, b.Upper, 1e-9)
+	}
+
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, Bound64{}, s.Bound())
+}
@@ -33,6 +33,7 @@ func RoundDown32(x float32, n int) float32 {
 }
 
 // SimpleAvg64 computes the simple average of a given list of values
+// Returns 0.0 for an empty list. NaN and Inf values propagate. See MissingPolicy.
 func SimpleAvg64(xs []float64) float64 {
 	if len(xs) == 0 {
 		return 0.0
@@ -63,6 +64,7 @@ func SimpleAvg32(xs []float32) float32 {
 }
 
 // Variance64 computes the population variance of a given iist of values
+// Returns 0.0 for an empty list. NaN and Inf values propagate. See MissingPolicy.
 func Variance64(xs []float64) float64 {
 	if len(xs) == 0 {
 		return 0.0
@@ -99,18 +101,13 @@ func Variance32(xs []float32) float32 {
 }
 
 // StdDev64 computes the standard deviation of a given list of values
+// Returns 0.0 for an empty list. NaN values propagate, as with Variance64. See MissingPolicy.
 func StdDev64(xs []float64) float64 {
 	if len(xs) == 0 {
 		return 0.0
 	}
 
-	res := math.Sqrt(Variance64(xs))
-
-	if math.IsNaN(res) {
-		return 0.0
-	}
-
-	return res
+	return math.Sqrt(Variance64(xs))
 }
 
 // StdDev32 is 32 bit version of StdDev64
@@ -119,13 +116,7 @@ func StdDev32(xs []float32) float32 {
 		return 0.0
 	}
 
-	res := math.Sqrt(float64(Variance32(xs)))
-
-	if math.IsNaN(res) {
-		return float32(0.0)
-	}
-
-	return float32(res)
+	return float32(math.Sqrt(float64(Variance32(xs))))
 }
 
 // EwmaSeries computes a list of Exponentially Weighted Moving Averages for a given list of values
@@ -138,9 +129,12 @@ func StdDev32(xs []float32) float32 {
 //		lb (lookback): size of the period to compute avg. Must be < len of data series
 //
 // Constraint: 0 < y < 1
+// See Smoothing and EwmaSeriesSmoothed64 to use other parameterizations of y
 //
+// Returns nil if the series is empty or lb <= 0. If lb > len(series) the full series is used as the lookback.
+// See EwmaSeriesChecked64 to validate the parameters instead.
 func EwmaSeries64(series []float64, y float64, lb int) []float64 {
-	if len(series) == 0 {
+	if len(series) == 0 || lb <= 0 {
 		return nil
 	}
 
@@ -149,9 +143,7 @@ func EwmaSeries64(series []float64, y float64, lb int) []float64 {
 		lb = size
 	}
 
-	if y == 0.0 { // use default smoothing
-		y = 2.0 / float64(lb+1)
-	}
+	y = Smoothing(y).Lambda(lb) // 0.0 uses default smoothing
 
 	var lastEma float64
 	ewmas := make([]float64, size)
@@ -176,7 +168,7 @@ func EwmaSeries64(series []float64, y float64, lb int) []float64 {
 
 // EwmaSeries32 is 32 bit version of EwmaSeries64
 func EwmaSeries32(series []float32, y float32, lb int) []float32 {
-	if series == nil || len(series) == 0 {
+	if series == nil || len(series) == 0 || lb <= 0 {
 		return nil
 	}
 
@@ -185,9 +177,7 @@ func EwmaSeries32(series []float32, y float32, lb int) []float32 {
 		lb = size
 	}
 
-	if y == float32(0.0) { // use default smoothing
-		y = 2.0 / float32(lb+1)
-	}
+	y = Smoothing(y).Lambda32(lb) // 0.0 uses default smoothing
 
 	var lastEma float32
 	ewmas := make([]float32, size)
@@ -210,14 +200,43 @@ func EwmaSeries32(series []float32, y float32, lb int) []float32 {
 	return ewmas
 }
 
+// EwmaSeriesChecked64 is EwmaSeries64 returning an error for invalid parameters
+// Returns ErrEmptySeries, ErrInvalidLookback if lb <= 0 or lb > len(series)
+// and ErrInvalidSmoothing if y is not 0.0 (default) and does not satisfy 0 < y < 1
+func EwmaSeriesChecked64(series []float64, y float64, lb int) ([]float64, error) {
+	if err := checkSeries(len(series), lb); err != nil {
+		return nil, err
+	}
+
+	if err := Smoothing(y).Validate(); err != nil {
+		return nil, err
+	}
+
+	return EwmaSeries64(series, y, lb), nil
+}
+
+// EwmaSeriesChecked32 is 32 bit version of EwmaSeriesChecked64
+func EwmaSeriesChecked32(series []float32, y float32, lb int) ([]float32, error) {
+	if err := checkSeries(len(series), lb); err != nil {
+		return nil, err
+	}
+
+	if err := Smoothing(y).Validate(); err != nil {
+		return nil, err
+	}
+
+	return EwmaSeries32(series, y, lb), nil
+}
+
 // RollingEwma computes the next EWMA value in a series
 // Assumes last EWMA value given is correct
 //
 // Parameters:
 //		v: the current value in the series
 //		last: the last EWMA value of the series
-//		y (lambda): smoothing factor
+//		y (lambda): raw smoothing factor, see RollingEMASmoothed64 for a Smoothing
 // Constraint: 0 < y < 1
+// y is not validated on this hot path, see Smoothing.Validate
 func RollingEMA64(v float64, last float64, y float64) float64 {
 	return v*y + (1-y)*last
 }
@@ -280,6 +280,51 @@ func TestEwmaSeries32(t *testing.T) {
 
 }
 
+func TestEwmaSeriesChecked64(t *testing.T) {
+	_, err := EwmaSeriesChecked64(nil, 0.0, 10)
+	assert.Equal(t, ErrEmptySeries, err)
+
+	l := []float64{1.0, 9.0}
+
+	// lookback out of range
+	_, err = EwmaSeriesChecked64(l, 0.0, 0)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	_, err = EwmaSeriesChecked64(l, 0.0, 10)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	// smoothing out of range
+	_, err = EwmaSeriesChecked64(l, 1.5, 2)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	ewmas, err := EwmaSeriesChecked64(l, 0.0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 5}, ewmas)
+
+	// unchecked version does not panic or compute on an invalid lookback
+	assert.Nil(t, EwmaSeries64(l, 0.0, 0))
+	assert.Nil(t, EwmaSeries64(l, 0.0, -1))
+}
+
+func TestEwmaSeriesChecked32(t *testing.T) {
+	_, err := EwmaSeriesChecked32(nil, 0.0, 10)
+	assert.Equal(t, ErrEmptySeries, err)
+
+	l := []float32{1.0, 9.0}
+
+	_, err = EwmaSeriesChecked32(l, 0.0, -1)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	_, err = EwmaSeriesChecked32(l, -0.5, 2)
+	assert.Equal(t, ErrInvalidSmoothing, err)
+
+	ewmas, err := EwmaSeriesChecked32(l, 0.0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0, 5}, ewmas)
+
+	assert.Nil(t, EwmaSeries32(l, 0.0, 0))
+}
+
 func TestRollingEMA64(t *testing.T) {
 	ewma := RollingEMA64(10, 5, 0.5)
 	assert.Equal(t, 7.5, ewma)
@@ -0,0 +1,288 @@
+package technical
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+/*
+* A TickSize is the price increment of an instrument, e.g. 0.01 for equities, 0.25 for index futures,
+* 1/32 for bond futures or 0.0001 for sub-penny equities.
+*
+* The increment is stored exactly as a fraction num / den of integers, and prices are rounded by counting whole ticks,
+* so a rounded price is the nearest float64 to an exact multiple of the tick and not an accumulation of float error.
+*
+* The number of ticks in a price is x * den / num in float64, and a price within a few ulps of a multiple of the tick
+* is on the grid. The snap only absorbs the float error of the price and the division, so Floor never rounds up and Ceil
+* never rounds down by more than a few ulps, at any price. The counts are exact up to 2^53 ticks, e.g. 9e13 at a tick
+* of 0.01. Beyond that a float64 cannot hold every count, NaN, infinite and larger prices return ErrInvalidPrice
+* from the tick counts and are left unchanged by the rounding.
+ */
+
+// tickSnap is the relative distance of a price from a multiple of the tick within which it is on the grid
+// It is about 4 ulps, enough for the float error of the price itself, e.g. 0.3 / 0.1 is 2.9999999999999996 ticks,
+// but a fixed number of ulps so that it does not snap prices a fraction of a tick away at large tick counts.
+const tickSnap = 1e-15
+
+// tickSnap32 is tickSnap for float32 prices, about 4 float32 ulps
+const tickSnap32 = 5e-7
+
+// maxTicks is the largest number of ticks in a price, every count up to it is an exact float64
+const maxTicks = 1 << 53
+
+// maxTickDecimals is the largest number of decimals of a tick created from a float64
+const maxTickDecimals = 12
+
+// TickSize is an exact price increment
+// The zero TickSize does not round, i.e. its rounding methods return prices unchanged.
+type TickSize struct {
+	num int64
+	den int64
+}
+
+// NewTickSize creates a TickSize from a decimal increment, e.g. 0.01, 0.25 or 0.03125 (1/32)
+// A tick of 0 creates the zero TickSize, which does not round.
+// Returns ErrInvalidTickSize if tick is negative, NaN, or has more than 12 decimals
+func NewTickSize(tick float64) (TickSize, error) {
+	if tick == 0.0 {
+		return TickSize{}, nil
+	}
+
+	if !(tick > 0.0) || math.IsInf(tick, 1) {
+		return TickSize{}, ErrInvalidTickSize
+	}
+
+	den := int64(1)
+	for i := 0; i <= maxTickDecimals; i++ {
+		scaled := tick * float64(den)
+		if num := math.Round(scaled); num >= 1.0 && math.Abs(scaled-num) <= tickSnap*scaled {
+			return NewTickSizeFraction(int64(num), den)
+		}
+
+		den *= 10
+	}
+
+	return TickSize{}, errorf(ErrInvalidTickSize, "%v has more than %d decimals", tick, maxTickDecimals)
+}
+
+// NewTickSizeFraction creates a TickSize of num / den, e.g. 1 / 32
+// Returns ErrInvalidTickSize if num or den is not positive
+func NewTickSizeFraction(num int64, den int64) (TickSize, error) {
+	if num <= 0 || den <= 0 {
+		return TickSize{}, ErrInvalidTickSize
+	}
+
+	g := gcd(num, den)
+
+	return TickSize{num: num / g, den: den / g}, nil
+}
+
+// gcd returns the greatest common divisor of positive a and b
+func gcd(a int64, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
+
+// IsZero reports whether t is the zero TickSize, which does not round
+func (t TickSize) IsZero() bool {
+	return t.den == 0
+}
+
+// Float64 returns the tick size, 0.0 for the zero TickSize
+func (t TickSize) Float64() float64 {
+	if t.IsZero() {
+		return 0.0
+	}
+
+	return float64(t.num) / float64(t.den)
+}
+
+// String returns the tick size as a decimal or a fraction, e.g. 0.25 or 1/3
+func (t TickSize) String() string {
+	if t.IsZero() {
+		return "0"
+	}
+
+	// fractions with only factors of 2 and 5 in the denominator are exact decimals
+	d := t.den
+	for d%2 == 0 {
+		d /= 2
+	}
+
+	for d%5 == 0 {
+		d /= 5
+	}
+
+	if d != 1 {
+		return fmt.Sprintf("%d/%d", t.num, t.den)
+	}
+
+	return fmt.Sprint(t.Float64())
+}
+
+// ticks returns the number of ticks q in x and the integer n nearest to it, with on true if q is within tol of n relative to q
+// Returns ErrInvalidPrice if q is NaN, infinite or beyond maxTicks.
+func (t TickSize) ticks(x float64, tol float64) (q float64, n float64, on bool, err error) {
+	q = x * float64(t.den) / float64(t.num)
+	if !(math.Abs(q) <= maxTicks) {
+		return 0.0, 0.0, false, errorf(ErrInvalidPrice, "%v in ticks of %v", x, t)
+	}
+
+	n = math.Round(q)
+
+	return q, n, math.Abs(q-n) <= tol*math.Max(1.0, math.Abs(q)), nil
+}
+
+// floorTicks rounds the number of ticks in x down, snapping to the grid within tol
+func (t TickSize) floorTicks(x float64, tol float64) (int64, error) {
+	if t.IsZero() {
+		return 0, nil
+	}
+
+	q, n, on, err := t.ticks(x, tol)
+	if err != nil || on {
+		return int64(n), err
+	}
+
+	return int64(math.Floor(q)), nil
+}
+
+// ceilTicks rounds the number of ticks in x up, snapping to the grid within tol
+func (t TickSize) ceilTicks(x float64, tol float64) (int64, error) {
+	if t.IsZero() {
+		return 0, nil
+	}
+
+	q, n, on, err := t.ticks(x, tol)
+	if err != nil || on {
+		return int64(n), err
+	}
+
+	return int64(math.Ceil(q)), nil
+}
+
+// FloorTicks returns the number of whole ticks in x rounded down, 0 for the zero TickSize
+// Returns ErrInvalidPrice if x is NaN, infinite or more than 2^53 ticks
+func (t TickSize) FloorTicks(x float64) (int64, error) {
+	return t.floorTicks(x, tickSnap)
+}
+
+// CeilTicks returns the number of whole ticks in x rounded up, 0 for the zero TickSize
+// Returns ErrInvalidPrice if x is NaN, infinite or more than 2^53 ticks
+func (t TickSize) CeilTicks(x float64) (int64, error) {
+	return t.ceilTicks(x, tickSnap)
+}
+
+// RoundTicks returns the number of whole ticks in x rounded to the nearest, halves away from zero, 0 for the zero TickSize
+// Returns ErrInvalidPrice if x is NaN, infinite or more than 2^53 ticks
+func (t TickSize) RoundTicks(x float64) (int64, error) {
+	if t.IsZero() {
+		return 0, nil
+	}
+
+	_, n, _, err := t.ticks(x, tickSnap)
+
+	return int64(n), err
+}
+
+// Price returns the price of n ticks, the nearest float64 to n * num / den, 0.0 for the zero TickSize
+func (t TickSize) Price(n int64) float64 {
+	if t.IsZero() {
+		return 0.0
+	}
+
+	// below 2^53 the product and the denominator are exact and the division rounds once
+	if lim := maxTicks / t.num; n >= -lim && n <= lim && t.den <= maxTicks {
+		return float64(n*t.num) / float64(t.den)
+	}
+
+	p, _ := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(n), big.NewInt(t.num)), big.NewInt(t.den)).Float64()
+
+	return p
+}
+
+// Floor rounds x down to a multiple of the tick size
+// NaN, infinite prices and prices of more than 2^53 ticks are returned unchanged, see FloorTicks.
+func (t TickSize) Floor(x float64) float64 {
+	n, err := t.FloorTicks(x)
+	if t.IsZero() || err != nil {
+		return x
+	}
+
+	return t.Price(n)
+}
+
+// Ceil rounds x up to a multiple of the tick size
+// NaN, infinite prices and prices of more than 2^53 ticks are returned unchanged, see CeilTicks.
+func (t TickSize) Ceil(x float64) float64 {
+	n, err := t.CeilTicks(x)
+	if t.IsZero() || err != nil {
+		return x
+	}
+
+	return t.Price(n)
+}
+
+// Round rounds x to the nearest multiple of the tick size, halves away from zero
+// NaN, infinite prices and prices of more than 2^53 ticks are returned unchanged, see RoundTicks.
+func (t TickSize) Round(x float64) float64 {
+	n, err := t.RoundTicks(x)
+	if t.IsZero() || err != nil {
+		return x
+	}
+
+	return t.Price(n)
+}
+
+// Floor32 is 32 bit version of Floor
+// Prices within the float32 precision of a multiple of the tick are on the grid, e.g. float32(10.3) with a tick of 0.01.
+func (t TickSize) Floor32(x float32) float32 {
+	if t.IsZero() {
+		return x
+	}
+
+	n, err := t.floorTicks(float64(x), tickSnap32)
+	if err != nil {
+		return x
+	}
+
+	return float32(t.Price(n))
+}
+
+// Ceil32 is 32 bit version of Ceil
+func (t TickSize) Ceil32(x float32) float32 {
+	if t.IsZero() {
+		return x
+	}
+
+	n, err := t.ceilTicks(float64(x), tickSnap32)
+	if err != nil {
+		return x
+	}
+
+	return float32(t.Price(n))
+}
+
+// Round32 is 32 bit version of Round
+func (t TickSize) Round32(x float32) float32 {
+	return float32(t.Round(float64(x)))
+}
+
+// RoundToTick rounds the bound to the tick size, the upper bound up, the lower bound down and the midpoint to the nearest
+// so the rounded bound contains the original bound, as RoundBoundToNearestCent64, up to the few ulps of the snap to the grid.
+func (b *Bound64) RoundToTick(t TickSize) {
+	b.Upper = t.Ceil(b.Upper)
+	b.Midpoint = t.Round(b.Midpoint)
+	b.Lower = t.Floor(b.Lower)
+}
+
+// RoundToTick is 32 bit version of Bound64.RoundToTick
+func (b *Bound32) RoundToTick(t TickSize) {
+	b.Upper = t.Ceil32(b.Upper)
+	b.Midpoint = t.Round32(b.Midpoint)
+	b.Lower = t.Floor32(b.Lower)
+}
@@ -0,0 +1,175 @@
+package technical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// quarter and cent are tick sizes of 0.25 and 0.01
+var (
+	quarter, _ = NewTickSize(0.25)
+	cent, _    = NewTickSize(0.01)
+)
+
+func TestNewTickSize(t *testing.T) {
+	for _, c := range []struct {
+		tick     float64
+		num, den int64
+	}{
+		{0.01, 1, 100},
+		{0.25, 1, 4},
+		{0.03125, 1, 32},
+		{0.0001, 1, 10000},
+		{0.05, 1, 20},
+		{5, 5, 1},
+		{1.5, 3, 2},
+	} {
+		ts, err := NewTickSize(c.tick)
+		assert.Nil(t, err)
+		assert.Equal(t, TickSize{num: c.num, den: c.den}, ts, "tick %v", c.tick)
+		assert.Equal(t, c.tick, ts.Float64())
+	}
+
+	ts, err := NewTickSize(0)
+	assert.Nil(t, err)
+	assert.True(t, ts.IsZero())
+
+	_, err = NewTickSize(-0.01)
+	assert.Equal(t, ErrInvalidTickSize, err)
+	_, err = NewTickSize(1e-13)
+	assert.Equal(t, ErrInvalidTickSize, Cause(err))
+
+	ts, err = NewTickSizeFraction(2, 64)
+	assert.Nil(t, err)
+	assert.Equal(t, TickSize{num: 1, den: 32}, ts)
+
+	_, err = NewTickSizeFraction(1, 0)
+	assert.Equal(t, ErrInvalidTickSize, err)
+
+	third, _ := NewTickSizeFraction(1, 3)
+	assert.Equal(t, "1/3", third.String())
+	assert.Equal(t, "0.03125", ts.String())
+	assert.Equal(t, "0.25", quarter.String())
+	assert.Equal(t, "0", TickSize{}.String())
+}
+
+func TestTickSizeRound(t *testing.T) {
+	// quarter ticks
+	assert.Equal(t, 10.25, quarter.Floor(10.3))
+	assert.Equal(t, 10.5, quarter.Ceil(10.3))
+	assert.Equal(t, 10.25, quarter.Round(10.3))
+	assert.Equal(t, 10.5, quarter.Round(10.375))
+	assert.Equal(t, -10.5, quarter.Round(-10.375))
+	assert.Equal(t, -10.5, quarter.Floor(-10.3))
+	n41, _ := quarter.FloorTicks(10.3)
+	n42, _ := quarter.CeilTicks(10.3)
+	nr, _ := quarter.RoundTicks(10.3)
+	assert.Equal(t, []int64{41, 42, 41}, []int64{n41, n42, nr})
+
+	// prices on the grid are unchanged despite float error, e.g. 0.1 + 0.2
+	dime, _ := NewTickSize(0.1)
+	a, b := 0.1, 0.2
+	assert.NotEqual(t, 0.3, a+b)
+	assert.Equal(t, 0.3, dime.Floor(a+b))
+	assert.Equal(t, 0.3, dime.Ceil(a+b))
+	assert.Equal(t, 10.3, cent.Ceil(10.3))
+	assert.Equal(t, 10.3, cent.Floor(10.3))
+	assert.Equal(t, 1.13, cent.Floor(1.13))
+
+	// the result is the nearest float to the exact multiple, not n * tick
+	assert.Equal(t, 0.3, dime.Price(3))
+	n, tick := 3.0, 0.1
+	assert.NotEqual(t, 0.3, n*tick)
+
+	// 1/32 bond prices
+	thirtySecond, _ := NewTickSizeFraction(1, 32)
+	assert.Equal(t, 99.0+15.0/32.0, thirtySecond.Floor(99.48))
+	assert.Equal(t, 99.5, thirtySecond.Ceil(99.48))
+
+	// sub penny
+	hundredth, _ := NewTickSize(0.0001)
+	assert.Equal(t, 1.2345, hundredth.Floor(1.23456))
+	assert.Equal(t, 1.2346, hundredth.Ceil(1.23451))
+
+	// the zero tick size does not round
+	assert.Equal(t, 10.3, TickSize{}.Floor(10.3))
+	assert.Equal(t, 10.3, TickSize{}.Ceil(10.3))
+	assert.Equal(t, 10.3, TickSize{}.Round(10.3))
+	zero, err := TickSize{}.RoundTicks(10.3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), zero)
+	assert.Equal(t, 0.0, TickSize{}.Price(3))
+	assert.Equal(t, float32(10.3), TickSize{}.Floor32(10.3))
+}
+
+func TestTickSizeInvalidPrice(t *testing.T) {
+	_, err := cent.FloorTicks(math.NaN())
+	assert.Equal(t, ErrInvalidPrice, Cause(err))
+	_, err = cent.CeilTicks(math.Inf(1))
+	assert.Equal(t, ErrInvalidPrice, Cause(err))
+	_, err = cent.RoundTicks(1e18)
+	assert.Equal(t, ErrInvalidPrice, Cause(err))
+
+	// the largest counts are exact
+	n, err := cent.RoundTicks(90071992547409.92)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1<<53), n)
+	assert.Equal(t, 90071992547409.92, cent.Price(n))
+	assert.Equal(t, 1e15, cent.Price(int64(1e17)))
+
+	// invalid prices are not rounded
+	assert.True(t, math.IsNaN(cent.Floor(math.NaN())))
+	assert.Equal(t, math.Inf(1), cent.Ceil(math.Inf(1)))
+	assert.Equal(t, math.Inf(-1), cent.Round(math.Inf(-1)))
+	assert.Equal(t, 1e18, cent.Floor(1e18))
+	assert.Equal(t, float32(math.Inf(1)), cent.Ceil32(float32(math.Inf(1))))
+}
+
+func TestTickSizeLargePrices(t *testing.T) {
+	// the snap does not grow with the price, so Floor and Ceil stay on their side of the input
+	assert.Equal(t, 60000.01, cent.Ceil(60000.00005))
+	assert.Equal(t, 999999.99, cent.Floor(999999.9999))
+	assert.Equal(t, 60000.0, cent.Floor(60000.00005))
+	assert.Equal(t, 1000000.0, cent.Ceil(999999.9999))
+
+	for _, x := range []float64{60000.00005, 999999.9999, 12345678.901234} {
+		assert.True(t, cent.Ceil(x) >= x, "%v", x)
+		assert.True(t, cent.Floor(x) <= x, "%v", x)
+	}
+
+	b := Bound64{Lower: 999999.9999, Midpoint: 1000000.0, Upper: 60000.00005 + 1e6}
+	r := b
+	r.RoundToTick(cent)
+	assert.Equal(t, Bound64{Lower: 999999.99, Midpoint: 1000000.0, Upper: 1060000.01}, r)
+	assert.True(t, r.Lower <= b.Lower && r.Upper >= b.Upper)
+}
+
+func TestTickSizeRound32(t *testing.T) {
+	assert.Equal(t, float32(10.3), cent.Floor32(10.3))
+	assert.Equal(t, float32(10.3), cent.Ceil32(10.3))
+	assert.Equal(t, float32(10.3), cent.Round32(10.304))
+	assert.Equal(t, float32(10.25), quarter.Floor32(10.3))
+	assert.Equal(t, float32(10.5), quarter.Ceil32(10.3))
+}
+
+func TestBoundRoundToTick(t *testing.T) {
+	b := Bound64{Lower: 10.123, Midpoint: 10.37, Upper: 10.601}
+
+	b.RoundToTick(quarter)
+	assert.Equal(t, Bound64{Lower: 10.0, Midpoint: 10.25, Upper: 10.75}, b)
+
+	// the same as RoundBoundToNearestCent64 for the bounds
+	b = Bound64{Lower: 10.123, Midpoint: 10.125, Upper: 10.123}
+	c := b
+	b.RoundToTick(cent)
+	RoundBoundToNearestCent64(&c)
+	assert.Equal(t, c.Upper, b.Upper)
+	assert.Equal(t, c.Lower, b.Lower)
+	assert.Equal(t, 10.13, b.Midpoint)
+
+	b32 := Bound32{Lower: 10.123, Midpoint: 10.37, Upper: 10.601}
+	b32.RoundToTick(quarter)
+	assert.Equal(t, Bound32{Lower: 10.0, Midpoint: 10.25, Upper: 10.75}, b32)
+}
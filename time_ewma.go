@@ -0,0 +1,370 @@
+package technical
+
+import (
+	"math"
+	"time"
+)
+
+/*
+* A time aware Exponentially Weighted Moving Average decays the prior average by the time elapsed
+* between observations rather than by the number of observations.
+*
+* This suits irregularly spaced data such as ticks, where gaps and bursts would otherwise skew an EWMA
+* that assumes equally spaced values. The decay is parameterized by a half life, the duration after which
+* the weight of an observation has halved. The weight of the prior average after an elapsed time dt is
+* exp(-dt/τ) where τ = halfLife / ln(2).
+*
+* Bursts of trades often share a timestamp. No time elapses between them, so rather than decaying the average
+* the values of a timestamp are weighted equally: the average is the decayed average of the prior timestamps
+* plus the mean of the values of the current timestamp, whatever their order.
+ */
+
+// timeDecay returns the weight of the prior average after dt has elapsed for the given half life
+func timeDecay(dt time.Duration, halfLife time.Duration) float64 {
+	if halfLife <= 0 {
+		return 0.0
+	}
+
+	if dt <= 0 {
+		return 1.0
+	}
+
+	tau := float64(halfLife) / math.Ln2
+
+	return math.Exp(-float64(dt) / tau)
+}
+
+// RollingTimeEMA64 computes the next time aware EWMA value in a series
+// Assumes last EWMA value given is correct
+// Observations with the same timestamp as the last (dt <= 0) do not move the average,
+// TimeEMAStream64 and the series functions weight them equally with the other values of the timestamp instead
+//
+// Parameters:
+//
+//	v: the current value in the series
+//	last: the last EWMA value of the series
+//	dt: time elapsed since the observation of the last EWMA value
+//	halfLife: duration after which the weight of an observation has halved. If <= 0 the current value is returned
+func RollingTimeEMA64(v float64, last float64, dt time.Duration, halfLife time.Duration) float64 {
+	w := timeDecay(dt, halfLife)
+
+	return v*(1-w) + w*last
+}
+
+// RollingTimeEMA32 is 32 bit version of RollingTimeEMA64
+func RollingTimeEMA32(v float32, last float32, dt time.Duration, halfLife time.Duration) float32 {
+	w := float32(timeDecay(dt, halfLife))
+
+	return v*(1-w) + w*last
+}
+
+// TimeEwmaSeries64 computes a list of time aware EWMAs for a given list of values and their timestamps
+// Assumes ascending time order
+// The first value seeds the average. Values observed before one half life has
+// elapsed since the first value are 0.0, similar to the lookback warm up of EwmaSeries64.
+//
+// Parameters:
+//
+//	series: the data series
+//	times: the timestamp of each value in series. Must be the same length as series
+//	halfLife: duration after which the weight of an observation has halved
+//
+// Returns nil if the series is empty or the lengths of series and times differ.
+// See TimeEwmaSeriesChecked64 to validate the parameters instead.
+func TimeEwmaSeries64(series []float64, times []time.Time, halfLife time.Duration) []float64 {
+	if len(series) == 0 || len(series) != len(times) {
+		return nil
+	}
+
+	s := &TimeEMAStream64{halfLife: halfLife}
+
+	ewmas := make([]float64, len(series))
+	for i, v := range series {
+		s.Update(times[i], v)
+		if times[i].Sub(times[0]) < halfLife {
+			continue
+		}
+
+		ewmas[i] = s.ema
+	}
+
+	return ewmas
+}
+
+// TimeEwmaSeries32 is 32 bit version of TimeEwmaSeries64
+func TimeEwmaSeries32(series []float32, times []time.Time, halfLife time.Duration) []float32 {
+	if len(series) == 0 || len(series) != len(times) {
+		return nil
+	}
+
+	return to32(TimeEwmaSeries64(to64(series), times, halfLife))
+}
+
+// TimeEwmaSeriesChecked64 is TimeEwmaSeries64 returning an error for invalid parameters
+// Returns ErrEmptySeries, ErrLengthMismatch and ErrInvalidHalfLife if halfLife <= 0
+func TimeEwmaSeriesChecked64(series []float64, times []time.Time, halfLife time.Duration) ([]float64, error) {
+	if err := checkTimeSeries(len(series), len(times), halfLife); err != nil {
+		return nil, err
+	}
+
+	return TimeEwmaSeries64(series, times, halfLife), nil
+}
+
+// TimeEwmaSeriesChecked32 is 32 bit version of TimeEwmaSeriesChecked64
+func TimeEwmaSeriesChecked32(series []float32, times []time.Time, halfLife time.Duration) ([]float32, error) {
+	if err := checkTimeSeries(len(series), len(times), halfLife); err != nil {
+		return nil, err
+	}
+
+	return TimeEwmaSeries32(series, times, halfLife), nil
+}
+
+// checkTimeSeries validates the parameters of a time aware series
+func checkTimeSeries(size int, timesSize int, halfLife time.Duration) error {
+	if size == 0 {
+		return ErrEmptySeries
+	}
+
+	if size != timesSize {
+		return ErrLengthMismatch
+	}
+
+	if halfLife <= 0 {
+		return ErrInvalidHalfLife
+	}
+
+	return nil
+}
+
+// TimeEMAStream64 computes a time aware EWMA over a stream of timestamped values
+// Values with the same timestamp are weighted equally.
+type TimeEMAStream64 struct {
+	halfLife time.Duration
+	first    time.Time
+	last     time.Time
+	base     float64 // average of the timestamps before the last
+	w        float64 // weight of base, 0.0 at the first timestamp
+	sum      float64 // sum of the values of the last timestamp
+	k        int     // number of values of the last timestamp
+	ema      float64
+	n        int
+}
+
+// NewTimeEMAStream64 creates a TimeEMAStream64 with the given half life
+// Returns ErrInvalidHalfLife if halfLife <= 0
+func NewTimeEMAStream64(halfLife time.Duration) (*TimeEMAStream64, error) {
+	if halfLife <= 0 {
+		return nil, ErrInvalidHalfLife
+	}
+
+	return &TimeEMAStream64{halfLife: halfLife}, nil
+}
+
+// Update adds the value v observed at time t
+// Values observed before the last observed value are ignored, and values observed at the same time
+// as the last are blended in with equal weight.
+func (s *TimeEMAStream64) Update(t time.Time, v float64) {
+	switch {
+	case s.n == 0:
+		s.first = t
+	case t.Before(s.last):
+		return
+	case t.After(s.last):
+		s.base = s.ema
+		s.w = timeDecay(t.Sub(s.last), s.halfLife)
+		s.sum, s.k = 0.0, 0
+	}
+
+	s.sum += v
+	s.k++
+	s.ema = s.w*s.base + (1-s.w)*s.sum/float64(s.k)
+
+	s.last = t
+	s.n++
+}
+
+// Value returns the current EWMA
+func (s *TimeEMAStream64) Value() float64 {
+	return s.ema
+}
+
+// Ready reports whether one half life has elapsed since the first observed value
+func (s *TimeEMAStream64) Ready() bool {
+	return s.n > 0 && s.last.Sub(s.first) >= s.halfLife
+}
+
+// WarmupDuration returns the time after the first observed value before the stream is ready
+func (s *TimeEMAStream64) WarmupDuration() time.Duration {
+	return s.halfLife
+}
+
+// Reset clears the stream
+func (s *TimeEMAStream64) Reset() {
+	*s = TimeEMAStream64{halfLife: s.halfLife}
+}
+
+// AdjustGap shifts the EWMA by gap as if every value seen so far had been gap higher
+func (s *TimeEMAStream64) AdjustGap(gap float64) {
+	s.ema += gap
+	s.base += gap
+	s.sum += gap * float64(s.k)
+}
+
+// Clone returns an independent copy of the stream
+func (s *TimeEMAStream64) Clone() *TimeEMAStream64 {
+	c := *s
+	return &c
+}
+
+// TimeBollingerStream64 computes a time aware Bollinger Bound over a stream of timestamped values
+// The midpoint is a time aware EWMA and the legs are scaled by the exponentially
+// weighted standard deviation using the same time decay. As for the midpoint,
+// values with the same timestamp are weighted equally.
+type TimeBollingerStream64 struct {
+	ema    TimeEMAStream64
+	vr     float64
+	baseVr float64 // variance of the timestamps before the last
+	m2     float64 // sum of squared differences from the mean of the values of the last timestamp
+	a      float64
+}
+
+// NewTimeBollingerStream64 creates a TimeBollingerStream64
+// Returns ErrInvalidHalfLife if halfLife <= 0 and ErrInvalidMultiplier if a < 0
+//
+// Parameters:
+//
+//	halfLife: duration after which the weight of an observation has halved
+//	a (alpha): multiplier on the weighted standard deviation
+func NewTimeBollingerStream64(halfLife time.Duration, a float64) (*TimeBollingerStream64, error) {
+	if halfLife <= 0 {
+		return nil, ErrInvalidHalfLife
+	}
+
+	if err := checkMultiplier(a); err != nil {
+		return nil, err
+	}
+
+	return &TimeBollingerStream64{ema: TimeEMAStream64{halfLife: halfLife}, a: a}, nil
+}
+
+// Update adds the value v observed at time t
+// Values observed before the last observed value are ignored
+func (s *TimeBollingerStream64) Update(t time.Time, v float64) {
+	e := &s.ema
+	if e.n > 0 && t.Before(e.last) {
+		return
+	}
+
+	if e.n == 0 || t.After(e.last) {
+		s.baseVr, s.m2 = s.vr, 0.0
+	}
+
+	// welford's update of the values of the timestamp
+	prior := 0.0
+	if e.k > 0 && !t.After(e.last) {
+		prior = e.sum / float64(e.k)
+	}
+
+	e.Update(t, v)
+	mean := e.sum / float64(e.k)
+	if e.k > 1 {
+		s.m2 += (v - prior) * (v - mean)
+	}
+
+	// the prior weighted by w mixed with the values of the timestamp weighted equally by 1 - w
+	diff := mean - e.base
+	s.vr = e.w*(s.baseVr+(1-e.w)*diff*diff) + (1-e.w)*s.m2/float64(e.k)
+}
+
+// Bound returns the current Bollinger Bound
+func (s *TimeBollingerStream64) Bound() Bound64 {
+	var b Bound64
+
+	leg := math.Sqrt(s.vr) * s.a
+
+	b.Midpoint = s.ema.ema
+	b.Lower = b.Midpoint - leg
+	b.Upper = b.Midpoint + leg
+
+	return b
+}
+
+// Value returns the midpoint of the current Bollinger Bound
+func (s *TimeBollingerStream64) Value() float64 {
+	return s.ema.Value()
+}
+
+// Ready reports whether one half life has elapsed since the first observed value
+func (s *TimeBollingerStream64) Ready() bool {
+	return s.ema.Ready()
+}
+
+// WarmupDuration returns the time after the first observed value before the stream is ready
+func (s *TimeBollingerStream64) WarmupDuration() time.Duration {
+	return s.ema.halfLife
+}
+
+// Reset clears the stream
+func (s *TimeBollingerStream64) Reset() {
+	s.ema.Reset()
+	s.vr, s.baseVr, s.m2 = 0.0, 0.0, 0.0
+}
+
+// AdjustGap shifts the midpoint by gap, leaving the width of the band unchanged
+func (s *TimeBollingerStream64) AdjustGap(gap float64) {
+	s.ema.AdjustGap(gap)
+}
+
+// Clone returns an independent copy of the stream
+func (s *TimeBollingerStream64) Clone() *TimeBollingerStream64 {
+	c := *s
+	return &c
+}
+
+// StaticBollingerTimeEMA64 creates a float64 time aware Bollinger Band for a series of timestamped values
+// Assumes ascending time order
+// Uses a time aware EWMA midpoint and an exponentially weighted standard deviation with the same time decay.
+// Bounds observed before one half life has elapsed since the first value are empty.
+//
+// Parameters:
+//
+//	series: data series
+//	times: the timestamp of each value in series. Must be the same length as series
+//	halfLife: duration after which the weight of an observation has halved
+//	a (alpha): multiplier on the weighted standard deviation
+//
+// Returns nil if the series is empty or the lengths of series and times differ.
+// See StaticBollingerTimeEMAChecked64 to validate the parameters instead.
+func StaticBollingerTimeEMA64(series []float64, times []time.Time, halfLife time.Duration, a float64) []Bound64 {
+	if len(series) == 0 || len(series) != len(times) {
+		return nil
+	}
+
+	s := &TimeBollingerStream64{ema: TimeEMAStream64{halfLife: halfLife}, a: a}
+
+	band := make([]Bound64, len(series))
+	for i, v := range series {
+		s.Update(times[i], v)
+		if !s.Ready() {
+			continue
+		}
+
+		band[i] = s.Bound()
+	}
+
+	return band
+}
+
+// StaticBollingerTimeEMAChecked64 is StaticBollingerTimeEMA64 returning an error for invalid parameters
+// Returns ErrEmptySeries, ErrLengthMismatch, ErrInvalidHalfLife if halfLife <= 0 and ErrInvalidMultiplier if a < 0
+func StaticBollingerTimeEMAChecked64(series []float64, times []time.Time, halfLife time.Duration, a float64) ([]Bound64, error) {
+	if err := checkTimeSeries(len(series), len(times), halfLife); err != nil {
+		return nil, err
+	}
+
+	if err := checkMultiplier(a); err != nil {
+		return nil, err
+	}
+
+	return StaticBollingerTimeEMA64(series, times, halfLife, a), nil
+}
@@ -0,0 +1,223 @@
+package technical
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollingTimeEMA64(t *testing.T) {
+	// one half life halves the weight of the last value
+	assert.Equal(t, 7.5, RollingTimeEMA64(10, 5, time.Minute, time.Minute))
+
+	// two half lives
+	assert.Equal(t, 8.75, RollingTimeEMA64(10, 5, 2*time.Minute, time.Minute))
+
+	// no time elapsed
+	assert.Equal(t, 5.0, RollingTimeEMA64(10, 5, 0, time.Minute))
+
+	// no half life
+	assert.Equal(t, 10.0, RollingTimeEMA64(10, 5, time.Second, 0))
+}
+
+func TestRollingTimeEMA32(t *testing.T) {
+	assert.Equal(t, float32(7.5), RollingTimeEMA32(10, 5, time.Minute, time.Minute))
+	assert.Equal(t, float32(8.75), RollingTimeEMA32(10, 5, 2*time.Minute, time.Minute))
+	assert.Equal(t, float32(5.0), RollingTimeEMA32(10, 5, 0, time.Minute))
+}
+
+func TestTimeEwmaSeries64(t *testing.T) {
+	// nil or mismatched
+	assert.Nil(t, TimeEwmaSeries64(nil, nil, time.Minute))
+	assert.Nil(t, TimeEwmaSeries64([]float64{1, 2}, mockSessionTimes(1), time.Minute))
+
+	// irregular spacing, the gap decays the prior average more
+	times := []time.Time{
+		mockSessionOpen,
+		mockSessionOpen.Add(time.Second),
+		mockSessionOpen.Add(3 * time.Second),
+	}
+	ewmas := TimeEwmaSeries64([]float64{4, 8, 8}, times, time.Second)
+	assert.Equal(t, []float64{0, 6, 7.5}, ewmas)
+
+	// equally spaced values match a standard EWMA of the equivalent lambda
+	testseries := readMockSeries64("./mock/test_series.txt")
+	testtimes := mockSessionTimes(len(testseries))
+
+	halfLife := 10 * time.Minute
+	y := 1 - math.Exp(-math.Ln2*float64(time.Second)/float64(halfLife))
+
+	ewmas = TimeEwmaSeries64(testseries, testtimes, halfLife)
+	assert.Len(t, ewmas, len(testseries))
+	assert.Equal(t, 0.0, ewmas[598])
+
+	last := testseries[0]
+	for _, v := range testseries[1:] {
+		last = RollingEMA64(v, last, y)
+	}
+	assert.InDelta(t, last, ewmas[len(ewmas)-1], 1e-9)
+}
+
+func TestTimeEwmaSeries32(t *testing.T) {
+	assert.Nil(t, TimeEwmaSeries32(nil, nil, time.Minute))
+
+	times := []time.Time{
+		mockSessionOpen,
+		mockSessionOpen.Add(time.Second),
+		mockSessionOpen.Add(3 * time.Second),
+	}
+	ewmas := TimeEwmaSeries32([]float32{4, 8, 8}, times, time.Second)
+	assert.Equal(t, []float32{0, 6, 7.5}, ewmas)
+}
+
+func TestTimeEwmaSeriesChecked64(t *testing.T) {
+	_, err := TimeEwmaSeriesChecked64(nil, nil, time.Second)
+	assert.Equal(t, ErrEmptySeries, err)
+
+	_, err = TimeEwmaSeriesChecked64([]float64{1, 2}, mockSessionTimes(1), time.Second)
+	assert.Equal(t, ErrLengthMismatch, err)
+
+	_, err = TimeEwmaSeriesChecked64([]float64{1, 2}, mockSessionTimes(2), 0)
+	assert.Equal(t, ErrInvalidHalfLife, err)
+
+	ewmas, err := TimeEwmaSeriesChecked64([]float64{4, 8}, mockSessionTimes(2), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 6}, ewmas)
+}
+
+func TestTimeEwmaSeriesChecked32(t *testing.T) {
+	_, err := TimeEwmaSeriesChecked32([]float32{1, 2}, mockSessionTimes(1), time.Second)
+	assert.Equal(t, ErrLengthMismatch, err)
+
+	ewmas, err := TimeEwmaSeriesChecked32([]float32{4, 8}, mockSessionTimes(2), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0, 6}, ewmas)
+}
+
+func TestTimeEMAStream64(t *testing.T) {
+	_, err := NewTimeEMAStream64(0)
+	assert.Equal(t, ErrInvalidHalfLife, err)
+
+	s, err := NewTimeEMAStream64(time.Second)
+	assert.NoError(t, err)
+	assert.False(t, s.Ready())
+
+	s.Update(mockSessionOpen, 4)
+	assert.False(t, s.Ready())
+	assert.Equal(t, 4.0, s.Value())
+
+	s.Update(mockSessionOpen.Add(time.Second), 8)
+	assert.True(t, s.Ready())
+	assert.Equal(t, 6.0, s.Value())
+
+	// out of order values are ignored
+	s.Update(mockSessionOpen, 100)
+	assert.Equal(t, 6.0, s.Value())
+
+	s.Update(mockSessionOpen.Add(3*time.Second), 8)
+	assert.Equal(t, 7.5, s.Value())
+
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, 0.0, s.Value())
+}
+
+func TestTimeEMAStreamSameTimestamp(t *testing.T) {
+	later := mockSessionOpen.Add(time.Second)
+
+	// the values of a timestamp are weighted equally in any order
+	for _, values := range [][]float64{{6, 10}, {10, 6}} {
+		s, _ := NewTimeEMAStream64(time.Second)
+		s.Update(mockSessionOpen, 4)
+		for _, v := range values {
+			s.Update(later, v)
+		}
+
+		// w = 0.5 of 4 and 0.5 of the mean 8
+		assert.Equal(t, 6.0, s.Value())
+
+		// the gap shifts the values of the timestamp too
+		s.AdjustGap(1)
+		s.Update(later, 9)
+		assert.InDelta(t, 0.5*5+0.5*(7+11+9)/3.0, s.Value(), 1e-12)
+	}
+
+	// values at the first timestamp are averaged
+	s, _ := NewTimeEMAStream64(time.Second)
+	s.Update(mockSessionOpen, 4)
+	s.Update(mockSessionOpen, 8)
+	assert.Equal(t, 6.0, s.Value())
+
+	times := []time.Time{mockSessionOpen, later, later}
+	assert.Equal(t, []float64{0, 5, 6}, TimeEwmaSeries64([]float64{4, 6, 10}, times, time.Second))
+	assert.Equal(t, []float32{0, 5, 6}, TimeEwmaSeries32([]float32{4, 6, 10}, times, time.Second))
+
+	// w = 0.5, diff of the mean 4, variance of the timestamp 4: var = 0.5 * (0 + 0.5 * 16) + 0.5 * 4 = 6
+	b, _ := NewTimeBollingerStream64(time.Second, 2)
+	for i, v := range []float64{4, 6, 10} {
+		b.Update(times[i], v)
+	}
+
+	leg := 2 * math.Sqrt(6)
+	assert.Equal(t, 6.0, b.Value())
+	assert.InDelta(t, 6.0+leg, b.Bound().Upper, 1e-12)
+	assert.InDelta(t, 6.0-leg, b.Bound().Lower, 1e-12)
+}
+
+func TestTimeBollingerStream64(t *testing.T) {
+	_, err := NewTimeBollingerStream64(0, 2)
+	assert.Equal(t, ErrInvalidHalfLife, err)
+
+	_, err = NewTimeBollingerStream64(time.Second, -2)
+	assert.Equal(t, ErrInvalidMultiplier, err)
+
+	s, err := NewTimeBollingerStream64(time.Second, 2)
+	assert.NoError(t, err)
+
+	s.Update(mockSessionOpen, 4)
+	assert.Equal(t, Bound64{Lower: 4, Midpoint: 4, Upper: 4}, s.Bound())
+
+	// w = 0.5, diff = 4, var = 0.5 * (0 + 0.5 * 16) = 4
+	s.Update(mockSessionOpen.Add(time.Second), 8)
+	assert.True(t, s.Ready())
+	assert.Equal(t, Bound64{Lower: 2, Midpoint: 6, Upper: 10}, s.Bound())
+
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, Bound64{}, s.Bound())
+}
+
+func TestStaticBollingerTimeEMA64(t *testing.T) {
+	assert.Nil(t, StaticBollingerTimeEMA64(nil, nil, time.Minute, 2))
+
+	times := []time.Time{
+		mockSessionOpen,
+		mockSessionOpen.Add(time.Second),
+	}
+	band := StaticBollingerTimeEMA64([]float64{4, 8}, times, time.Second, 2)
+	assert.Equal(t, []Bound64{{}, {Lower: 2, Midpoint: 6, Upper: 10}}, band)
+
+	// midpoint of the full series is the time aware EWMA
+	testseries := readMockSeries64("./mock/test_series.txt")
+	testtimes := mockSessionTimes(len(testseries))
+
+	band = StaticBollingerTimeEMA64(testseries, testtimes, 10*time.Minute, 2)
+	ewmas := TimeEwmaSeries64(testseries, testtimes, 10*time.Minute)
+	b := band[len(band)-1]
+	assert.Equal(t, ewmas[len(ewmas)-1], b.Midpoint)
+	assert.True(t, b.Lower < b.Midpoint && b.Midpoint < b.Upper)
+}
+
+func TestStaticBollingerTimeEMAChecked64(t *testing.T) {
+	_, err := StaticBollingerTimeEMAChecked64([]float64{1, 2}, mockSessionTimes(2), -time.Second, 2)
+	assert.Equal(t, ErrInvalidHalfLife, err)
+
+	_, err = StaticBollingerTimeEMAChecked64([]float64{1, 2}, mockSessionTimes(2), time.Second, -2)
+	assert.Equal(t, ErrInvalidMultiplier, err)
+
+	band, err := StaticBollingerTimeEMAChecked64([]float64{4, 8}, mockSessionTimes(2), time.Second, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, Bound64{Lower: 2, Midpoint: 6, Upper: 10}, band[1])
+}
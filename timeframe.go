@@ -0,0 +1,479 @@
+package technical
+
+import (
+	"time"
+)
+
+/*
+* Multi timeframe evaluation feeds a single tick stream into several bar aggregators,
+* each with its own set of streaming indicators computed on its bars.
+*
+* For every tick a snapshot gives each timeframe's indicator values as of its last completed bar,
+* and as they would be if the in progress bar closed on that tick. Both only use ticks up to
+* and including the current tick, so there is no look-ahead.
+ */
+
+// IndicatorValue is the output of a streaming indicator at a point in time
+// Scalar indicators set Value. Band indicators set Bound and its Midpoint as Value.
+type IndicatorValue struct {
+	Value float64 `json:"value"`
+	Bound Bound64 `json:"bound"`
+	Ready bool    `json:"ready"`
+}
+
+// BarIndicator is a streaming indicator computed on the bars of a timeframe
+type BarIndicator interface {
+	// UpdateBar adds the next completed bar.
+	UpdateBar(b Bar)
+	// Snapshot returns the current value of the indicator.
+	Snapshot() IndicatorValue
+	// Clone returns an independent copy of the indicator.
+	Clone() BarIndicator
+	// Reset clears the indicator.
+	Reset()
+}
+
+// emaBarIndicator adapts an EMAStream64 to a BarIndicator
+type emaBarIndicator struct {
+	s *EMAStream64
+}
+
+// EMABarIndicator computes an EMAStream64 on the closes of bars
+func EMABarIndicator(s *EMAStream64) BarIndicator {
+	return emaBarIndicator{s}
+}
+
+// UpdateBar adds the next completed bar
+func (i emaBarIndicator) UpdateBar(b Bar) {
+	i.s.Update(b.Close)
+}
+
+// Snapshot returns the current value
+func (i emaBarIndicator) Snapshot() IndicatorValue {
+	return IndicatorValue{Value: i.s.Value(), Ready: i.s.Ready()}
+}
+
+// Clone returns an independent copy
+func (i emaBarIndicator) Clone() BarIndicator {
+	return emaBarIndicator{i.s.Clone()}
+}
+
+// Reset clears the indicator
+func (i emaBarIndicator) Reset() {
+	i.s.Reset()
+}
+
+// bollingerEMABarIndicator adapts a BollingerEMAStream64 to a BarIndicator
+type bollingerEMABarIndicator struct {
+	s *BollingerEMAStream64
+}
+
+// BollingerEMABarIndicator computes a BollingerEMAStream64 on the closes of bars
+func BollingerEMABarIndicator(s *BollingerEMAStream64) BarIndicator {
+	return bollingerEMABarIndicator{s}
+}
+
+// UpdateBar adds the next completed bar
+func (i bollingerEMABarIndicator) UpdateBar(b Bar) {
+	i.s.Update(b.Close)
+}
+
+// Snapshot returns the current value
+func (i bollingerEMABarIndicator) Snapshot() IndicatorValue {
+	b := i.s.Bound()
+
+	return IndicatorValue{Value: b.Midpoint, Bound: b, Ready: i.s.Ready()}
+}
+
+// Clone returns an independent copy
+func (i bollingerEMABarIndicator) Clone() BarIndicator {
+	return bollingerEMABarIndicator{i.s.Clone()}
+}
+
+// Reset clears the indicator
+func (i bollingerEMABarIndicator) Reset() {
+	i.s.Reset()
+}
+
+// atrBarIndicator adapts an ATRStream64 to a BarIndicator
+type atrBarIndicator struct {
+	s *ATRStream64
+}
+
+// ATRBarIndicator computes an ATRStream64 on bars
+func ATRBarIndicator(s *ATRStream64) BarIndicator {
+	return atrBarIndicator{s}
+}
+
+// UpdateBar adds the next completed bar
+func (i atrBarIndicator) UpdateBar(b Bar) {
+	i.s.UpdateBar(b)
+}
+
+// Snapshot returns the current value
+func (i atrBarIndicator) Snapshot() IndicatorValue {
+	return IndicatorValue{Value: i.s.Value(), Ready: i.s.Ready()}
+}
+
+// Clone returns an independent copy
+func (i atrBarIndicator) Clone() BarIndicator {
+	return atrBarIndicator{i.s.Clone()}
+}
+
+// Reset clears the indicator
+func (i atrBarIndicator) Reset() {
+	i.s.Reset()
+}
+
+// linRegBarIndicator adapts a LinRegStream64 to a BarIndicator
+type linRegBarIndicator struct {
+	s *LinRegStream64
+	a float64
+}
+
+// LinRegBarIndicator computes a LinRegStream64 on the closes of bars
+// The value is the endpoint of the fitted line and the bound is its channel with multiplier a.
+func LinRegBarIndicator(s *LinRegStream64, a float64) BarIndicator {
+	return linRegBarIndicator{s, a}
+}
+
+// UpdateBar adds the next completed bar
+func (i linRegBarIndicator) UpdateBar(b Bar) {
+	i.s.Update(b.Close)
+}
+
+// Snapshot returns the current value
+func (i linRegBarIndicator) Snapshot() IndicatorValue {
+	b := i.s.Channel(i.a)
+
+	return IndicatorValue{Value: b.Midpoint, Bound: b, Ready: i.s.Ready()}
+}
+
+// Clone returns an independent copy
+func (i linRegBarIndicator) Clone() BarIndicator {
+	return linRegBarIndicator{i.s.Clone(), i.a}
+}
+
+// Reset clears the indicator
+func (i linRegBarIndicator) Reset() {
+	i.s.Reset()
+}
+
+// timeEMABarIndicator adapts a TimeEMAStream64 to a BarIndicator
+type timeEMABarIndicator struct {
+	s *TimeEMAStream64
+}
+
+// TimeEMABarIndicator computes a TimeEMAStream64 on the closes of bars at their end times
+func TimeEMABarIndicator(s *TimeEMAStream64) BarIndicator {
+	return timeEMABarIndicator{s}
+}
+
+// UpdateBar adds the next completed bar
+func (i timeEMABarIndicator) UpdateBar(b Bar) {
+	i.s.Update(b.End, b.Close)
+}
+
+// Snapshot returns the current value
+func (i timeEMABarIndicator) Snapshot() IndicatorValue {
+	return IndicatorValue{Value: i.s.Value(), Ready: i.s.Ready()}
+}
+
+// Clone returns an independent copy
+func (i timeEMABarIndicator) Clone() BarIndicator {
+	return timeEMABarIndicator{i.s.Clone()}
+}
+
+// Reset clears the indicator
+func (i timeEMABarIndicator) Reset() {
+	i.s.Reset()
+}
+
+// timeBollingerBarIndicator adapts a TimeBollingerStream64 to a BarIndicator
+type timeBollingerBarIndicator struct {
+	s *TimeBollingerStream64
+}
+
+// TimeBollingerBarIndicator computes a TimeBollingerStream64 on the closes of bars at their end times
+func TimeBollingerBarIndicator(s *TimeBollingerStream64) BarIndicator {
+	return timeBollingerBarIndicator{s}
+}
+
+// UpdateBar adds the next completed bar
+func (i timeBollingerBarIndicator) UpdateBar(b Bar) {
+	i.s.Update(b.End, b.Close)
+}
+
+// Snapshot returns the current value
+func (i timeBollingerBarIndicator) Snapshot() IndicatorValue {
+	b := i.s.Bound()
+
+	return IndicatorValue{Value: b.Midpoint, Bound: b, Ready: i.s.Ready()}
+}
+
+// Clone returns an independent copy
+func (i timeBollingerBarIndicator) Clone() BarIndicator {
+	return timeBollingerBarIndicator{i.s.Clone()}
+}
+
+// Reset clears the indicator
+func (i timeBollingerBarIndicator) Reset() {
+	i.s.Reset()
+}
+
+// volumeStream64 is a volume stream, which is updated with whole bars
+type volumeStream64 interface {
+	UpdateBar(b Bar)
+	Value() float64
+	Ready() bool
+	Reset()
+}
+
+// volumeBarIndicator adapts a volume stream to a BarIndicator
+// The VWAP streams also set their bound.
+type volumeBarIndicator struct {
+	s volumeStream64
+}
+
+// OBVBarIndicator computes an OBVStream64 on bars
+func OBVBarIndicator(s *OBVStream64) BarIndicator {
+	return volumeBarIndicator{s}
+}
+
+// ADLineBarIndicator computes an ADLineStream64 on bars
+func ADLineBarIndicator(s *ADLineStream64) BarIndicator {
+	return volumeBarIndicator{s}
+}
+
+// ChaikinMoneyFlowBarIndicator computes a ChaikinMoneyFlowStream64 on bars
+func ChaikinMoneyFlowBarIndicator(s *ChaikinMoneyFlowStream64) BarIndicator {
+	return volumeBarIndicator{s}
+}
+
+// ChaikinOscillatorBarIndicator computes a ChaikinOscillatorStream64 on bars
+func ChaikinOscillatorBarIndicator(s *ChaikinOscillatorStream64) BarIndicator {
+	return volumeBarIndicator{s}
+}
+
+// MFIBarIndicator computes an MFIStream64 on bars
+func MFIBarIndicator(s *MFIStream64) BarIndicator {
+	return volumeBarIndicator{s}
+}
+
+// VWAPBarIndicator computes a VWAPStream64 on bars, with its bands as the bound
+func VWAPBarIndicator(s *VWAPStream64) BarIndicator {
+	return volumeBarIndicator{s}
+}
+
+// RollingVWAPBarIndicator computes a RollingVWAPStream64 on bars, with its bands as the bound
+func RollingVWAPBarIndicator(s *RollingVWAPStream64) BarIndicator {
+	return volumeBarIndicator{s}
+}
+
+// AnchoredVWAPBarIndicator computes an AnchoredVWAPStream64 on bars, with its bands as the bound
+func AnchoredVWAPBarIndicator(s *AnchoredVWAPStream64) BarIndicator {
+	return volumeBarIndicator{s}
+}
+
+// UpdateBar adds the next completed bar
+func (i volumeBarIndicator) UpdateBar(b Bar) {
+	i.s.UpdateBar(b)
+}
+
+// Snapshot returns the current value
+func (i volumeBarIndicator) Snapshot() IndicatorValue {
+	v := IndicatorValue{Value: i.s.Value(), Ready: i.s.Ready()}
+	if band, ok := i.s.(interface{ Bound() Bound64 }); ok {
+		v.Bound = band.Bound()
+	}
+
+	return v
+}
+
+// Clone returns an independent copy
+func (i volumeBarIndicator) Clone() BarIndicator {
+	switch s := i.s.(type) {
+	case *OBVStream64:
+		return volumeBarIndicator{s.Clone()}
+	case *ADLineStream64:
+		return volumeBarIndicator{s.Clone()}
+	case *ChaikinMoneyFlowStream64:
+		return volumeBarIndicator{s.Clone()}
+	case *ChaikinOscillatorStream64:
+		return volumeBarIndicator{s.Clone()}
+	case *MFIStream64:
+		return volumeBarIndicator{s.Clone()}
+	case *VWAPStream64:
+		return volumeBarIndicator{s.Clone()}
+	case *RollingVWAPStream64:
+		return volumeBarIndicator{s.Clone()}
+	case *AnchoredVWAPStream64:
+		return volumeBarIndicator{s.Clone()}
+	}
+
+	return i
+}
+
+// Reset clears the indicator
+func (i volumeBarIndicator) Reset() {
+	i.s.Reset()
+}
+
+// Timeframe is a bar aggregator and the named indicators computed on its bars
+type Timeframe struct {
+	Name       string
+	Aggregator BarAggregator
+	Indicators map[string]BarIndicator
+}
+
+// TimeframeSnapshot is the state of a timeframe after a tick
+// A bar with no ticks means there is no such bar yet.
+type TimeframeSnapshot struct {
+	Closed     bool                      `json:"closed"`     // whether a bar closed on the tick
+	Bar        Bar                       `json:"bar"`        // last completed bar
+	Current    Bar                       `json:"current"`    // in progress bar
+	Completed  map[string]IndicatorValue `json:"completed"`  // indicator values as of the last completed bar
+	InProgress map[string]IndicatorValue `json:"inProgress"` // indicator values if the in progress bar closed now
+}
+
+// MultiTimeframeSnapshot is the state of every timeframe after a tick
+type MultiTimeframeSnapshot struct {
+	Time   time.Time                    `json:"time"`
+	Frames map[string]TimeframeSnapshot `json:"frames"`
+}
+
+type timeframeState struct {
+	Timeframe
+	last      Bar
+	completed map[string]IndicatorValue
+}
+
+// snapshot returns the snapshot of the timeframe, closed reports whether a bar closed on the last tick
+func (f *timeframeState) snapshot(closed bool) TimeframeSnapshot {
+	snap := TimeframeSnapshot{
+		Closed:     closed,
+		Bar:        f.last,
+		Completed:  f.completed,
+		InProgress: f.completed,
+	}
+
+	cur, ok := f.Aggregator.Current()
+	if !ok {
+		return snap
+	}
+
+	// the in progress values are computed on copies so the indicators only ever see completed bars
+	snap.Current = cur
+	snap.InProgress = make(map[string]IndicatorValue, len(f.Indicators))
+	for name, ind := range f.Indicators {
+		c := ind.Clone()
+		c.UpdateBar(cur)
+		snap.InProgress[name] = c.Snapshot()
+	}
+
+	return snap
+}
+
+// complete updates the indicators with a completed bar
+func (f *timeframeState) complete(b Bar) {
+	f.last = b
+
+	// a new map each bar so earlier snapshots are not modified
+	f.completed = make(map[string]IndicatorValue, len(f.Indicators))
+	for name, ind := range f.Indicators {
+		ind.UpdateBar(b)
+		f.completed[name] = ind.Snapshot()
+	}
+}
+
+func (f *timeframeState) reset() {
+	f.Aggregator.Reset()
+	for _, ind := range f.Indicators {
+		ind.Reset()
+	}
+
+	f.last = Bar{}
+	f.completed = f.initial()
+}
+
+// initial returns the indicator values before any bar has completed
+func (f *timeframeState) initial() map[string]IndicatorValue {
+	values := make(map[string]IndicatorValue, len(f.Indicators))
+	for name, ind := range f.Indicators {
+		values[name] = ind.Snapshot()
+	}
+
+	return values
+}
+
+// MultiTimeframe evaluates the indicators of several timeframes on a single tick stream
+// Computing the in progress values clones each indicator on every tick,
+// which is O(lb) for indicators that keep a window of values.
+type MultiTimeframe struct {
+	frames []*timeframeState
+	time   time.Time
+}
+
+// NewMultiTimeframe creates a MultiTimeframe evaluating the given timeframes
+// Returns ErrInvalidTimeframe if a timeframe has no name or aggregator, a nil indicator, or a duplicate name
+func NewMultiTimeframe(frames ...Timeframe) (*MultiTimeframe, error) {
+	m := &MultiTimeframe{}
+	names := make(map[string]bool, len(frames))
+
+	for _, f := range frames {
+		if f.Name == "" || f.Aggregator == nil || names[f.Name] {
+			return nil, ErrInvalidTimeframe
+		}
+
+		for _, ind := range f.Indicators {
+			if ind == nil {
+				return nil, ErrInvalidTimeframe
+			}
+		}
+
+		names[f.Name] = true
+
+		state := &timeframeState{Timeframe: f}
+		state.completed = state.initial()
+		m.frames = append(m.frames, state)
+	}
+
+	return m, nil
+}
+
+// Update adds the next tick to every timeframe and returns the snapshot after it
+// Assumes ascending time order
+func (m *MultiTimeframe) Update(t Tick) MultiTimeframeSnapshot {
+	m.time = t.Time
+
+	snap := MultiTimeframeSnapshot{Time: t.Time, Frames: make(map[string]TimeframeSnapshot, len(m.frames))}
+	for _, f := range m.frames {
+		b, closed := f.Aggregator.Update(t)
+		if closed {
+			f.complete(b)
+		}
+
+		snap.Frames[f.Name] = f.snapshot(closed)
+	}
+
+	return snap
+}
+
+// Snapshot returns the snapshot after the last tick without adding a tick
+func (m *MultiTimeframe) Snapshot() MultiTimeframeSnapshot {
+	snap := MultiTimeframeSnapshot{Time: m.time, Frames: make(map[string]TimeframeSnapshot, len(m.frames))}
+	for _, f := range m.frames {
+		snap.Frames[f.Name] = f.snapshot(false)
+	}
+
+	return snap
+}
+
+// Reset clears every aggregator and indicator
+func (m *MultiTimeframe) Reset() {
+	for _, f := range m.frames {
+		f.reset()
+	}
+
+	m.time = time.Time{}
+}
@@ -0,0 +1,190 @@
+package technical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMultiTimeframe(t *testing.T) {
+	agg, _ := NewTimeBarAggregator(time.Minute)
+
+	_, err := NewMultiTimeframe(Timeframe{Aggregator: agg})
+	assert.Equal(t, ErrInvalidTimeframe, err)
+
+	_, err = NewMultiTimeframe(Timeframe{Name: "1m"})
+	assert.Equal(t, ErrInvalidTimeframe, err)
+
+	_, err = NewMultiTimeframe(Timeframe{Name: "1m", Aggregator: agg, Indicators: map[string]BarIndicator{"ema": nil}})
+	assert.Equal(t, ErrInvalidTimeframe, err)
+
+	_, err = NewMultiTimeframe(Timeframe{Name: "1m", Aggregator: agg}, Timeframe{Name: "1m", Aggregator: agg})
+	assert.Equal(t, ErrInvalidTimeframe, err)
+}
+
+func TestMultiTimeframe(t *testing.T) {
+	testseries := readMockSeries64("./mock/test_series.txt")
+	ticks := SeriesTicks(testseries, mockSessionTimes(len(testseries)))
+
+	agg1m, _ := NewTimeBarAggregator(time.Minute)
+	agg30m, _ := NewTimeBarAggregator(30 * time.Minute)
+	bb, _ := NewBollingerEMAStream64(20, 0, 2)
+	lr, _ := NewLinRegStream64(20)
+	atr, _ := NewATRStream64(3)
+	ema, _ := NewEMAStream64(3, 0)
+
+	m, err := NewMultiTimeframe(
+		Timeframe{Name: "1m", Aggregator: agg1m, Indicators: map[string]BarIndicator{
+			"bb": BollingerEMABarIndicator(bb),
+			"lr": LinRegBarIndicator(lr, 2),
+		}},
+		Timeframe{Name: "30m", Aggregator: agg30m, Indicators: map[string]BarIndicator{
+			"atr": ATRBarIndicator(atr),
+			"ema": EMABarIndicator(ema),
+		}},
+	)
+	assert.NoError(t, err)
+
+	var (
+		snap   MultiTimeframeSnapshot
+		closed int
+	)
+
+	for _, tk := range ticks {
+		snap = m.Update(tk)
+
+		// no look-ahead, completed bars end at or before the tick and the in progress bar contains it
+		for _, f := range snap.Frames {
+			if f.Bar.Ticks > 0 && f.Bar.End.After(tk.Time) {
+				t.Fatalf("completed bar ends after tick %v", tk.Time)
+			}
+
+			if f.Current.End.Before(tk.Time) || f.Current.Close != tk.Price {
+				t.Fatalf("in progress bar does not contain tick %v", tk.Time)
+			}
+		}
+
+		if snap.Frames["1m"].Closed {
+			closed++
+		}
+	}
+
+	bars1m, _ := TimeBars(ticks, time.Minute)
+	bars30m, _ := TimeBars(ticks, 30*time.Minute)
+	assert.Len(t, bars1m, 390)
+	assert.Len(t, bars30m, 13)
+
+	// the last bar of the session is still in progress
+	assert.Equal(t, 389, closed)
+	assert.Equal(t, ticks[len(ticks)-1].Time, snap.Time)
+
+	f1m := snap.Frames["1m"]
+	assert.Equal(t, bars1m[388], f1m.Bar)
+	assert.Equal(t, bars1m[389], f1m.Current)
+
+	closes := BarCloses(bars1m)
+	assert.True(t, f1m.Completed["bb"].Ready)
+	assert.InDelta(t, StaticBollingerEMA64(closes[:389], 20, 0, 2)[388].Upper, f1m.Completed["bb"].Bound.Upper, 1e-9)
+	assert.InDelta(t, StaticBollingerEMA64(closes, 20, 0, 2)[389].Upper, f1m.InProgress["bb"].Bound.Upper, 1e-9)
+	assert.InDelta(t, StaticLinRegChannel64(closes, 20, 2)[389].Midpoint, f1m.InProgress["lr"].Value, 1e-9)
+
+	f30m := snap.Frames["30m"]
+	assert.InDelta(t, StaticBarATR64(bars30m[:12], 3), f30m.Completed["atr"].Value, 1e-12)
+	assert.InDelta(t, StaticBarATR64(bars30m, 3), f30m.InProgress["atr"].Value, 1e-12)
+	assert.InDelta(t, EwmaSeries64(BarCloses(bars30m), 0.5, 3)[12], f30m.InProgress["ema"].Value, 1e-12)
+
+	// the in progress values do not update the indicators
+	assert.Equal(t, snap, m.Snapshot())
+	assert.InDelta(t, StaticBarATR64(bars30m[:12], 3), atr.Value(), 1e-12)
+
+	m.Reset()
+	snap = m.Snapshot()
+	assert.Equal(t, 0, snap.Frames["1m"].Current.Ticks)
+	assert.False(t, snap.Frames["30m"].Completed["atr"].Ready)
+	assert.False(t, atr.Ready())
+}
+
+func TestStreamClone(t *testing.T) {
+	bb, _ := NewBollingerEMAStream64(3, 0, 2)
+	for _, v := range []float64{1, 3, 2} {
+		bb.Update(v)
+	}
+
+	c := bb.Clone()
+	c.Update(10)
+	assert.NotEqual(t, bb.Bound(), c.Bound())
+
+	bb.Update(10)
+	assert.Equal(t, bb.Bound(), c.Bound())
+
+	lr, _ := NewLinRegStream64(3)
+	lr.Update(1)
+	lc := lr.Clone()
+	lc.Update(2)
+	assert.False(t, lr.Fit() == lc.Fit())
+}
+
+func TestBarIndicatorAdapters(t *testing.T) {
+	bars := mockBars()[:300]
+
+	obv, ad := NewOBVStream64(), NewADLineStream64()
+	cmf, _ := NewChaikinMoneyFlowStream64(20)
+	co, _ := NewChaikinOscillatorStream64(3, 10)
+	mfi, _ := NewMFIStream64(14)
+	vwap, _ := NewVWAPStream64(2)
+	rolling, _ := NewRollingVWAPStream64(20, 2)
+	anchored, _ := NewAnchoredVWAPStream64(2)
+	tema, _ := NewTimeEMAStream64(10 * time.Minute)
+	tboll, _ := NewTimeBollingerStream64(10*time.Minute, 2)
+
+	// the expected value of each adapter from a copy of its stream updated directly
+	adapters := map[string]BarIndicator{
+		"obv":      OBVBarIndicator(obv.Clone()),
+		"ad":       ADLineBarIndicator(ad.Clone()),
+		"cmf":      ChaikinMoneyFlowBarIndicator(cmf.Clone()),
+		"co":       ChaikinOscillatorBarIndicator(co.Clone()),
+		"mfi":      MFIBarIndicator(mfi.Clone()),
+		"vwap":     VWAPBarIndicator(vwap.Clone()),
+		"rolling":  RollingVWAPBarIndicator(rolling.Clone()),
+		"anchored": AnchoredVWAPBarIndicator(anchored.Clone()),
+		"tema":     TimeEMABarIndicator(tema.Clone()),
+		"tboll":    TimeBollingerBarIndicator(tboll.Clone()),
+	}
+
+	for _, b := range bars {
+		for _, s := range []volumeStream64{obv, ad, cmf, co, mfi, vwap, rolling, anchored} {
+			s.UpdateBar(b)
+		}
+
+		tema.Update(b.End, b.Close)
+		tboll.Update(b.End, b.Close)
+		for _, ind := range adapters {
+			ind.UpdateBar(b)
+		}
+	}
+
+	expected := map[string]IndicatorValue{
+		"obv":      {Value: obv.Value(), Ready: obv.Ready()},
+		"ad":       {Value: ad.Value(), Ready: ad.Ready()},
+		"cmf":      {Value: cmf.Value(), Ready: cmf.Ready()},
+		"co":       {Value: co.Value(), Ready: co.Ready()},
+		"mfi":      {Value: mfi.Value(), Ready: mfi.Ready()},
+		"vwap":     {Value: vwap.Value(), Bound: vwap.Bound(), Ready: vwap.Ready()},
+		"rolling":  {Value: rolling.Value(), Bound: rolling.Bound(), Ready: rolling.Ready()},
+		"anchored": {Value: anchored.Value(), Bound: anchored.Bound(), Ready: anchored.Ready()},
+		"tema":     {Value: tema.Value(), Ready: tema.Ready()},
+		"tboll":    {Value: tboll.Bound().Midpoint, Bound: tboll.Bound(), Ready: tboll.Ready()},
+	}
+
+	for name, ind := range adapters {
+		snap := ind.Snapshot()
+		assert.Equal(t, expected[name], snap, name)
+		assert.True(t, snap.Ready, name)
+
+		c := ind.Clone()
+		ind.Reset()
+		assert.False(t, ind.Snapshot().Ready, name)
+		assert.Equal(t, snap, c.Snapshot(), name)
+	}
+}
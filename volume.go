@@ -0,0 +1,735 @@
+package technical
+
+import (
+	"math"
+)
+
+/*
+* Volume indicators weight the price action of bars by their traded volume:
+*
+*	On-Balance Volume (OBV): cumulative volume, added on up closes and subtracted on down closes
+*	Accumulation/Distribution (A/D) line: cumulative volume weighted by the close location within the bar range
+*	Chaikin Money Flow (CMF): A/D volume of the last n bars over their volume, between -1 and 1
+*	Chaikin Oscillator: fast EWMA minus slow EWMA of the A/D line
+*	Money Flow Index (MFI): the RSI of the typical price weighted by volume, between 0 and 100
+*	VWAP: volume weighted average of the typical price, anchored to a session or over the last n bars,
+*	with bands of a multiple of the volume weighted standard deviation
+*
+* The indicators need the volume of bars so their streams are updated with UpdateBar only.
+ */
+
+// moneyFlowMultiplier returns the location of the close within the range of the bar, from -1 at the low to 1 at the high
+// A bar without range has a multiplier of 0.0
+func moneyFlowMultiplier(b Bar) float64 {
+	r := b.High - b.Low
+	if r == 0.0 {
+		return 0.0
+	}
+
+	return ((b.Close - b.Low) - (b.High - b.Close)) / r
+}
+
+// rollingSum64 is the sum of the last values of a stream
+// The sum is exactly 0.0 when all the values in the window are 0.0, e.g. for bars without volume.
+type rollingSum64 struct {
+	w       window64
+	sum     float64
+	nonzero int
+}
+
+func newRollingSum64(size int) rollingSum64 {
+	return rollingSum64{w: newWindow64(size)}
+}
+
+// push adds v as the most recent value of the sum
+func (s *rollingSum64) push(v float64) {
+	if oldest, ok := s.w.push(v); ok {
+		s.sum -= oldest
+		if oldest != 0.0 {
+			s.nonzero--
+		}
+	}
+
+	s.sum += v
+	if v != 0.0 {
+		s.nonzero++
+	}
+
+	if s.nonzero == 0 { // drop the rounding error of the values that left the window
+		s.sum = 0.0
+	}
+}
+
+func (s *rollingSum64) full() bool {
+	return s.w.full()
+}
+
+func (s *rollingSum64) clone() rollingSum64 {
+	c := *s
+	c.w = s.w.clone()
+
+	return c
+}
+
+func (s *rollingSum64) reset() {
+	s.w.reset()
+	s.sum, s.nonzero = 0.0, 0
+}
+
+// OBV64 computes the On-Balance Volume of each bar, see OBVStream64
+func OBV64(bars []Bar) []float64 {
+	s := NewOBVStream64()
+
+	return updateBars(bars, s.UpdateBar, s.Ready, s.Value)
+}
+
+// OBVStream64 computes the On-Balance Volume over a stream of bars
+// The OBV starts at 0.0 on the first bar. The volume of each following bar is added if its close is above
+// the previous close and subtracted if it is below. The zero OBVStream64 is ready to use.
+type OBVStream64 struct {
+	obv   float64
+	close float64
+	n     int
+}
+
+// NewOBVStream64 creates an OBVStream64
+func NewOBVStream64() *OBVStream64 {
+	return &OBVStream64{}
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *OBVStream64) UpdateBar(b Bar) {
+	if s.n > 0 {
+		switch {
+		case b.Close > s.close:
+			s.obv += b.Volume
+		case b.Close < s.close:
+			s.obv -= b.Volume
+		}
+	}
+
+	s.close = b.Close
+	s.n++
+}
+
+// Value returns the current OBV
+func (s *OBVStream64) Value() float64 {
+	return s.obv
+}
+
+// Ready reports whether a bar has been seen
+func (s *OBVStream64) Ready() bool {
+	return s.n > 0
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *OBVStream64) WarmupPeriod() int {
+	return 1
+}
+
+// Reset clears the stream
+func (s *OBVStream64) Reset() {
+	*s = OBVStream64{}
+}
+
+// Clone returns an independent copy of the stream
+func (s *OBVStream64) Clone() *OBVStream64 {
+	c := *s
+	return &c
+}
+
+// ADLine64 computes the Accumulation/Distribution line of each bar, see ADLineStream64
+func ADLine64(bars []Bar) []float64 {
+	s := NewADLineStream64()
+
+	return updateBars(bars, s.UpdateBar, s.Ready, s.Value)
+}
+
+// ADLineStream64 computes the Accumulation/Distribution line over a stream of bars
+// Each bar adds its money flow volume, its volume times ((close - low) - (high - close)) / (high - low).
+// The zero ADLineStream64 is ready to use.
+type ADLineStream64 struct {
+	ad float64
+	n  int
+}
+
+// NewADLineStream64 creates an ADLineStream64
+func NewADLineStream64() *ADLineStream64 {
+	return &ADLineStream64{}
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *ADLineStream64) UpdateBar(b Bar) {
+	s.ad += moneyFlowMultiplier(b) * b.Volume
+	s.n++
+}
+
+// Value returns the current A/D line
+func (s *ADLineStream64) Value() float64 {
+	return s.ad
+}
+
+// Ready reports whether a bar has been seen
+func (s *ADLineStream64) Ready() bool {
+	return s.n > 0
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *ADLineStream64) WarmupPeriod() int {
+	return 1
+}
+
+// Reset clears the stream
+func (s *ADLineStream64) Reset() {
+	*s = ADLineStream64{}
+}
+
+// Clone returns an independent copy of the stream
+func (s *ADLineStream64) Clone() *ADLineStream64 {
+	c := *s
+	return &c
+}
+
+// ChaikinMoneyFlow64 computes the Chaikin Money Flow of each bar, see ChaikinMoneyFlowStream64
+// Values before n bars are 0.0. Returns nil if n <= 0.
+func ChaikinMoneyFlow64(bars []Bar, n int) []float64 {
+	s, err := NewChaikinMoneyFlowStream64(n)
+	if err != nil {
+		return nil
+	}
+
+	return updateBars(bars, s.UpdateBar, s.Ready, s.Value)
+}
+
+// ChaikinMoneyFlowStream64 computes the Chaikin Money Flow over a stream of bars
+// The CMF is the sum of the money flow volume of the last n bars over the sum of their volume,
+// 0.0 for bars without volume.
+type ChaikinMoneyFlowStream64 struct {
+	flows   rollingSum64
+	volumes rollingSum64
+}
+
+// NewChaikinMoneyFlowStream64 creates a ChaikinMoneyFlowStream64 over n bars, typically 20 or 21
+// Returns ErrInvalidPeriods if n <= 0
+func NewChaikinMoneyFlowStream64(n int) (*ChaikinMoneyFlowStream64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	return &ChaikinMoneyFlowStream64{flows: newRollingSum64(n), volumes: newRollingSum64(n)}, nil
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *ChaikinMoneyFlowStream64) UpdateBar(b Bar) {
+	s.flows.push(moneyFlowMultiplier(b) * b.Volume)
+	s.volumes.push(b.Volume)
+}
+
+// Value returns the current CMF
+func (s *ChaikinMoneyFlowStream64) Value() float64 {
+	if s.volumes.sum <= 0.0 {
+		return 0.0
+	}
+
+	return s.flows.sum / s.volumes.sum
+}
+
+// Ready reports whether n bars have been seen
+func (s *ChaikinMoneyFlowStream64) Ready() bool {
+	return s.volumes.full()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *ChaikinMoneyFlowStream64) WarmupPeriod() int {
+	return len(s.volumes.w.buf)
+}
+
+// Reset clears the stream
+func (s *ChaikinMoneyFlowStream64) Reset() {
+	s.flows.reset()
+	s.volumes.reset()
+}
+
+// Clone returns an independent copy of the stream
+func (s *ChaikinMoneyFlowStream64) Clone() *ChaikinMoneyFlowStream64 {
+	return &ChaikinMoneyFlowStream64{flows: s.flows.clone(), volumes: s.volumes.clone()}
+}
+
+// ChaikinOscillator64 computes the Chaikin Oscillator of each bar, see ChaikinOscillatorStream64
+// Values before the slow EWMA is ready are 0.0. Returns nil for invalid lookbacks.
+func ChaikinOscillator64(bars []Bar, fast int, slow int) []float64 {
+	s, err := NewChaikinOscillatorStream64(fast, slow)
+	if err != nil {
+		return nil
+	}
+
+	return updateBars(bars, s.UpdateBar, s.Ready, s.Value)
+}
+
+// ChaikinOscillatorStream64 computes the Chaikin Oscillator over a stream of bars
+// The oscillator is the fast EWMA minus the slow EWMA of the A/D line, with the default smoothing of each lookback.
+type ChaikinOscillatorStream64 struct {
+	ad   ADLineStream64
+	fast EMAStream64
+	slow EMAStream64
+}
+
+// NewChaikinOscillatorStream64 creates a ChaikinOscillatorStream64
+// Returns ErrInvalidLookback if fast <= 0, slow <= 0 or fast >= slow
+//
+// Parameters:
+//
+//	fast: lookback of the fast EWMA, typically 3
+//	slow: lookback of the slow EWMA, typically 10
+func NewChaikinOscillatorStream64(fast int, slow int) (*ChaikinOscillatorStream64, error) {
+	if fast >= slow {
+		return nil, ErrInvalidLookback
+	}
+
+	f, err := NewEMAStream64(fast, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sl, err := NewEMAStream64(slow, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChaikinOscillatorStream64{fast: *f, slow: *sl}, nil
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *ChaikinOscillatorStream64) UpdateBar(b Bar) {
+	s.ad.UpdateBar(b)
+	s.fast.Update(s.ad.Value())
+	s.slow.Update(s.ad.Value())
+}
+
+// Value returns the current oscillator, 0.0 until the stream is ready
+func (s *ChaikinOscillatorStream64) Value() float64 {
+	if !s.Ready() {
+		return 0.0
+	}
+
+	return s.fast.Value() - s.slow.Value()
+}
+
+// ADLine returns the current A/D line
+func (s *ChaikinOscillatorStream64) ADLine() float64 {
+	return s.ad.Value()
+}
+
+// Ready reports whether the slow EWMA is ready
+func (s *ChaikinOscillatorStream64) Ready() bool {
+	return s.slow.Ready()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *ChaikinOscillatorStream64) WarmupPeriod() int {
+	return s.slow.WarmupPeriod()
+}
+
+// Reset clears the stream
+func (s *ChaikinOscillatorStream64) Reset() {
+	s.ad.Reset()
+	s.fast.Reset()
+	s.slow.Reset()
+}
+
+// Clone returns an independent copy of the stream
+func (s *ChaikinOscillatorStream64) Clone() *ChaikinOscillatorStream64 {
+	c := *s
+	return &c
+}
+
+// MFI64 computes the Money Flow Index of each bar, see MFIStream64
+// Values before n + 1 bars are 0.0. Returns nil if n <= 0.
+func MFI64(bars []Bar, n int) []float64 {
+	s, err := NewMFIStream64(n)
+	if err != nil {
+		return nil
+	}
+
+	return updateBars(bars, s.UpdateBar, s.Ready, s.Value)
+}
+
+// MFIStream64 computes the Money Flow Index over a stream of bars
+// The raw money flow of a bar is its typical price times its volume, positive if the typical price is above
+// the previous typical price and negative if below. The MFI of the last n money flows is
+// 100 - 100 / (1 + positive flow / negative flow), 100 without negative flow and 50 without any flow, as the RSI.
+type MFIStream64 struct {
+	positive rollingSum64
+	negative rollingSum64
+	tp       float64
+	n        int
+}
+
+// NewMFIStream64 creates an MFIStream64 of n money flows, typically 14
+// Returns ErrInvalidPeriods if n <= 0
+func NewMFIStream64(n int) (*MFIStream64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	return &MFIStream64{positive: newRollingSum64(n), negative: newRollingSum64(n)}, nil
+}
+
+// UpdateBar adds the next bar of the stream
+func (s *MFIStream64) UpdateBar(b Bar) {
+	tp := b.TypicalPrice()
+	if s.n > 0 {
+		flow := tp * b.Volume
+		switch {
+		case tp > s.tp:
+			s.positive.push(flow)
+			s.negative.push(0.0)
+		case tp < s.tp:
+			s.positive.push(0.0)
+			s.negative.push(flow)
+		default:
+			s.positive.push(0.0)
+			s.negative.push(0.0)
+		}
+	}
+
+	s.tp = tp
+	s.n++
+}
+
+// Value returns the current MFI, 0.0 until the stream is ready
+func (s *MFIStream64) Value() float64 {
+	if !s.Ready() {
+		return 0.0
+	}
+
+	return rsiFromAverages64(s.positive.sum, s.negative.sum)
+}
+
+// Ready reports whether n money flows, n + 1 bars, have been seen
+func (s *MFIStream64) Ready() bool {
+	return s.positive.full()
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *MFIStream64) WarmupPeriod() int {
+	return len(s.positive.w.buf) + 1
+}
+
+// Reset clears the stream
+func (s *MFIStream64) Reset() {
+	s.positive.reset()
+	s.negative.reset()
+	s.tp, s.n = 0.0, 0
+}
+
+// Clone returns an independent copy of the stream
+func (s *MFIStream64) Clone() *MFIStream64 {
+	c := *s
+	c.positive = s.positive.clone()
+	c.negative = s.negative.clone()
+
+	return &c
+}
+
+// VWAP64 computes the VWAP and its bands of each bar anchored to the sessions of cal, see VWAPStream64
+// The VWAP restarts on the first bar of each session, bars outside of every session have an empty bound.
+// With a nil cal the VWAP is anchored to the first bar. Returns nil if a < 0.
+//
+// Parameters:
+//
+//	bars: bars placed in their session by their start
+//	cal: calendar of the sessions, or nil
+//	a: multiplier on the volume weighted standard deviation of the bands
+func VWAP64(bars []Bar, cal *Calendar, a float64) []Bound64 {
+	s, err := NewVWAPStream64(a)
+	if err != nil {
+		return nil
+	}
+
+	var guard *SessionGuard
+	if cal != nil {
+		guard = NewSessionGuard(cal, SessionReset, s)
+	}
+
+	bounds := make([]Bound64, len(bars))
+	for i, b := range bars {
+		if guard != nil && !guard.ObserveBar(b) {
+			continue
+		}
+
+		s.UpdateBar(b)
+		if s.Ready() {
+			bounds[i] = s.Bound()
+		}
+	}
+
+	return bounds
+}
+
+// VWAPStream64 computes the Volume Weighted Average Price over a stream of bars since it was created or reset
+// The price of a bar is its typical price (high + low + close) / 3. The bands are a multiple of the volume
+// weighted standard deviation of the prices from the VWAP. Use a SessionGuard to anchor it to each session.
+type VWAPStream64 struct {
+	volume float64
+	mean   float64
+	m2     float64 // volume weighted sum of squared differences from the mean
+	a      float64
+}
+
+// NewVWAPStream64 creates a VWAPStream64 with bands a standard deviations from the VWAP
+// Returns ErrInvalidMultiplier if a < 0
+func NewVWAPStream64(a float64) (*VWAPStream64, error) {
+	if err := checkMultiplier(a); err != nil {
+		return nil, err
+	}
+
+	return &VWAPStream64{a: a}, nil
+}
+
+// UpdateBar adds the next bar of the stream, bars without volume do not move the VWAP
+func (s *VWAPStream64) UpdateBar(b Bar) {
+	s.update(b.TypicalPrice(), b.Volume)
+}
+
+// update adds price p traded with volume v
+func (s *VWAPStream64) update(p float64, v float64) {
+	if !(v > 0.0) {
+		return
+	}
+
+	// weighted welford update
+	s.volume += v
+	delta := p - s.mean
+	s.mean += delta * v / s.volume
+	s.m2 += v * delta * (p - s.mean)
+}
+
+// Bound returns the VWAP as the Midpoint and the bands as the Upper and Lower bounds
+func (s *VWAPStream64) Bound() Bound64 {
+	return vwapBound(s.mean, s.variance(), s.a)
+}
+
+// vwapBound returns the bound of a VWAP with bands a standard deviations away
+func vwapBound(vwap float64, variance float64, a float64) Bound64 {
+	sd := math.Sqrt(math.Max(variance, 0.0))
+
+	return Bound64{Upper: vwap + a*sd, Midpoint: vwap, Lower: vwap - a*sd}
+}
+
+// variance returns the volume weighted variance of the prices
+func (s *VWAPStream64) variance() float64 {
+	if s.volume == 0.0 {
+		return 0.0
+	}
+
+	return s.m2 / s.volume
+}
+
+// Value returns the current VWAP, 0.0 until the stream is ready
+func (s *VWAPStream64) Value() float64 {
+	return s.mean
+}
+
+// StdDev returns the volume weighted standard deviation of the prices from the VWAP
+func (s *VWAPStream64) StdDev() float64 {
+	return math.Sqrt(s.variance())
+}
+
+// Volume returns the volume since the anchor
+func (s *VWAPStream64) Volume() float64 {
+	return s.volume
+}
+
+// Ready reports whether a bar with volume has been seen
+func (s *VWAPStream64) Ready() bool {
+	return s.volume > 0.0
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *VWAPStream64) WarmupPeriod() int {
+	return 1
+}
+
+// Reset clears the stream, anchoring the VWAP to the next bar
+func (s *VWAPStream64) Reset() {
+	s.volume, s.mean, s.m2 = 0.0, 0.0, 0.0
+}
+
+// AdjustGap shifts the VWAP by gap as if every price seen so far had been gap higher, the bands keep their width
+func (s *VWAPStream64) AdjustGap(gap float64) {
+	if s.volume > 0.0 {
+		s.mean += gap
+	}
+}
+
+// Clone returns an independent copy of the stream
+func (s *VWAPStream64) Clone() *VWAPStream64 {
+	c := *s
+	return &c
+}
+
+// RollingVWAP64 computes the VWAP and its bands of the last n bars of each bar, see RollingVWAPStream64
+// Bounds before n bars are empty. Returns nil if n <= 0 or a < 0.
+func RollingVWAP64(bars []Bar, n int, a float64) []Bound64 {
+	s, err := NewRollingVWAPStream64(n, a)
+	if err != nil {
+		return nil
+	}
+
+	bounds := make([]Bound64, len(bars))
+	for i, b := range bars {
+		s.UpdateBar(b)
+		if s.Ready() {
+			bounds[i] = s.Bound()
+		}
+	}
+
+	return bounds
+}
+
+// RollingVWAPStream64 computes the VWAP of the last n bars over a stream of bars, see VWAPStream64
+// The VWAP of bars without volume is 0.0. The mean and variance use a sliding weighted Welford update,
+// recomputed from the window once per full rotation to keep the floating point drift bounded.
+type RollingVWAPStream64 struct {
+	prices  []float64
+	volumes []float64 // volume of each bar, 0.0 for bars without volume
+	head    int       // index of the oldest bar once full
+	n       int       // number of bars in the window
+	traded  int       // number of bars in the window with volume
+	volume  float64
+	mean    float64
+	m2      float64 // volume weighted sum of squared differences from the mean
+	a       float64
+}
+
+// NewRollingVWAPStream64 creates a RollingVWAPStream64 of n bars with bands a standard deviations from the VWAP
+// Returns ErrInvalidPeriods if n <= 0 and ErrInvalidMultiplier if a < 0
+func NewRollingVWAPStream64(n int, a float64) (*RollingVWAPStream64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	if err := checkMultiplier(a); err != nil {
+		return nil, err
+	}
+
+	return &RollingVWAPStream64{prices: make([]float64, n), volumes: make([]float64, n), a: a}, nil
+}
+
+// UpdateBar adds the next bar of the stream, bars without volume do not move the VWAP
+func (s *RollingVWAPStream64) UpdateBar(b Bar) {
+	p, v := b.TypicalPrice(), b.Volume
+	if !(v > 0.0) {
+		v = 0.0
+	}
+
+	size := len(s.prices)
+	i := s.n
+	if s.n == size {
+		i = s.head
+		s.remove(s.prices[i], s.volumes[i])
+		s.head = (s.head + 1) % size
+	} else {
+		s.n++
+	}
+
+	s.prices[i], s.volumes[i] = p, v
+	s.add(p, v)
+
+	if s.n == size && s.head == 0 {
+		s.resum()
+	}
+}
+
+// add adds price p traded with volume v, a weighted welford update
+func (s *RollingVWAPStream64) add(p float64, v float64) {
+	if v == 0.0 {
+		return
+	}
+
+	s.traded++
+	s.volume += v
+	delta := p - s.mean
+	s.mean += delta * v / s.volume
+	s.m2 += v * delta * (p - s.mean)
+}
+
+// remove removes price p traded with volume v, the inverse of add
+func (s *RollingVWAPStream64) remove(p float64, v float64) {
+	if v == 0.0 {
+		return
+	}
+
+	s.traded--
+	if s.traded == 0 { // drop the rounding error of the bars that left the window
+		s.volume, s.mean, s.m2 = 0.0, 0.0, 0.0
+		return
+	}
+
+	rest := s.volume - v
+	delta := p - s.mean
+	mean := s.mean - delta*v/rest
+	s.m2 = math.Max(s.m2-v*delta*(p-mean), 0.0)
+	s.mean, s.volume = mean, rest
+}
+
+// resum recomputes the mean and variance from the window values
+func (s *RollingVWAPStream64) resum() {
+	s.traded, s.volume, s.mean, s.m2 = 0, 0.0, 0.0, 0.0
+	for i := 0; i < s.n; i++ {
+		s.add(s.prices[i], s.volumes[i])
+	}
+}
+
+// Bound returns the VWAP as the Midpoint and the bands as the Upper and Lower bounds
+func (s *RollingVWAPStream64) Bound() Bound64 {
+	if s.traded == 0 {
+		return Bound64{}
+	}
+
+	return vwapBound(s.mean, s.m2/s.volume, s.a)
+}
+
+// Value returns the current VWAP
+func (s *RollingVWAPStream64) Value() float64 {
+	return s.Bound().Midpoint
+}
+
+// Ready reports whether n bars have been seen
+func (s *RollingVWAPStream64) Ready() bool {
+	return s.n == len(s.prices)
+}
+
+// WarmupPeriod returns the number of bars before the stream is ready
+func (s *RollingVWAPStream64) WarmupPeriod() int {
+	return len(s.prices)
+}
+
+// Reset clears the stream
+func (s *RollingVWAPStream64) Reset() {
+	s.head, s.n, s.traded = 0, 0, 0
+	s.volume, s.mean, s.m2 = 0.0, 0.0, 0.0
+}
+
+// Clone returns an independent copy of the stream
+func (s *RollingVWAPStream64) Clone() *RollingVWAPStream64 {
+	c := *s
+	c.prices = append([]float64(nil), s.prices...)
+	c.volumes = append([]float64(nil), s.volumes...)
+
+	return &c
+}
+
+// updateBars updates a stream with every bar and returns its value after each, 0.0 before it is ready
+func updateBars(bars []Bar, update func(Bar), ready func() bool, value func() float64) []float64 {
+	values := make([]float64, len(bars))
+	for i, b := range bars {
+		update(b)
+		if ready() {
+			values[i] = value()
+		}
+	}
+
+	return values
+}
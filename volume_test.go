@@ -0,0 +1,312 @@
+package technical
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockVolumeBars returns the 1 minute bars of the mock series with the tick sizes of the mock volumes
+func mockVolumeBars() []Bar {
+	series := readMockSeries64("mock/test_series.txt")
+	volumes := readMockSeries64("mock/test_volume.txt")
+	times := mockSessionTimes(len(series))
+
+	ticks := make([]Tick, len(series))
+	for i, v := range series {
+		ticks[i] = Tick{Time: times[i], Price: v, Size: volumes[i]}
+	}
+
+	bars, _ := TimeBars(ticks, time.Minute)
+
+	return bars
+}
+
+// volumeBars are 5 bars up, down, up and unchanged, the last without volume
+func volumeBars() []Bar {
+	bars := testBars(
+		[4]float64{10, 11, 9, 10.5},
+		[4]float64{10.5, 12, 10, 12},
+		[4]float64{12, 12, 10, 10},
+		[4]float64{11, 11, 11, 11},
+		[4]float64{11, 12, 10, 11},
+	)
+
+	for i, v := range []float64{100, 200, 300, 400, 0} {
+		bars[i].Volume = v
+	}
+
+	return bars
+}
+
+// vwapOf computes the VWAP bound of bars directly
+func vwapOf(bars []Bar, a float64) Bound64 {
+	var pv, v float64
+	for _, b := range bars {
+		pv += b.TypicalPrice() * b.Volume
+		v += b.Volume
+	}
+
+	vwap := pv / v
+
+	var ss float64
+	for _, b := range bars {
+		ss += b.Volume * math.Pow(b.TypicalPrice()-vwap, 2)
+	}
+
+	sd := math.Sqrt(ss / v)
+
+	return Bound64{Upper: vwap + a*sd, Midpoint: vwap, Lower: vwap - a*sd}
+}
+
+func assertBoundInDelta(t *testing.T, expected Bound64, actual Bound64, delta float64) {
+	assert.InDelta(t, expected.Upper, actual.Upper, delta)
+	assert.InDelta(t, expected.Midpoint, actual.Midpoint, delta)
+	assert.InDelta(t, expected.Lower, actual.Lower, delta)
+}
+
+func TestOBV(t *testing.T) {
+	assert.Equal(t, []float64{0, 200, -100, 300, 300}, OBV64(volumeBars()))
+	assert.Empty(t, OBV64(nil))
+
+	s := NewOBVStream64()
+	for _, b := range volumeBars() {
+		s.UpdateBar(b)
+	}
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, 300.0, c.Value())
+}
+
+func TestADLine(t *testing.T) {
+	// money flow multipliers 0.5, 1, -1, 0 (no range) and 0 (no volume)
+	assert.Equal(t, []float64{50, 250, -50, -50, -50}, ADLine64(volumeBars()))
+
+	s := NewADLineStream64()
+	s.UpdateBar(volumeBars()[0])
+	assert.Equal(t, 1, s.WarmupPeriod())
+	assert.Equal(t, 50.0, s.Clone().Value())
+}
+
+func TestChaikinMoneyFlow(t *testing.T) {
+	assert.Nil(t, ChaikinMoneyFlow64(volumeBars(), 0))
+
+	cmf := ChaikinMoneyFlow64(volumeBars(), 2)
+	assert.Equal(t, 0.0, cmf[0])
+	assert.InDelta(t, 250.0/300.0, cmf[1], 1e-12)
+	assert.InDelta(t, -100.0/500.0, cmf[2], 1e-12)
+	assert.InDelta(t, -300.0/700.0, cmf[3], 1e-12)
+	assert.Equal(t, 0.0, cmf[4])
+
+	// bars without volume
+	s, _ := NewChaikinMoneyFlowStream64(2)
+	for _, b := range testBars([4]float64{10, 11, 9, 10}, [4]float64{10, 11, 9, 11}) {
+		s.UpdateBar(b)
+	}
+
+	assert.True(t, s.Ready())
+	assert.Equal(t, 0.0, s.Value())
+	assert.Equal(t, 2, s.WarmupPeriod())
+}
+
+func TestChaikinOscillator(t *testing.T) {
+	_, err := NewChaikinOscillatorStream64(10, 3)
+	assert.Equal(t, ErrInvalidLookback, err)
+	_, err = NewChaikinOscillatorStream64(0, 3)
+	assert.Equal(t, ErrInvalidLookback, err)
+	assert.Nil(t, ChaikinOscillator64(volumeBars(), 3, 3))
+
+	bars := mockVolumeBars()
+	osc := ChaikinOscillator64(bars, 3, 10)
+	assert.Equal(t, make([]float64, 9), osc[:9])
+
+	fast, _ := NewEMAStream64(3, 0)
+	slow, _ := NewEMAStream64(10, 0)
+	for i, ad := range ADLine64(bars) {
+		fast.Update(ad)
+		slow.Update(ad)
+		if i >= 9 {
+			assert.InDelta(t, fast.Value()-slow.Value(), osc[i], 1e-9)
+		}
+	}
+
+	s, _ := NewChaikinOscillatorStream64(3, 10)
+	for _, b := range bars {
+		s.UpdateBar(b)
+	}
+
+	assert.Equal(t, 10, s.WarmupPeriod())
+	assert.Equal(t, ADLine64(bars)[len(bars)-1], s.ADLine())
+	c := s.Clone()
+	s.Reset()
+	assert.Equal(t, 0.0, s.Value())
+	assert.Equal(t, osc[len(osc)-1], c.Value())
+}
+
+func TestMFI(t *testing.T) {
+	assert.Nil(t, MFI64(volumeBars(), 0))
+
+	// money flows of +200 * 34/3, -300 * 32/3, +400 * 11 and 0 for an unchanged typical price
+	up, down, up2 := 200*34.0/3.0, 300*32.0/3.0, 400*11.0
+	mfi := MFI64(volumeBars(), 2)
+	assert.Equal(t, []float64{0, 0}, mfi[:2])
+	assert.InDelta(t, 100.0-100.0/(1.0+up/down), mfi[2], 1e-12)
+	assert.InDelta(t, 100.0-100.0/(1.0+up2/down), mfi[3], 1e-12)
+	assert.Equal(t, 100.0, mfi[4])
+
+	s, _ := NewMFIStream64(14)
+	assert.Equal(t, 15, s.WarmupPeriod())
+	for _, v := range mfiOfMock(s) {
+		assert.True(t, v >= 0.0 && v <= 100.0)
+	}
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.True(t, c.Ready())
+}
+
+// mfiOfMock updates s with the mock volume bars and returns the ready values
+func mfiOfMock(s *MFIStream64) []float64 {
+	var values []float64
+	for _, b := range mockVolumeBars() {
+		s.UpdateBar(b)
+		if s.Ready() {
+			values = append(values, s.Value())
+		}
+	}
+
+	return values
+}
+
+func TestVWAP(t *testing.T) {
+	bars := volumeBars()
+
+	assert.Nil(t, VWAP64(bars, nil, -1))
+
+	bounds := VWAP64(bars, nil, 2)
+	for i := range bars {
+		assertBoundInDelta(t, vwapOf(bars[:i+1], 2), bounds[i], 1e-12)
+	}
+
+	// the bar without volume does not move the VWAP
+	assert.Equal(t, bounds[3], bounds[4])
+
+	s, _ := NewVWAPStream64(2)
+	assert.False(t, s.Ready())
+	for _, b := range bars {
+		s.UpdateBar(b)
+	}
+
+	assert.Equal(t, 1000.0, s.Volume())
+	assert.Equal(t, bounds[4].Midpoint, s.Value())
+	assert.InDelta(t, (bounds[4].Upper-bounds[4].Midpoint)/2, s.StdDev(), 1e-12)
+
+	c := s.Clone()
+	c.AdjustGap(-1)
+	assert.InDelta(t, s.Value()-1, c.Value(), 1e-12)
+	assert.InDelta(t, s.StdDev(), c.StdDev(), 1e-12)
+
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.Equal(t, Bound64{}, s.Bound())
+}
+
+func TestSessionVWAP(t *testing.T) {
+	cal, _ := NewCalendar(time.UTC, 9*time.Hour+30*time.Minute, 16*time.Hour)
+
+	// 3 bars on friday, 1 on saturday and 2 on monday
+	bars := volumeBars()
+	bars = append(bars, bars[0])
+	for i, d := range []int{0, 0, 0, 1, 3, 3} {
+		start := mockSessionOpen.AddDate(0, 0, d).Add(time.Duration(i) * time.Minute)
+		bars[i].Start, bars[i].End = start, start.Add(time.Minute)
+	}
+
+	bounds := VWAP64(bars, cal, 1)
+	assertBoundInDelta(t, vwapOf(bars[:3], 1), bounds[2], 1e-12)
+	assert.Equal(t, Bound64{}, bounds[3])
+
+	// monday restarts from its first bar without volume
+	assert.Equal(t, Bound64{}, bounds[4])
+	assertBoundInDelta(t, vwapOf(bars[5:], 1), bounds[5], 1e-12)
+}
+
+func TestRollingVWAP(t *testing.T) {
+	bars := mockVolumeBars()
+
+	assert.Nil(t, RollingVWAP64(bars, 0, 2))
+	assert.Nil(t, RollingVWAP64(bars, 20, -2))
+
+	bounds := RollingVWAP64(bars, 20, 2)
+	assert.Equal(t, Bound64{}, bounds[18])
+	for i := 19; i < len(bars); i++ {
+		assertBoundInDelta(t, vwapOf(bars[i-19:i+1], 2), bounds[i], 1e-6)
+		assert.True(t, bounds[i].Lower <= bounds[i].Midpoint)
+	}
+
+	// the anchored VWAP of the session is the rolling VWAP of every bar
+	anchored := VWAP64(bars, nil, 2)
+	all := RollingVWAP64(bars, len(bars), 2)
+	assertBoundInDelta(t, anchored[len(bars)-1], all[len(bars)-1], 1e-6)
+
+	// a window without volume
+	s, _ := NewRollingVWAPStream64(2, 1)
+	for _, b := range volumeBars()[3:] {
+		s.UpdateBar(b)
+	}
+
+	assert.Equal(t, 11.0, s.Value())
+	s.UpdateBar(volumeBars()[4])
+	assert.Equal(t, 0.0, s.Value())
+	assert.Equal(t, 2, s.WarmupPeriod())
+
+	c := s.Clone()
+	s.Reset()
+	assert.False(t, s.Ready())
+	assert.True(t, c.Ready())
+}
+
+func TestRollingVWAPDrift(t *testing.T) {
+	// prices near 1e6 with a small spread, where sums of p^2 * volume cancel
+	bars := make([]Bar, 5000)
+	for i := range bars {
+		p := 1e6 + 0.01*float64(i%7) - 0.003*float64(i%11)
+		bars[i] = Bar{High: p, Low: p, Close: p, Volume: 1.0 + float64(i%5)*1000.0}
+	}
+
+	s, _ := NewRollingVWAPStream64(30, 2)
+	for i, b := range bars {
+		s.UpdateBar(b)
+		if i >= 29 && i%97 == 0 {
+			assertBoundInDelta(t, vwapOf(bars[i-29:i+1], 2), s.Bound(), 1e-9)
+		}
+	}
+
+	assertBoundInDelta(t, vwapOf(bars[len(bars)-30:], 2), s.Bound(), 1e-9)
+}
+
+func TestMockVolume(t *testing.T) {
+	bars := mockVolumeBars()
+
+	for _, v := range ChaikinMoneyFlow64(bars, 20) {
+		assert.True(t, v >= -1.0 && v <= 1.0)
+	}
+
+	// the streams match their series
+	obv, cmf := OBV64(bars), ChaikinMoneyFlow64(bars, 20)
+	s, _ := NewChaikinMoneyFlowStream64(20)
+	o := NewOBVStream64()
+	for i, b := range bars {
+		s.UpdateBar(b)
+		o.UpdateBar(b)
+		assert.Equal(t, obv[i], o.Value())
+		if i >= 19 {
+			assert.Equal(t, cmf[i], s.Value())
+		}
+	}
+}
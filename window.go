@@ -0,0 +1,450 @@
+package technical
+
+import (
+	"math"
+)
+
+// window64 is a fixed size sliding window of the most recent values of a stream
+// It tracks the mean and population variance of its values in O(1) per push.
+type window64 struct {
+	buf  []float64
+	head int // index of the oldest value in buf once full
+	n    int // number of values currently in the window
+	mean float64
+	m2   float64 // sum of squared differences from the mean
+}
+
+func newWindow64(size int) window64 {
+	if size <= 0 {
+		size = 1
+	}
+
+	return window64{buf: make([]float64, size)}
+}
+
+// push adds v as the most recent value of the window
+// If the window is full the oldest value is dropped and returned with ok true
+func (w *window64) push(v float64) (oldest float64, ok bool) {
+	size := len(w.buf)
+
+	if w.n < size {
+		// welford's online update
+		w.buf[w.n] = v
+		w.n++
+
+		delta := v - w.mean
+		w.mean += delta / float64(w.n)
+		w.m2 += delta * (v - w.mean)
+
+		return 0.0, false
+	}
+
+	oldest = w.buf[w.head]
+	w.buf[w.head] = v
+	w.head = (w.head + 1) % size
+
+	// sliding welford update replacing oldest with v
+	lastMean := w.mean
+	w.mean += (v - oldest) / float64(size)
+	w.m2 += (v - oldest) * (v - w.mean + oldest - lastMean)
+	if w.m2 < 0.0 { // rounding
+		w.m2 = 0.0
+	}
+
+	return oldest, true
+}
+
+// full reports whether the window holds size values
+func (w *window64) full() bool {
+	return w.n == len(w.buf)
+}
+
+// len returns the number of values in the window
+func (w *window64) len() int {
+	return w.n
+}
+
+// at returns the ith oldest value of the window
+func (w *window64) at(i int) float64 {
+	return w.buf[(w.head+i)%len(w.buf)]
+}
+
+// last returns the most recent value of the window
+func (w *window64) last() float64 {
+	if w.n == 0 {
+		return 0.0
+	}
+
+	return w.at(w.n - 1)
+}
+
+// variance returns the population variance of the window values
+func (w *window64) variance() float64 {
+	if w.n == 0 {
+		return 0.0
+	}
+
+	return w.m2 / float64(w.n)
+}
+
+// stdDev returns the population standard deviation of the window values
+func (w *window64) stdDev() float64 {
+	return math.Sqrt(w.variance())
+}
+
+// shift adds d to every value of the window, the variance is unchanged
+func (w *window64) shift(d float64) {
+	for i := 0; i < w.n; i++ {
+		w.buf[(w.head+i)%len(w.buf)] += d
+	}
+
+	if w.n > 0 {
+		w.mean += d
+	}
+}
+
+// clone returns a deep copy of the window
+func (w *window64) clone() window64 {
+	c := *w
+	c.buf = append([]float64(nil), w.buf...)
+
+	return c
+}
+
+func (w *window64) reset() {
+	for i := range w.buf {
+		w.buf[i] = 0.0
+	}
+
+	w.head, w.n = 0, 0
+	w.mean, w.m2 = 0.0, 0.0
+}
+
+// extremeWindow64 tracks the largest or smallest of the most recent size values of a stream
+// It keeps a monotonic deque of the values that can still become the extreme, so a push is amortized O(1)
+// and the extreme is O(1), instead of scanning the window.
+type extremeWindow64 struct {
+	idx     []int // stream index of each deque value
+	val     []float64
+	head    int // index of the front of the deque, the current extreme
+	len     int // number of values in the deque
+	n       int // number of values pushed
+	greater bool
+}
+
+// newMaxWindow64 creates an extremeWindow64 of the largest value
+func newMaxWindow64(size int) extremeWindow64 {
+	return newExtremeWindow64(size, true)
+}
+
+// newMinWindow64 creates an extremeWindow64 of the smallest value
+func newMinWindow64(size int) extremeWindow64 {
+	return newExtremeWindow64(size, false)
+}
+
+func newExtremeWindow64(size int, greater bool) extremeWindow64 {
+	if size <= 0 {
+		size = 1
+	}
+
+	return extremeWindow64{idx: make([]int, size), val: make([]float64, size), greater: greater}
+}
+
+// push adds v as the most recent value of the window
+func (w *extremeWindow64) push(v float64) {
+	size := len(w.val)
+
+	// drop the front once it leaves the window
+	if w.len > 0 && w.idx[w.head] <= w.n-size {
+		w.head = (w.head + 1) % size
+		w.len--
+	}
+
+	// drop the values at the back v dominates, they can no longer be the extreme
+	for w.len > 0 {
+		back := (w.head + w.len - 1) % size
+		if (w.greater && w.val[back] > v) || (!w.greater && w.val[back] < v) {
+			break
+		}
+
+		w.len--
+	}
+
+	back := (w.head + w.len) % size
+	w.idx[back], w.val[back] = w.n, v
+	w.len++
+	w.n++
+}
+
+// full reports whether size values have been pushed
+func (w *extremeWindow64) full() bool {
+	return w.n >= len(w.val)
+}
+
+// value returns the extreme of the window, 0.0 if empty
+func (w *extremeWindow64) value() float64 {
+	if w.len == 0 {
+		return 0.0
+	}
+
+	return w.val[w.head]
+}
+
+// age returns the number of values pushed after the extreme, 0 if it is the most recent value
+func (w *extremeWindow64) age() int {
+	if w.len == 0 {
+		return 0
+	}
+
+	return w.n - 1 - w.idx[w.head]
+}
+
+// size returns the size of the window
+func (w *extremeWindow64) size() int {
+	return len(w.val)
+}
+
+// clone returns a deep copy of the window
+func (w *extremeWindow64) clone() extremeWindow64 {
+	c := *w
+	c.idx = append([]int(nil), w.idx...)
+	c.val = append([]float64(nil), w.val...)
+
+	return c
+}
+
+func (w *extremeWindow64) reset() {
+	w.head, w.len, w.n = 0, 0, 0
+}
+
+// sortedWindowSeed is the initial state of the priorities of a sortedWindow64
+const sortedWindowSeed = 2463534242
+
+// sortedWindow64 is a fixed size sliding window of the most recent values of a stream ordered by value
+// It keeps the values in a treap, a binary search tree balanced by random priorities, with the count and sum
+// of each subtree, so a push and the sum of the absolute deviations from a value are O(log n) expected.
+// The nodes of the tree are the slots of the ring buffer, and equal values are ordered by slot.
+type sortedWindow64 struct {
+	val   []float64 // value of each slot
+	left  []int     // left child of each slot, -1 if none
+	right []int     // right child of each slot, -1 if none
+	prio  []uint32
+	cnt   []int     // number of values in the subtree of each slot
+	sum   []float64 // sum of the values in the subtree of each slot
+	root  int
+	head  int // slot of the oldest value once full
+	n     int // number of values currently in the window
+	seed  uint32
+}
+
+func newSortedWindow64(size int) sortedWindow64 {
+	if size <= 0 {
+		size = 1
+	}
+
+	return sortedWindow64{
+		val:   make([]float64, size),
+		left:  make([]int, size),
+		right: make([]int, size),
+		prio:  make([]uint32, size),
+		cnt:   make([]int, size),
+		sum:   make([]float64, size),
+		root:  -1,
+		seed:  sortedWindowSeed,
+	}
+}
+
+// push adds v as the most recent value of the window, dropping the oldest value if the window is full
+func (w *sortedWindow64) push(v float64) {
+	slot := w.n
+	if w.n == len(w.val) {
+		slot = w.head
+		w.root = w.erase(w.root, slot)
+		w.head = (w.head + 1) % len(w.val)
+	} else {
+		w.n++
+	}
+
+	w.val[slot] = v
+	w.left[slot], w.right[slot] = -1, -1
+	w.prio[slot] = w.random()
+	w.update(slot)
+
+	l, r := w.split(w.root, slot)
+	w.root = w.merge(w.merge(l, slot), r)
+}
+
+// full reports whether the window holds size values
+func (w *sortedWindow64) full() bool {
+	return w.n == len(w.val)
+}
+
+// size returns the size of the window
+func (w *sortedWindow64) size() int {
+	return len(w.val)
+}
+
+// mean returns the mean of the window values, 0.0 if empty
+func (w *sortedWindow64) mean() float64 {
+	if w.n == 0 {
+		return 0.0
+	}
+
+	return w.sum[w.root] / float64(w.n)
+}
+
+// absDev returns the sum of the absolute deviations of the window values from m
+// The values below m deviate by m * count - sum and the others by sum - m * count.
+func (w *sortedWindow64) absDev(m float64) float64 {
+	if w.n == 0 {
+		return 0.0
+	}
+
+	count, sum := 0, 0.0
+	for t := w.root; t >= 0; {
+		if w.val[t] < m {
+			count += 1 + w.count(w.left[t])
+			sum += w.val[t] + w.total(w.left[t])
+			t = w.right[t]
+		} else {
+			t = w.left[t]
+		}
+	}
+
+	d := w.sum[w.root] - 2.0*sum + m*float64(2*count-w.n)
+	if d < 0.0 { // rounding
+		d = 0.0
+	}
+
+	return d
+}
+
+// less reports whether slot a is before slot b in the tree, by value then by slot with NaN values last
+func (w *sortedWindow64) less(a int, b int) bool {
+	x, y := w.val[a], w.val[b]
+	if nx, ny := math.IsNaN(x), math.IsNaN(y); nx || ny {
+		if nx != ny {
+			return ny
+		}
+	} else if x != y {
+		return x < y
+	}
+
+	return a < b
+}
+
+// split splits the tree t into the slots before slot k and the others
+func (w *sortedWindow64) split(t int, k int) (int, int) {
+	if t < 0 {
+		return -1, -1
+	}
+
+	if w.less(t, k) {
+		l, r := w.split(w.right[t], k)
+		w.right[t] = l
+		w.update(t)
+
+		return t, r
+	}
+
+	l, r := w.split(w.left[t], k)
+	w.left[t] = r
+	w.update(t)
+
+	return l, t
+}
+
+// merge joins the trees a and b, all slots of a before those of b
+func (w *sortedWindow64) merge(a int, b int) int {
+	if a < 0 {
+		return b
+	}
+
+	if b < 0 {
+		return a
+	}
+
+	if w.prio[a] > w.prio[b] {
+		w.right[a] = w.merge(w.right[a], b)
+		w.update(a)
+
+		return a
+	}
+
+	w.left[b] = w.merge(a, w.left[b])
+	w.update(b)
+
+	return b
+}
+
+// erase removes slot k from the tree t
+func (w *sortedWindow64) erase(t int, k int) int {
+	if t < 0 {
+		return -1
+	}
+
+	if t == k {
+		return w.merge(w.left[t], w.right[t])
+	}
+
+	if w.less(k, t) {
+		w.left[t] = w.erase(w.left[t], k)
+	} else {
+		w.right[t] = w.erase(w.right[t], k)
+	}
+
+	w.update(t)
+
+	return t
+}
+
+// update recomputes the count and sum of the subtree of slot t from its children
+func (w *sortedWindow64) update(t int) {
+	w.cnt[t] = 1 + w.count(w.left[t]) + w.count(w.right[t])
+	w.sum[t] = w.val[t] + w.total(w.left[t]) + w.total(w.right[t])
+}
+
+// count returns the number of values in the tree t
+func (w *sortedWindow64) count(t int) int {
+	if t < 0 {
+		return 0
+	}
+
+	return w.cnt[t]
+}
+
+// total returns the sum of the values in the tree t
+func (w *sortedWindow64) total(t int) float64 {
+	if t < 0 {
+		return 0.0
+	}
+
+	return w.sum[t]
+}
+
+// random returns the next priority of a xorshift generator
+func (w *sortedWindow64) random() uint32 {
+	w.seed ^= w.seed << 13
+	w.seed ^= w.seed >> 17
+	w.seed ^= w.seed << 5
+
+	return w.seed
+}
+
+// clone returns a deep copy of the window
+func (w *sortedWindow64) clone() sortedWindow64 {
+	c := *w
+	c.val = append([]float64(nil), w.val...)
+	c.left = append([]int(nil), w.left...)
+	c.right = append([]int(nil), w.right...)
+	c.prio = append([]uint32(nil), w.prio...)
+	c.cnt = append([]int(nil), w.cnt...)
+	c.sum = append([]float64(nil), w.sum...)
+
+	return c
+}
+
+func (w *sortedWindow64) reset() {
+	w.root, w.head, w.n = -1, 0, 0
+	w.seed = sortedWindowSeed
+}
@@ -0,0 +1,102 @@
+package technical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtremeWindow(t *testing.T) {
+	series := readMockSeries64("mock/test_series.txt")
+
+	for _, size := range []int{1, 3, 26} {
+		hi, lo := newMaxWindow64(size), newMinWindow64(size)
+		assert.Equal(t, size, hi.size())
+
+		for i, v := range series {
+			hi.push(v)
+			lo.push(v)
+			assert.Equal(t, i+1 >= size, hi.full())
+
+			// scan of the window
+			m, n := math.Inf(-1), math.Inf(1)
+			for _, w := range series[maxInt(0, i-size+1) : i+1] {
+				m, n = math.Max(m, w), math.Min(n, w)
+			}
+
+			assert.Equal(t, m, hi.value())
+			assert.Equal(t, n, lo.value())
+		}
+
+		last := hi.value()
+		c := hi.clone()
+		hi.reset()
+		assert.Equal(t, 0.0, hi.value())
+		assert.False(t, hi.full())
+		assert.Equal(t, last, c.value())
+	}
+}
+
+func TestExtremeWindowAge(t *testing.T) {
+	w := newMaxWindow64(3)
+	assert.Equal(t, 0, w.age())
+
+	// equal values keep the most recent
+	for i, expected := range []int{0, 1, 0, 0, 1, 2} {
+		w.push([]float64{5, 4, 5, 6, 3, 2}[i])
+		assert.Equal(t, expected, w.age())
+	}
+}
+
+func TestSortedWindow(t *testing.T) {
+	series := readMockSeries64("mock/test_series.txt")
+
+	// repeated values exercise the ordering of equal values by slot
+	for i := range series {
+		if i%7 == 0 {
+			series[i] = series[i/2]
+		}
+	}
+
+	for _, size := range []int{1, 3, 20} {
+		w := newSortedWindow64(size)
+		assert.Equal(t, size, w.size())
+
+		for i, v := range series {
+			w.push(v)
+			assert.Equal(t, i+1 >= size, w.full())
+
+			// scan of the window
+			values := series[maxInt(0, i-size+1) : i+1]
+			mean, dev := 0.0, 0.0
+			for _, x := range values {
+				mean += x / float64(len(values))
+			}
+
+			for _, x := range values {
+				dev += math.Abs(x - mean)
+			}
+
+			assert.InDelta(t, mean, w.mean(), 1e-9)
+			assert.InDelta(t, dev, w.absDev(mean), 1e-7)
+			assert.Equal(t, len(values), w.count(w.root))
+		}
+
+		last := w.mean()
+		c := w.clone()
+		w.reset()
+		assert.Equal(t, 0.0, w.mean())
+		assert.False(t, w.full())
+		assert.Equal(t, last, c.mean())
+	}
+
+	// a NaN value is in the deviations until it leaves the window
+	w := newSortedWindow64(2)
+	w.push(1)
+	w.push(math.NaN())
+	assert.True(t, math.IsNaN(w.absDev(w.mean())))
+	w.push(2)
+	w.push(4)
+	assert.Equal(t, 2.0, w.absDev(w.mean()))
+}
@@ -0,0 +1,109 @@
+package technical
+
+import (
+	"math"
+)
+
+// ZScoreSeries64 computes the rolling z-score of each value of a series over a lookback lb
+// The z-score is the number of population standard deviations the value is from the mean of the last lb values,
+// including the value itself. A period with no deviation has a z-score of 0.0.
+// Values with fewer than lb values are 0.0. Returns nil for an empty series or lb <= 0.
+func ZScoreSeries64(series []float64, lb int) []float64 {
+	if len(series) == 0 || lb <= 0 {
+		return nil
+	}
+
+	zs := make([]float64, len(series))
+	win := newWindow64(lb)
+
+	for i, v := range series {
+		win.push(v)
+		if win.full() {
+			zs[i] = win.zScore(v)
+		}
+	}
+
+	return zs
+}
+
+// ZScoreSeries32 is 32 bit version of ZScoreSeries64
+func ZScoreSeries32(series []float32, lb int) []float32 {
+	if len(series) == 0 || lb <= 0 {
+		return nil
+	}
+
+	zs := make([]float32, len(series))
+	win := newWindow64(lb)
+
+	for i, v := range series {
+		win.push(float64(v))
+		if win.full() {
+			zs[i] = float32(win.zScore(float64(v)))
+		}
+	}
+
+	return zs
+}
+
+// zScore returns the z-score of v against the window values
+func (w *window64) zScore(v float64) float64 {
+	sd := w.stdDev()
+	if sd == 0.0 || math.IsNaN(sd) {
+		return 0.0
+	}
+
+	return (v - w.mean) / sd
+}
+
+// ZScoreStream64 computes the rolling z-score over a stream of values
+// Equivalent to ZScoreSeries64 computed one value at a time.
+type ZScoreStream64 struct {
+	win  window64
+	last float64
+}
+
+// NewZScoreStream64 creates a ZScoreStream64 with lookback lb
+// Returns ErrInvalidLookback if lb <= 0
+func NewZScoreStream64(lb int) (*ZScoreStream64, error) {
+	if lb <= 0 {
+		return nil, ErrInvalidLookback
+	}
+
+	return &ZScoreStream64{win: newWindow64(lb)}, nil
+}
+
+// Update adds the next value of the stream
+func (s *ZScoreStream64) Update(v float64) {
+	s.win.push(v)
+	s.last = v
+}
+
+// Value returns the z-score of the last value, 0.0 until the stream is ready
+func (s *ZScoreStream64) Value() float64 {
+	if !s.Ready() {
+		return 0.0
+	}
+
+	return s.win.zScore(s.last)
+}
+
+// Ready reports whether a full lookback of values has been seen
+func (s *ZScoreStream64) Ready() bool {
+	return s.win.full()
+}
+
+// WarmupPeriod returns the number of values before the stream is ready
+func (s *ZScoreStream64) WarmupPeriod() int {
+	return len(s.win.buf)
+}
+
+// Reset clears the stream
+func (s *ZScoreStream64) Reset() {
+	s.win.reset()
+	s.last = 0.0
+}
+
+// Clone returns an independent copy of the stream
+func (s *ZScoreStream64) Clone() *ZScoreStream64 {
+	return &ZScoreStream64{win: s.win.clone(), last: s.last}
+}
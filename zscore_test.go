@@ -0,0 +1,47 @@
+package technical
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZScoreSeries64(t *testing.T) {
+	// mean 2, population standard deviation sqrt(2/3)
+	zs := ZScoreSeries64([]float64{1, 2, 3, 3, 3}, 3)
+	assert.Equal(t, 0.0, zs[1])
+	assert.InDelta(t, 1.0/0.816496580927726, zs[2], 1e-12)
+	assert.Equal(t, 0.0, zs[4]) // no deviation
+	assert.Nil(t, ZScoreSeries64(nil, 3))
+	assert.Nil(t, ZScoreSeries64([]float64{1}, 0))
+
+	testseries := readMockSeries64("./mock/test_series.txt")
+	zs = ZScoreSeries64(testseries, 1200)
+	period := testseries[len(testseries)-1200:]
+	expected := (testseries[len(testseries)-1] - SimpleAvg64(period)) / StdDev64(period)
+	assert.InDelta(t, expected, zs[len(zs)-1], 1e-9)
+}
+
+func TestZScoreSeries32(t *testing.T) {
+	zs := ZScoreSeries32([]float32{1, 2, 3, 3, 3}, 3)
+	assert.InDelta(t, 1.0/0.816496580927726, zs[2], 1e-6)
+	assert.Nil(t, ZScoreSeries32(nil, 3))
+}
+
+func TestZScoreStream64(t *testing.T) {
+	_, err := NewZScoreStream64(0)
+	assert.Equal(t, ErrInvalidLookback, err)
+
+	testseries := readMockSeries64("./mock/test_series.txt")
+	s, err := NewZScoreStream64(1200)
+	assert.NoError(t, err)
+	assert.Equal(t, 1200, s.WarmupPeriod())
+	assert.Equal(t, ZScoreSeries64(testseries, 1200), UpdateSeries(s, testseries))
+
+	c := s.Clone()
+	c.Update(100)
+	assert.NotEqual(t, s.Value(), c.Value())
+
+	s.Reset()
+	assert.False(t, s.Ready())
+}